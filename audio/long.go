@@ -0,0 +1,46 @@
+package audio
+
+import (
+	"io"
+	"time"
+)
+
+// LongTranscriptionRequest contains parameters of a transcription of long audio, which is split
+// into chunks at pauses, transcribed concurrently and merged with corrected timestamps.
+type LongTranscriptionRequest struct {
+	// required
+	Audio io.ReaderAt // WAV file, or raw samples if PCM is set
+	Size  int64       // size of Audio in bytes
+
+	// optional
+	// PCM is the format of raw samples without a WAV header.
+	PCM         *PCMFormat
+	Model       string // default is models.Whisper1, other models don't return segments
+	Language    string
+	Prompt      string
+	Temperature float64
+
+	// MaxChunkDuration is the maximum duration of a chunk, default is 10 minutes.
+	// Chunks are also limited by MaxFileSize.
+	MaxChunkDuration time.Duration
+	// MinSilence and SilenceThreshold configure detection of pauses, see SplitOptions.
+	MinSilence       time.Duration
+	SilenceThreshold float64
+
+	// Concurrency is the number of chunks transcribed at once, default is 4.
+	// Audio is divided into this many consecutive runs of chunks, chunks of a run are transcribed
+	// one after another, so that the tail of a transcript is the prompt of the next chunk.
+	// The first chunk of each run gets only Prompt, as its predecessor is transcribed at the same
+	// time, so Concurrency-1 chunk boundaries lose context. Set 1 to carry the tail across all chunks.
+	Concurrency int
+	// ChunkAttempts is the number of attempts to transcribe each chunk, default is taken from
+	// HTTPClient.RequestAttempts. Only rate limits, server and network errors are retried,
+	// after HTTPClient.RetryInterval doubled with each attempt or the delay asked by the server.
+	ChunkAttempts int
+	// PromptTail is the number of last characters of a chunk transcript used as the prompt
+	// of the next chunk, after Prompt, default is 200. Negative disables it.
+	PromptTail int
+
+	// OnChunk is called after each transcribed chunk with numbers of done and all chunks.
+	OnChunk func(done, total int)
+}
@@ -0,0 +1,80 @@
+// Package audio provides a wrapper for the OpenAI Audio API.
+package audio
+
+import (
+	"context"
+	"io"
+)
+
+// Voices of text-to-speech models.
+const (
+	VoiceAlloy   = "alloy"
+	VoiceAsh     = "ash"
+	VoiceBallad  = "ballad"
+	VoiceCoral   = "coral"
+	VoiceEcho    = "echo"
+	VoiceFable   = "fable"
+	VoiceNova    = "nova"
+	VoiceOnyx    = "onyx"
+	VoiceSage    = "sage"
+	VoiceShimmer = "shimmer"
+	VoiceVerse   = "verse"
+	VoiceMarin   = "marin"
+	VoiceCedar   = "cedar"
+)
+
+// Formats of generated speech.
+const (
+	SpeechFormatMP3  = "mp3"
+	SpeechFormatOpus = "opus"
+	SpeechFormatAAC  = "aac"
+	SpeechFormatFLAC = "flac"
+	SpeechFormatWAV  = "wav"
+	SpeechFormatPCM  = "pcm" // raw 24kHz 16-bit signed little-endian mono samples
+)
+
+// Service is the service layer for OpenAI Audio API.
+type Service interface {
+	// Speech generates audio from text. The audio is streamed as it's generated,
+	// the caller must close Speech.Audio. ctx controls cancellation of the whole stream.
+	Speech(ctx context.Context, req *SpeechRequest) (*Speech, error)
+
+	// Transcribe transcribes audio into the input language.
+	Transcribe(ctx context.Context, req *TranscriptionRequest) (*Transcription, error)
+
+	// TranscribeStream transcribes audio and streams the text as it's recognized.
+	// Not supported by whisper-1. ctx controls cancellation of the whole stream.
+	TranscribeStream(ctx context.Context, req *TranscriptionRequest) (*TranscriptionStream, error)
+
+	// Translate transcribes audio into English.
+	Translate(ctx context.Context, req *TranslationRequest) (*Transcription, error)
+
+	// TranscribeLong transcribes long WAV or PCM audio in chunks split at pauses.
+	// Segments of chunks are merged with global timestamps, use Transcription.SRT or VTT
+	// to get subtitles.
+	TranscribeLong(ctx context.Context, req *LongTranscriptionRequest) (*Transcription, error)
+}
+
+// SpeechRequest contains parameters of speech generation.
+type SpeechRequest struct {
+	// required
+	Input string // text to speak, up to LimitCharacters of the model
+	Voice string // one of Voice* constants
+
+	// optional
+	Model string // default is models.DefaultTTS
+	// Instructions control the voice, like tone or accent, not supported by tts-1 models.
+	Instructions   string
+	ResponseFormat string  // one of SpeechFormat* constants, default is mp3
+	Speed          float64 // from 0.25 to 4.0, default is 1.0
+}
+
+// Speech is generated audio.
+type Speech struct {
+	// Audio is the audio stream, it must be closed.
+	Audio       io.ReadCloser
+	ContentType string
+	Model       string
+	// Cost is the cost in USD calculated from the number of characters, zero if pricing is unknown.
+	Cost float64
+}
@@ -0,0 +1,55 @@
+package audio
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// cue is a subtitle with timestamps in seconds.
+type cue struct {
+	start, end float64
+	text       string
+}
+
+// cues returns subtitles from segments, or from speaker segments prefixed by speakers.
+func (t *Transcription) cues() []cue {
+	var cues []cue
+	for _, s := range t.Segments {
+		cues = append(cues, cue{s.Start, s.End, strings.TrimSpace(s.Text)})
+	}
+	if len(cues) > 0 {
+		return cues
+	}
+	for _, s := range t.SpeakerSegments {
+		cues = append(cues, cue{s.Start, s.End, s.Speaker + ": " + strings.TrimSpace(s.Text)})
+	}
+	return cues
+}
+
+// SRT formats segments of the transcription as SubRip subtitles.
+// Returns an empty string if there are no segments.
+func (t *Transcription) SRT() string {
+	var b strings.Builder
+	for i, c := range t.cues() {
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, timestamp(c.start, ","), timestamp(c.end, ","), c.text)
+	}
+	return b.String()
+}
+
+// VTT formats segments of the transcription as WebVTT subtitles.
+func (t *Transcription) VTT() string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, c := range t.cues() {
+		fmt.Fprintf(&b, "%s --> %s\n%s\n\n", timestamp(c.start, "."), timestamp(c.end, "."), c.text)
+	}
+	return b.String()
+}
+
+// timestamp formats seconds as hh:mm:ss followed by the separator and milliseconds.
+func timestamp(seconds float64, separator string) string {
+	d := time.Duration(seconds*1000+0.5) * time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d",
+		int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60, separator, d.Milliseconds()%1000)
+}
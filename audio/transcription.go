@@ -0,0 +1,266 @@
+package audio
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	openai "github.com/unkn0wncode/openai/internal"
+)
+
+// MaxFileSize is the maximum size of audio files accepted for transcription and translation.
+const MaxFileSize = 25 << 20 // 25 MB
+
+// Response formats of transcriptions and translations.
+const (
+	ResponseFormatJSON         = "json"
+	ResponseFormatText         = "text"
+	ResponseFormatSRT          = "srt"
+	ResponseFormatVTT          = "vtt"
+	ResponseFormatVerboseJSON  = "verbose_json"  // with segments and words timestamps, whisper-1 only
+	ResponseFormatDiarizedJSON = "diarized_json" // with speaker segments, diarization models only
+)
+
+// Timestamp granularities of verbose_json transcriptions.
+const (
+	TimestampWord    = "word"
+	TimestampSegment = "segment"
+)
+
+// IncludeLogprobs adds log probabilities of tokens to json transcriptions.
+const IncludeLogprobs = "logprobs"
+
+// Types of transcription stream events.
+const (
+	EventTextDelta   = "transcript.text.delta"
+	EventTextSegment = "transcript.text.segment"
+	EventTextDone    = "transcript.text.done"
+)
+
+// TranscriptionRequest contains parameters of a transcription.
+type TranscriptionRequest struct {
+	// required
+	File     io.Reader // audio content, up to MaxFileSize
+	Filename string    // name with an extension of a supported format, like mp3, wav or webm
+
+	// optional
+	Model    string // default is models.DefaultTranscription
+	Language string // ISO-639-1 code of the input language, improves accuracy and latency
+	// Prompt guides the style or continues a previous segment, not supported by diarization models.
+	Prompt         string
+	ResponseFormat string // one of ResponseFormat* constants, default is json
+	Temperature    float64
+	// TimestampGranularities are Timestamp* constants, require verbose_json format.
+	TimestampGranularities []string
+	// Include lists additional data, like IncludeLogprobs, which requires json format.
+	Include []string
+	// ChunkingStrategy controls splitting of audio into chunks, required by diarization models
+	// for audio longer than 30 seconds.
+	ChunkingStrategy *ChunkingStrategy
+	// KnownSpeakerNames and KnownSpeakerReferences map speakers of diarization models to names,
+	// references are audio samples as data URLs. Up to 4 speakers.
+	KnownSpeakerNames      []string
+	KnownSpeakerReferences []string
+}
+
+// TranslationRequest contains parameters of a translation into English.
+type TranslationRequest struct {
+	// required
+	File     io.Reader // audio content, up to MaxFileSize
+	Filename string    // name with an extension of a supported format
+
+	// optional
+	Model          string // default is models.DefaultTranslation
+	Prompt         string // in English
+	ResponseFormat string // one of ResponseFormat* constants except diarized_json, default is json
+	Temperature    float64
+}
+
+// ChunkingStrategy controls splitting of audio into chunks.
+type ChunkingStrategy struct {
+	Type string `json:"type"` // "auto" or "server_vad"
+
+	// parameters of server_vad
+	PrefixPaddingMs   int     `json:"prefix_padding_ms,omitempty"`
+	SilenceDurationMs int     `json:"silence_duration_ms,omitempty"`
+	Threshold         float64 `json:"threshold,omitempty"`
+}
+
+// Transcription is a result of a transcription or a translation.
+// For text, srt and vtt formats only Text is set, with the output as is.
+type Transcription struct {
+	Task     string  `json:"task"`     // verbose_json and diarized_json only
+	Language string  `json:"language"` // verbose_json only
+	Duration float64 `json:"duration"` // in seconds, verbose_json and diarized_json only
+	Text     string  `json:"text"`
+
+	Segments        []Segment         `json:"-"` // verbose_json only
+	SpeakerSegments []DiarizedSegment `json:"-"` // diarized_json and streams of diarization models
+	Words           []Word            `json:"words"`
+	Logprobs        []Logprob         `json:"logprobs"`
+	Usage           *Usage            `json:"usage"`
+}
+
+// Segment is a segment of a verbose_json transcription.
+type Segment struct {
+	ID               int     `json:"id"`
+	Seek             int     `json:"seek"`
+	Start            float64 `json:"start"` // in seconds
+	End              float64 `json:"end"`
+	Text             string  `json:"text"`
+	Tokens           []int   `json:"tokens"`
+	Temperature      float64 `json:"temperature"`
+	AvgLogprob       float64 `json:"avg_logprob"`
+	CompressionRatio float64 `json:"compression_ratio"`
+	NoSpeechProb     float64 `json:"no_speech_prob"`
+}
+
+// DiarizedSegment is a segment of speech of one speaker.
+type DiarizedSegment struct {
+	ID      string  `json:"id"`
+	Start   float64 `json:"start"` // in seconds
+	End     float64 `json:"end"`
+	Text    string  `json:"text"`
+	Speaker string  `json:"speaker"` // known speaker name or a label like "A"
+}
+
+// Word is a word with timestamps.
+type Word struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"` // in seconds
+	End   float64 `json:"end"`
+}
+
+// Logprob is a log probability of a token.
+type Logprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+	Bytes   []int   `json:"bytes"`
+}
+
+// Usage is usage of a transcription, in tokens or in seconds of audio depending on the model.
+type Usage struct {
+	Type              string `json:"type"` // "tokens" or "duration"
+	InputTokens       int    `json:"input_tokens"`
+	OutputTokens      int    `json:"output_tokens"`
+	TotalTokens       int    `json:"total_tokens"`
+	InputTokenDetails struct {
+		TextTokens  int `json:"text_tokens"`
+		AudioTokens int `json:"audio_tokens"`
+	} `json:"input_token_details"`
+	Seconds float64 `json:"seconds"`
+}
+
+// TranscriptionEvent is an event of a transcription stream.
+type TranscriptionEvent struct {
+	Type     string    // one of Event* constants
+	Delta    string    // text added by EventTextDelta
+	Text     string    // whole text of EventTextDone
+	Logprobs []Logprob // when IncludeLogprobs is requested
+	// Segment is set for EventTextSegment of diarization models.
+	Segment *DiarizedSegment
+	// Usage is set for EventTextDone.
+	Usage *Usage
+}
+
+// TranscriptionStream iterates over events of a streamed transcription.
+type TranscriptionStream struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	items   <-chan any
+	current TranscriptionEvent
+	err     error
+	done    bool
+
+	text   strings.Builder
+	result Transcription
+}
+
+// NewTranscriptionStream creates a new TranscriptionStream from a channel delivering
+// TranscriptionEvent values or an error. Channel is expected to be closed after the last
+// event or after an error.
+// cancel, if not nil, is called to abort the underlying request when the stream ends or is closed.
+func NewTranscriptionStream(ctx context.Context, items <-chan any, cancel context.CancelFunc) *TranscriptionStream {
+	return &TranscriptionStream{ctx: ctx, cancel: cancel, items: items}
+}
+
+// Next advances the stream to the next event.
+// It returns true if there is an event available, false if the stream is done or an error occurred.
+// After Next returns false, use Err() to check if it was due to an error.
+func (s *TranscriptionStream) Next() bool {
+	for !s.done {
+		item, ok, err := openai.ReceiveItem(s.ctx, s.items)
+		if !ok {
+			s.err = err
+			s.finish()
+			return false
+		}
+		if e, isEvent := item.(TranscriptionEvent); isEvent {
+			s.current = e
+			s.add(e)
+			return true
+		}
+		// unexpected items are skipped
+	}
+
+	return false
+}
+
+// add accumulates the event into the result.
+func (s *TranscriptionStream) add(e TranscriptionEvent) {
+	switch e.Type {
+	case EventTextDelta:
+		s.text.WriteString(e.Delta)
+		s.result.Logprobs = append(s.result.Logprobs, e.Logprobs...)
+	case EventTextSegment:
+		if e.Segment != nil {
+			s.result.SpeakerSegments = append(s.result.SpeakerSegments, *e.Segment)
+		}
+	case EventTextDone:
+		s.result.Text = e.Text
+		s.result.Usage = e.Usage
+		if len(e.Logprobs) > 0 {
+			s.result.Logprobs = e.Logprobs
+		}
+	}
+}
+
+// Event returns the current event. Only valid after Next() returns true.
+func (s *TranscriptionStream) Event() TranscriptionEvent {
+	return s.current
+}
+
+// Err returns any error that occurred during iteration.
+func (s *TranscriptionStream) Err() error {
+	return s.err
+}
+
+// Close stops the iteration and aborts the underlying request.
+func (s *TranscriptionStream) Close() {
+	s.finish()
+}
+
+// finish marks the stream as done and releases the underlying request.
+func (s *TranscriptionStream) finish() {
+	s.done = true
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// Text returns the text received so far.
+func (s *TranscriptionStream) Text() string {
+	if s.result.Text != "" {
+		return s.result.Text
+	}
+	return s.text.String()
+}
+
+// Transcription reads the rest of the stream and returns the complete transcription.
+func (s *TranscriptionStream) Transcription() (*Transcription, error) {
+	for s.Next() {
+	}
+	result := s.result
+	result.Text = s.Text()
+	return &result, s.err
+}
@@ -0,0 +1,313 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// WAV format tags.
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE
+)
+
+// wavHeaderSize is the size of the header written by WAVHeader.
+const wavHeaderSize = 44
+
+// frameDuration is the duration of frames used to measure loudness.
+const frameDuration = 10 * time.Millisecond
+
+// PCMFormat describes uncompressed audio samples, interleaved by channels and little-endian.
+type PCMFormat struct {
+	SampleRate    int
+	Channels      int
+	BitsPerSample int  // 8, 16, 24 or 32
+	Float         bool // 32-bit IEEE float samples instead of integers
+}
+
+// Validate checks that samples of the format can be read.
+func (f PCMFormat) Validate() error {
+	switch {
+	case f.SampleRate <= 0:
+		return fmt.Errorf("invalid sample rate %d", f.SampleRate)
+	case f.Channels <= 0:
+		return fmt.Errorf("invalid number of channels %d", f.Channels)
+	case f.Float && f.BitsPerSample != 32:
+		return fmt.Errorf("float samples must have 32 bits, got %d", f.BitsPerSample)
+	case f.BitsPerSample != 8 && f.BitsPerSample != 16 && f.BitsPerSample != 24 && f.BitsPerSample != 32:
+		return fmt.Errorf("unsupported %d bits per sample", f.BitsPerSample)
+	}
+	return nil
+}
+
+// BlockSize returns the size of one sample of all channels in bytes.
+func (f PCMFormat) BlockSize() int {
+	return f.Channels * f.BitsPerSample / 8
+}
+
+// Duration returns the duration of given number of bytes of samples.
+func (f PCMFormat) Duration(size int64) time.Duration {
+	blocks := size / int64(f.BlockSize())
+	return time.Duration(blocks) * time.Second / time.Duration(f.SampleRate)
+}
+
+// ReadWAVHeader parses the header of a WAV file with PCM or float samples.
+// Returns the format and the position and size of samples in the file.
+func ReadWAVHeader(r io.ReaderAt, size int64) (format PCMFormat, dataOffset, dataSize int64, err error) {
+	var riff [12]byte
+	if _, err := r.ReadAt(riff[:], 0); err != nil {
+		return format, 0, 0, fmt.Errorf("failed to read RIFF header: %w", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return format, 0, 0, errors.New("not a WAV file")
+	}
+
+	var hasFormat bool
+	offset := int64(12)
+	for offset+8 <= size {
+		var header [8]byte
+		if _, err := r.ReadAt(header[:], offset); err != nil {
+			return format, 0, 0, fmt.Errorf("failed to read chunk header: %w", err)
+		}
+		id, chunkSize := string(header[0:4]), int64(binary.LittleEndian.Uint32(header[4:8]))
+		offset += 8
+
+		switch id {
+		case "fmt ":
+			if chunkSize < 16 {
+				return format, 0, 0, fmt.Errorf("fmt chunk is too short: %d bytes", chunkSize)
+			}
+			b := make([]byte, min(chunkSize, 40))
+			if _, err := r.ReadAt(b, offset); err != nil {
+				return format, 0, 0, fmt.Errorf("failed to read fmt chunk: %w", err)
+			}
+
+			tag := binary.LittleEndian.Uint16(b[0:2])
+			if tag == wavFormatExtensible && len(b) >= 26 {
+				// the format tag is in the first bytes of the subformat GUID
+				tag = binary.LittleEndian.Uint16(b[24:26])
+			}
+			format = PCMFormat{
+				Channels:      int(binary.LittleEndian.Uint16(b[2:4])),
+				SampleRate:    int(binary.LittleEndian.Uint32(b[4:8])),
+				BitsPerSample: int(binary.LittleEndian.Uint16(b[14:16])),
+			}
+			switch tag {
+			case wavFormatPCM:
+			case wavFormatFloat:
+				format.Float = true
+			default:
+				return format, 0, 0, fmt.Errorf("unsupported WAV format %#x, only PCM and float are supported", tag)
+			}
+			if err := format.Validate(); err != nil {
+				return format, 0, 0, err
+			}
+			hasFormat = true
+
+		case "data":
+			if !hasFormat {
+				return format, 0, 0, errors.New("data chunk comes before fmt chunk")
+			}
+			// streamed files may have unknown data size
+			if chunkSize == 0 || chunkSize == math.MaxUint32 || offset+chunkSize > size {
+				chunkSize = size - offset
+			}
+			chunkSize -= chunkSize % int64(format.BlockSize())
+			return format, offset, chunkSize, nil
+		}
+
+		// chunks are padded to even sizes
+		offset += chunkSize + chunkSize%2
+	}
+
+	return format, 0, 0, errors.New("no data chunk in WAV file")
+}
+
+// WAVHeader returns a WAV header for given format and size of samples.
+func WAVHeader(format PCMFormat, dataSize int64) []byte {
+	var b bytes.Buffer
+	b.Grow(wavHeaderSize)
+
+	tag := uint16(wavFormatPCM)
+	if format.Float {
+		tag = wavFormatFloat
+	}
+
+	b.WriteString("RIFF")
+	_ = binary.Write(&b, binary.LittleEndian, uint32(36+dataSize))
+	b.WriteString("WAVEfmt ")
+	for _, v := range []any{
+		uint32(16),
+		tag,
+		uint16(format.Channels),
+		uint32(format.SampleRate),
+		uint32(format.SampleRate * format.BlockSize()),
+		uint16(format.BlockSize()),
+		uint16(format.BitsPerSample),
+	} {
+		_ = binary.Write(&b, binary.LittleEndian, v)
+	}
+	b.WriteString("data")
+	_ = binary.Write(&b, binary.LittleEndian, uint32(dataSize))
+	return b.Bytes()
+}
+
+// SplitOptions configure SplitAtSilence.
+type SplitOptions struct {
+	// MaxDuration is the maximum duration of a chunk.
+	MaxDuration time.Duration
+	// MaxSize is the maximum size of samples of a chunk in bytes, zero means no limit.
+	MaxSize int64
+	// MinSilence is the minimum duration of a pause to split at, default is 300ms.
+	MinSilence time.Duration
+	// SilenceThreshold is the loudness below which audio is silent, as RMS relative to
+	// full scale from 0 to 1, default is 0.02.
+	SilenceThreshold float64
+}
+
+// Chunk is a part of audio samples.
+type Chunk struct {
+	Offset int64 // position of samples in the source
+	Size   int64 // size of samples in bytes
+	Start  time.Duration
+	End    time.Duration
+}
+
+// SplitAtSilence splits samples at r from offset, of given size, into chunks limited by
+// MaxDuration and MaxSize. A chunk is cut in the middle of the longest pause in the second half
+// of its allowed length, or at the quietest moment there if there are no pauses.
+func SplitAtSilence(r io.ReaderAt, offset, size int64, format PCMFormat, opts SplitOptions) ([]Chunk, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.MaxDuration <= 0 {
+		return nil, errors.New("max duration must be positive")
+	}
+	if opts.MinSilence <= 0 {
+		opts.MinSilence = 300 * time.Millisecond
+	}
+	if opts.SilenceThreshold <= 0 {
+		opts.SilenceThreshold = 0.02
+	}
+
+	// frames are about 10ms long
+	blocksPerFrame := max(int64(format.SampleRate)*int64(frameDuration)/int64(time.Second), 1)
+	frameSize := blocksPerFrame * int64(format.BlockSize())
+	framesIn := func(d time.Duration) int {
+		return int(int64(d) * int64(format.SampleRate) / int64(time.Second) / blocksPerFrame)
+	}
+
+	maxFrames := framesIn(opts.MaxDuration)
+	if opts.MaxSize > 0 {
+		maxFrames = min(maxFrames, int(opts.MaxSize/frameSize))
+	}
+	if maxFrames < 2 {
+		return nil, errors.New("chunks are limited to less than 2 frames of 10ms")
+	}
+	minSilence := max(framesIn(opts.MinSilence), 1)
+
+	loudness, err := frameLoudness(io.NewSectionReader(r, offset, size), format, frameSize)
+	if err != nil {
+		return nil, err
+	}
+
+	starts := []int{0}
+	for start := 0; len(loudness)-start > maxFrames; {
+		start = splitPoint(loudness, start+maxFrames/2, start+maxFrames, minSilence, opts.SilenceThreshold)
+		starts = append(starts, start)
+	}
+
+	chunks := make([]Chunk, len(starts))
+	for i, start := range starts {
+		chunkOffset, chunkEnd := int64(start)*frameSize, size
+		if i+1 < len(starts) {
+			chunkEnd = int64(starts[i+1]) * frameSize
+		}
+		chunks[i] = Chunk{
+			Offset: offset + chunkOffset,
+			Size:   chunkEnd - chunkOffset,
+			Start:  format.Duration(chunkOffset),
+			End:    format.Duration(chunkEnd),
+		}
+	}
+	return chunks, nil
+}
+
+// splitPoint returns the frame in [from, to) to split at: the middle of the longest silent run
+// of at least minSilence frames, or the quietest frame. Later frames win ties.
+func splitPoint(loudness []float64, from, to, minSilence int, threshold float64) int {
+	bestStart, bestLen := 0, 0
+	quietest := from
+	runStart := -1
+	for i := from; i <= to; i++ {
+		if i < to && loudness[i] < threshold {
+			if runStart < 0 {
+				runStart = i
+			}
+		} else if runStart >= 0 {
+			if i-runStart >= bestLen {
+				bestStart, bestLen = runStart, i-runStart
+			}
+			runStart = -1
+		}
+		if i < to && loudness[i] <= loudness[quietest] {
+			quietest = i
+		}
+	}
+
+	if bestLen >= minSilence {
+		return bestStart + bestLen/2
+	}
+	return quietest
+}
+
+// frameLoudness reads samples and returns RMS loudness of each frame, relative to full scale.
+// The last frame may be shorter.
+func frameLoudness(r io.Reader, format PCMFormat, frameSize int64) ([]float64, error) {
+	var loudness []float64
+	bytesPerSample := format.BitsPerSample / 8
+	buf := make([]byte, frameSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		n -= n % format.BlockSize()
+		if n > 0 {
+			var sum float64
+			for i := 0; i < n; i += bytesPerSample {
+				v := sample(buf[i:i+bytesPerSample], format)
+				sum += v * v
+			}
+			loudness = append(loudness, math.Sqrt(sum/float64(n/bytesPerSample)))
+		}
+
+		switch {
+		case err == nil:
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			return loudness, nil
+		default:
+			return nil, fmt.Errorf("failed to read samples: %w", err)
+		}
+	}
+}
+
+// sample decodes a little-endian sample to a value from -1 to 1.
+func sample(b []byte, format PCMFormat) float64 {
+	switch {
+	case format.Float:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	case format.BitsPerSample == 8:
+		return (float64(b[0]) - 128) / 128
+	case format.BitsPerSample == 16:
+		return float64(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+	case format.BitsPerSample == 24:
+		v := int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8
+		return float64(v) / (1 << 23)
+	default:
+		return float64(int32(binary.LittleEndian.Uint32(b))) / (1 << 31)
+	}
+}
@@ -0,0 +1,59 @@
+package batch
+
+import (
+	"context"
+	"time"
+
+	"github.com/unkn0wncode/openai/responses"
+)
+
+// Executor accumulates requests to the Responses API and sends them in batches at batch pricing.
+// It implements responses.Service, so it can replace the regular service in code that doesn't
+// need results right away. Send submits the request and blocks until its batch is finished.
+// Other methods of responses.Service, like Stream or Poll, use the regular API.
+//
+// Calls of tools are returned in outputs and are not executed, requests with Stream, Background,
+// LocalConversation or ContextWindow are rejected.
+type Executor interface {
+	responses.Service
+
+	// Submit adds the request to the pending batch and returns a future of its result.
+	// The batch is sent when it reaches ExecutorOptions.MaxRequests or after ExecutorOptions.MaxWait,
+	// or before the request if it doesn't fit into MaxInputSize of the batch.
+	Submit(req *responses.Request) Future
+
+	// Flush sends pending requests right away.
+	Flush()
+
+	// Close sends pending requests and waits until all batches are finished.
+	// Requests submitted after Close fail.
+	Close() error
+}
+
+// Future is the result of a request submitted to an Executor.
+type Future interface {
+	// Done returns a channel closed when the result is available.
+	Done() <-chan struct{}
+
+	// Wait blocks until the result is available or ctx is done.
+	Wait(ctx context.Context) (*responses.Response, error)
+
+	// Result returns the result of the request in the batch, nil until it's available
+	// or if the batch failed as a whole.
+	Result() *Result
+}
+
+// ExecutorOptions configures an Executor.
+type ExecutorOptions struct {
+	// MaxRequests is the number of pending requests that triggers sending a batch,
+	// default is 1000, maximum is MaxRequests.
+	MaxRequests int
+	// MaxWait is the maximum time a request waits for its batch to be sent, default is 5 minutes.
+	MaxWait time.Duration
+	// PollInterval is time to wait between polls of batches, default is 1 minute.
+	PollInterval time.Duration
+	// Metadata is attached to each created batch.
+	Metadata map[string]string
+	// OutputExpiresAfter sets expiration of output and error files.
+	OutputExpiresAfter time.Duration
+}
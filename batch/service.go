@@ -0,0 +1,251 @@
+// Package batch provides a wrapper for the OpenAI Batch API, which runs requests asynchronously
+// within 24 hours at half of the regular price.
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/unkn0wncode/openai/chat"
+	"github.com/unkn0wncode/openai/embedding"
+	"github.com/unkn0wncode/openai/responses"
+)
+
+// Endpoints supported in batches by this package.
+const (
+	EndpointResponses  = "/v1/responses"
+	EndpointChat       = "/v1/chat/completions"
+	EndpointEmbeddings = "/v1/embeddings"
+)
+
+const (
+	// CompletionWindow is the only completion window supported by the API.
+	CompletionWindow = "24h"
+	// PriceMultiplier is the share of the regular price charged for batch requests.
+	PriceMultiplier = 0.5
+	// MaxRequests is the maximum number of requests in one batch.
+	MaxRequests = 50000
+	// MaxInputSize is the maximum size of the input file of a batch in bytes.
+	MaxInputSize = 200 << 20
+)
+
+// Statuses of batches.
+const (
+	StatusValidating = "validating"
+	StatusFailed     = "failed"
+	StatusInProgress = "in_progress"
+	StatusFinalizing = "finalizing"
+	StatusCompleted  = "completed"
+	StatusExpired    = "expired"
+	StatusCancelling = "cancelling"
+	StatusCancelled  = "cancelled"
+)
+
+// Service is the service layer for OpenAI Batch API.
+type Service interface {
+	// NewInput creates an empty input file builder.
+	NewInput() Input
+
+	// Create creates a batch from an uploaded input file.
+	Create(req *CreateRequest) (*Batch, error)
+
+	// Retrieve returns a batch by ID.
+	Retrieve(id string) (*Batch, error)
+
+	// Cancel cancels a batch, it gets status "cancelling" until running requests finish.
+	Cancel(id string) (*Batch, error)
+
+	// List returns a page of batches.
+	List(opts *ListOptions) (*BatchList, error)
+
+	// Poll fetches the batch until it's finished: completed, failed, expired or cancelled.
+	// ctx controls cancellation, interval is time to wait between polls.
+	Poll(ctx context.Context, id string, interval time.Duration) (*Batch, error)
+
+	// Results downloads output and error files of a finished batch and decodes results.
+	Results(ctx context.Context, b *Batch) (*Results, error)
+
+	// Run uploads the input, creates a batch, polls it until it's finished and returns results.
+	// Results of expired and cancelled batches contain requests finished before.
+	Run(ctx context.Context, input Input, opts *RunOptions) (*Results, error)
+
+	// NewExecutor creates an executor that sends requests to the Responses API in batches.
+	// ctx controls running batches, requests fail when it's cancelled.
+	NewExecutor(ctx context.Context, opts *ExecutorOptions) Executor
+}
+
+// Input builds a JSONL input file of a batch. Requests are marshaled the same way as they're
+// sent by services, with default models and tools from the registry of the client.
+// All requests of an input must be for the same endpoint.
+type Input interface {
+	// AddResponses adds a request to the Responses API.
+	AddResponses(customID string, req *responses.Request) error
+
+	// AddChat adds a request to the Chat API.
+	AddChat(customID string, req chat.Request) error
+
+	// AddEmbedding adds a request to the Embeddings API.
+	AddEmbedding(customID string, req *EmbeddingRequest) error
+
+	// Endpoint returns the endpoint of added requests, empty if there are none.
+	Endpoint() string
+
+	// Len returns the number of added requests.
+	Len() int
+
+	// Size returns the size of the JSONL content in bytes, up to MaxInputSize.
+	Size() int
+
+	// WriteTo writes the JSONL content of the input file.
+	WriteTo(w io.Writer) (int64, error)
+}
+
+// EmbeddingRequest is a request to the Embeddings API in a batch.
+type EmbeddingRequest struct {
+	Inputs     []string
+	Model      string // default is models.DefaultEmbedding
+	Dimensions int    // default is 256, like in the Embeddings service
+	User       string
+}
+
+// CreateRequest contains parameters of a new batch.
+type CreateRequest struct {
+	// required
+	InputFileID string
+	Endpoint    string // one of Endpoint* constants
+
+	// optional
+	Metadata map[string]string
+	// OutputExpiresAfter sets expiration of output and error files after their creation,
+	// from 1 hour to 30 days, default is 30 days.
+	OutputExpiresAfter time.Duration
+}
+
+// RunOptions configures Service.Run.
+type RunOptions struct {
+	Metadata           map[string]string
+	OutputExpiresAfter time.Duration
+	// PollInterval is time to wait between polls, default is 1 minute.
+	PollInterval time.Duration
+}
+
+// Batch is a batch object returned by the API.
+type Batch struct {
+	ID               string            `json:"id"`
+	Object           string            `json:"object"` // always "batch"
+	Endpoint         string            `json:"endpoint"`
+	Errors           *Errors           `json:"errors"` // errors of input validation
+	InputFileID      string            `json:"input_file_id"`
+	CompletionWindow string            `json:"completion_window"`
+	Status           string            `json:"status"` // one of Status* constants
+	OutputFileID     string            `json:"output_file_id"`
+	ErrorFileID      string            `json:"error_file_id"`
+	CreatedAt        int64             `json:"created_at"` // Unix timestamps
+	InProgressAt     int64             `json:"in_progress_at"`
+	ExpiresAt        int64             `json:"expires_at"`
+	FinalizingAt     int64             `json:"finalizing_at"`
+	CompletedAt      int64             `json:"completed_at"`
+	FailedAt         int64             `json:"failed_at"`
+	ExpiredAt        int64             `json:"expired_at"`
+	CancellingAt     int64             `json:"cancelling_at"`
+	CancelledAt      int64             `json:"cancelled_at"`
+	RequestCounts    RequestCounts     `json:"request_counts"`
+	Metadata         map[string]string `json:"metadata"`
+}
+
+// Finished reports whether the batch won't change anymore.
+func (b *Batch) Finished() bool {
+	switch b.Status {
+	case StatusCompleted, StatusFailed, StatusExpired, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Errors contains errors of batch input validation.
+type Errors struct {
+	Object string       `json:"object"` // always "list"
+	Data   []InputError `json:"data"`
+}
+
+// InputError is an error of a line of batch input.
+type InputError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Param   string `json:"param"`
+	Line    int    `json:"line"`
+}
+
+// RequestCounts contains numbers of requests in a batch by status.
+type RequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// ListOptions configures pagination when listing batches.
+type ListOptions struct {
+	// Limit is the number of batches per page, from 1 to 100, default is 20.
+	Limit int
+	// After is a batch ID to list batches after, use BatchList.LastID for the next page.
+	After string
+}
+
+// BatchList is a page of batches.
+type BatchList struct {
+	Object  string  `json:"object"` // always "list"
+	Data    []Batch `json:"data"`
+	FirstID string  `json:"first_id"`
+	LastID  string  `json:"last_id"`
+	HasMore bool    `json:"has_more"`
+}
+
+// Results contains decoded results of a batch.
+type Results struct {
+	Batch *Batch
+	// Items are results in order of output and error files.
+	Items []*Result
+	// Cost is the total cost of successful requests in USD at batch pricing.
+	Cost float64
+}
+
+// Get returns the result of the request with given custom ID.
+func (r *Results) Get(customID string) (*Result, bool) {
+	for _, item := range r.Items {
+		if item.CustomID == customID {
+			return item, true
+		}
+	}
+	return nil, false
+}
+
+// Result is the result of one request of a batch.
+// One of Response, Completion or Embeddings is set for successful requests, by endpoint.
+type Result struct {
+	CustomID   string
+	StatusCode int // HTTP status of the request, zero if it wasn't executed
+	RequestID  string
+	Body       json.RawMessage // raw response body
+	// Error is nil for successful requests. It's also set with code "invalid_body" when the body
+	// of a successful request can't be decoded, or "invalid_line" when the line of results can't,
+	// then Body holds the line.
+	Error *Error
+	Cost  float64 // cost in USD at batch pricing, zero if pricing is unknown
+
+	Response   *responses.Response
+	Completion *chat.Completion
+	Embeddings []embedding.Vector
+}
+
+// Error is an error of a request in a batch.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
@@ -3,6 +3,7 @@ package chat
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"strings"
 
@@ -15,6 +16,10 @@ type Service interface {
 	// Send sends a request to the Chat API.
 	Send(req Request) (string, error)
 
+	// Stream sends a request with parameter "stream":true and returns a stream of chunks.
+	// Tool calls are not executed automatically, they can be read from the accumulated message.
+	Stream(ctx context.Context, req Request) (*Stream, error)
+
 	// NewRequest creates a new empty request.
 	NewRequest() *Request
 
@@ -51,8 +56,12 @@ type Request struct {
 
 	// If set, partial message deltas will be sent, like in ChatGPT.
 	// Tokens will be sent as data-only server-sent events as they become available, with the stream terminated by a data: [DONE] message.
+	// Set automatically by Service.Stream, requests with it set can't be used with Service.Send.
 	Stream bool `json:"stream,omitempty"` // default false
 
+	// Options for streaming response. Only set this when Stream is true.
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+
 	// Up to 4 sequences where the API will stop generating further tokens.
 	Stop []string `json:"stop,omitempty"` // default []
 
@@ -106,6 +115,10 @@ type Request struct {
 	// By default (false), function calls will be executed automatically and request will be repeated with the results.
 	// If set to true, function calls will be returned in the response as encoded JSON and must be executed manually.
 	ReturnFunctionCalls bool `json:"-"` // default false
+
+	// If set, parameters unsupported by the model are removed before sending instead of
+	// failing validation, see StripUnsupported.
+	AutoStrip bool `json:"-"` // default false
 }
 
 // ResponseFormatStr represents a format that the model must output.
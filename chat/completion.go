@@ -0,0 +1,26 @@
+package chat
+
+// Completion is a chat completion object returned by the API, e.g. in batch results.
+type Completion struct {
+	ID      string   `json:"id"`
+	Object  string   `json:"object"`  // always "chat.completion"
+	Created int64    `json:"created"` // Unix timestamp
+	Model   string   `json:"model"`
+	Usage   Usage    `json:"usage"`
+	Choices []Choice `json:"choices"`
+}
+
+// Choice is one of the alternative messages of a completion.
+type Choice struct {
+	Index        int     `json:"index"`
+	Message      Message `json:"message"`
+	FinishReason string  `json:"finish_reason"` // stop/length/tool_calls/content_filter
+}
+
+// Message returns the message of the first choice, or an empty message if there are no choices.
+func (c *Completion) Message() Message {
+	if len(c.Choices) == 0 {
+		return Message{}
+	}
+	return c.Choices[0].Message
+}
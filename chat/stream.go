@@ -0,0 +1,268 @@
+// Package chat / stream.go contains types for streaming responses from the Chat API.
+package chat
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/roles"
+)
+
+// StreamOptions is a set of options for streaming responses.
+type StreamOptions struct {
+	// If set, an additional chunk will be streamed before the end of the stream.
+	// Its Usage field shows the token usage for the entire request,
+	// and its Choices field will always be an empty slice.
+	IncludeUsage bool `json:"include_usage,omitempty"`
+}
+
+// Chunk is a single streamed chunk of a chat completion.
+type Chunk struct {
+	ID                string        `json:"id"`
+	Object            string        `json:"object"`  // "chat.completion.chunk"
+	Created           int           `json:"created"` // Unix timestamp
+	Model             string        `json:"model"`
+	SystemFingerprint string        `json:"system_fingerprint,omitempty"`
+	ServiceTier       string        `json:"service_tier,omitempty"`
+	Choices           []ChunkChoice `json:"choices"`
+
+	// Usage is only present in the last chunk when StreamOptions.IncludeUsage is set.
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// ChunkChoice is a delta for one of the generated choices.
+type ChunkChoice struct {
+	Index        int    `json:"index"`
+	Delta        Delta  `json:"delta"`
+	FinishReason string `json:"finish_reason"` // empty until the last chunk of the choice: stop/length/content_filter/tool_calls
+}
+
+// Delta contains the parts of a message generated since the previous chunk.
+type Delta struct {
+	Role      string          `json:"role,omitempty"` // only present in the first chunk
+	Content   string          `json:"content,omitempty"`
+	Refusal   string          `json:"refusal,omitempty"`
+	ToolCalls []ToolCallDelta `json:"tool_calls,omitempty"`
+}
+
+// ToolCallDelta is a fragment of a tool call.
+// The first fragment of each call has ID, Type and function Name,
+// subsequent fragments with the same Index only add to Arguments.
+type ToolCallDelta struct {
+	Index    int    `json:"index"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"` // "function"
+	Function struct {
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments,omitempty"`
+	} `json:"function"`
+}
+
+// Usage contains token usage statistics for a request.
+type Usage struct {
+	PromptTokens        int `json:"prompt_tokens"`
+	CompletionTokens    int `json:"completion_tokens"`
+	TotalTokens         int `json:"total_tokens"`
+	PromptTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
+	CompletionTokensDetails struct {
+		ReasoningTokens int `json:"reasoning_tokens"`
+	} `json:"completion_tokens_details"`
+}
+
+// Accumulator reconstructs complete messages from streamed chunks.
+// The zero value is ready for use.
+type Accumulator struct {
+	ID    string
+	Model string
+	Usage *Usage
+
+	choices map[int]*accumulatedChoice
+}
+
+// accumulatedChoice holds the state of one choice being reconstructed.
+type accumulatedChoice struct {
+	role         string
+	content      strings.Builder
+	refusal      strings.Builder
+	toolCalls    map[int]*openai.ToolCallData
+	finishReason string
+}
+
+// Add adds a chunk to the accumulated state.
+func (a *Accumulator) Add(chunk Chunk) {
+	if a.ID == "" {
+		a.ID = chunk.ID
+	}
+	if a.Model == "" {
+		a.Model = chunk.Model
+	}
+	if chunk.Usage != nil {
+		a.Usage = chunk.Usage
+	}
+	if a.choices == nil {
+		a.choices = map[int]*accumulatedChoice{}
+	}
+
+	for _, c := range chunk.Choices {
+		choice, ok := a.choices[c.Index]
+		if !ok {
+			choice = &accumulatedChoice{toolCalls: map[int]*openai.ToolCallData{}}
+			a.choices[c.Index] = choice
+		}
+
+		if c.Delta.Role != "" {
+			choice.role = c.Delta.Role
+		}
+		choice.content.WriteString(c.Delta.Content)
+		choice.refusal.WriteString(c.Delta.Refusal)
+		if c.FinishReason != "" {
+			choice.finishReason = c.FinishReason
+		}
+
+		for _, tc := range c.Delta.ToolCalls {
+			call, ok := choice.toolCalls[tc.Index]
+			if !ok {
+				call = &openai.ToolCallData{Function: &openai.FunctionCallData{}}
+				choice.toolCalls[tc.Index] = call
+			}
+			if tc.ID != "" {
+				call.ID = tc.ID
+			}
+			if tc.Type != "" {
+				call.Type = tc.Type
+			}
+			if tc.Function.Name != "" {
+				call.Function.Name += tc.Function.Name
+			}
+			call.Function.Arguments += tc.Function.Arguments
+		}
+	}
+}
+
+// Message returns the message reconstructed so far for the first choice.
+func (a *Accumulator) Message() Message {
+	return a.Choice(0)
+}
+
+// Choice returns the message reconstructed so far for the choice with given index.
+func (a *Accumulator) Choice(index int) Message {
+	msg := Message{Role: roles.Assistant}
+
+	choice, ok := a.choices[index]
+	if !ok {
+		return msg
+	}
+
+	if choice.role != "" {
+		msg.Role = choice.role
+	}
+	msg.Content = choice.content.String()
+	msg.Refusal = choice.refusal.String()
+
+	indexes := make([]int, 0, len(choice.toolCalls))
+	for i := range choice.toolCalls {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		call := *choice.toolCalls[i]
+		if call.Type == "" {
+			call.Type = "function"
+		}
+		fn := *call.Function
+		call.Function = &fn
+		msg.ToolCalls = append(msg.ToolCalls, call)
+	}
+
+	return msg
+}
+
+// FinishReason returns the finish reason of the first choice, empty if not finished yet.
+func (a *Accumulator) FinishReason() string {
+	if choice, ok := a.choices[0]; ok {
+		return choice.finishReason
+	}
+	return ""
+}
+
+// Stream iterates over chunks of a streamed chat completion.
+// Received chunks are automatically added to the stream's Accumulator.
+type Stream struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	items   <-chan any
+	current Chunk
+	err     error
+	done    bool
+
+	acc Accumulator
+}
+
+// NewStream creates a new Stream from a channel delivering Chunk values or an error.
+// Channel is expected to be closed after the last chunk or after an error.
+// cancel, if not nil, is called to abort the underlying request when the stream ends or is closed.
+func NewStream(ctx context.Context, items <-chan any, cancel context.CancelFunc) *Stream {
+	return &Stream{ctx: ctx, cancel: cancel, items: items}
+}
+
+// Next advances the stream to the next chunk.
+// It returns true if there is a chunk available, false if the stream is done or an error occurred.
+// After Next returns false, use Err() to check if it was due to an error.
+func (s *Stream) Next() bool {
+	for !s.done {
+		item, ok, err := openai.ReceiveItem(s.ctx, s.items)
+		if !ok {
+			s.err = err
+			s.finish()
+			return false
+		}
+		if chunk, isChunk := item.(Chunk); isChunk {
+			s.current = chunk
+			s.acc.Add(chunk)
+			return true
+		}
+		// unexpected items are skipped
+	}
+
+	return false
+}
+
+// Chunk returns the current chunk. Only valid after Next() returns true.
+func (s *Stream) Chunk() Chunk {
+	return s.current
+}
+
+// Err returns any error that occurred during iteration.
+func (s *Stream) Err() error {
+	return s.err
+}
+
+// Close stops the iteration and aborts the underlying request.
+func (s *Stream) Close() {
+	s.finish()
+}
+
+// finish marks the stream as done and releases the underlying request.
+func (s *Stream) finish() {
+	s.done = true
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// Accumulator returns the accumulator holding the state reconstructed from all chunks read so far.
+func (s *Stream) Accumulator() *Accumulator {
+	return &s.acc
+}
+
+// Message reads the rest of the stream and returns the complete assistant message
+// of the first choice.
+func (s *Stream) Message() (Message, error) {
+	for s.Next() {
+	}
+	return s.acc.Message(), s.err
+}
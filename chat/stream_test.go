@@ -0,0 +1,117 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// chunkFromJSON decodes a chunk payload as it's received from the API.
+func chunkFromJSON(t *testing.T, data string) Chunk {
+	t.Helper()
+	var c Chunk
+	require.NoError(t, json.Unmarshal([]byte(data), &c))
+	return c
+}
+
+func TestAccumulator(t *testing.T) {
+	t.Parallel()
+
+	chunks := []string{
+		`{"id":"c1","model":"gpt-x","choices":[{"index":0,"delta":{"role":"assistant","content":""}}]}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{"content":"Hel"}}]}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{"content":"lo"}}]}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_b","type":"function","function":{"name":"second","arguments":""}}]}}]}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_a","type":"function","function":{"name":"first","arguments":"{\"a\":"}}]}}]}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"1}"}},{"index":1,"function":{"arguments":"{}"}}]}}]}`,
+		`{"id":"c1","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+		`{"id":"c1","choices":[],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`,
+	}
+
+	var acc Accumulator
+	for _, c := range chunks {
+		acc.Add(chunkFromJSON(t, c))
+	}
+
+	require.Equal(t, "c1", acc.ID)
+	require.Equal(t, "gpt-x", acc.Model)
+	require.Equal(t, "tool_calls", acc.FinishReason())
+	require.NotNil(t, acc.Usage)
+	require.Equal(t, 15, acc.Usage.TotalTokens)
+
+	msg := acc.Message()
+	require.Equal(t, "assistant", msg.Role)
+	require.Equal(t, "Hello", msg.Content)
+	require.Len(t, msg.ToolCalls, 2)
+	require.Equal(t, "call_a", msg.ToolCalls[0].ID)
+	require.Equal(t, "first", msg.ToolCalls[0].Function.Name)
+	require.Equal(t, `{"a":1}`, msg.ToolCalls[0].Function.Arguments)
+	require.Equal(t, "call_b", msg.ToolCalls[1].ID)
+	require.Equal(t, "{}", msg.ToolCalls[1].Function.Arguments)
+
+	// returned message must not share state with the accumulator
+	msg.ToolCalls[0].Function.Arguments = "changed"
+	require.Equal(t, `{"a":1}`, acc.Message().ToolCalls[0].Function.Arguments)
+}
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Chunks", func(t *testing.T) {
+		t.Parallel()
+		items := make(chan any, 3)
+		items <- chunkFromJSON(t, `{"choices":[{"index":0,"delta":{"content":"a"}}]}`)
+		items <- chunkFromJSON(t, `{"choices":[{"index":0,"delta":{"content":"b"},"finish_reason":"stop"}]}`)
+		close(items)
+
+		s := NewStream(context.Background(), items, nil)
+		msg, err := s.Message()
+		require.NoError(t, err)
+		require.Equal(t, "ab", msg.Content)
+		require.False(t, s.Next())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+		items := make(chan any, 2)
+		items <- chunkFromJSON(t, `{"choices":[{"index":0,"delta":{"content":"a"}}]}`)
+		items <- errors.New("broken")
+		close(items)
+
+		s := NewStream(context.Background(), items, nil)
+		require.True(t, s.Next())
+		require.Equal(t, "a", s.Chunk().Choices[0].Delta.Content)
+		require.False(t, s.Next())
+		require.EqualError(t, s.Err(), "broken")
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		items := make(chan any)
+
+		s := NewStream(ctx, items, cancel)
+		s.Close()
+		require.ErrorIs(t, ctx.Err(), context.Canceled, "Close must abort the request")
+		require.False(t, s.Next())
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		t.Parallel()
+		// a producer stopped by the context closes the channel without delivering the error
+		for range 100 {
+			ctx, cancel := context.WithCancel(context.Background())
+			items := make(chan any)
+			cancel()
+			close(items)
+
+			s := NewStream(ctx, items, nil)
+			require.False(t, s.Next())
+			require.ErrorIs(t, s.Err(), context.Canceled)
+		}
+	})
+}
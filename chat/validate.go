@@ -0,0 +1,108 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/unkn0wncode/openai/models"
+)
+
+// Validate checks the request against capabilities and limits of its model, see models.GetCapabilities.
+// Requests to unknown models are only checked against limits from models.Data.
+// Returns joined errors wrapping models.ErrUnsupported.
+func (r *Request) Validate() error {
+	model := r.Model
+	if model == "" {
+		model = models.Default
+	}
+
+	var errs []error
+	unsupported := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: model '%s': %s", models.ErrUnsupported, model, fmt.Sprintf(format, args...)))
+	}
+
+	if limit := models.Data[model].LimitOutput; limit > 0 && max(r.MaxCompletionTokens, r.MaxTokens) > limit {
+		unsupported("max tokens %d exceed the limit of %d", max(r.MaxCompletionTokens, r.MaxTokens), limit)
+	}
+
+	caps, ok := models.GetCapabilities(model)
+	if !ok {
+		return errors.Join(errs...)
+	}
+
+	if r.usesSampling() && !caps.Sampling {
+		unsupported("Temperature, TopP, penalties and LogitBias are not supported")
+	}
+	if r.MaxTokens != 0 && caps.Reasoning {
+		unsupported("MaxTokens is not supported, use MaxCompletionTokens")
+	}
+	if r.usesSchema() && !caps.StructuredOutputs {
+		unsupported("structured outputs are not supported")
+	}
+	if len(r.Functions) > 0 && !caps.FunctionCalling {
+		unsupported("functions are not supported")
+	}
+	if !caps.SupportsInput(models.ModalityImage) {
+		for _, msg := range r.Messages {
+			if len(msg.Images) > 0 {
+				unsupported("image inputs are not supported")
+				break
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// usesSampling reports whether any sampling parameter is set.
+func (r *Request) usesSampling() bool {
+	return r.Temperature != 0 || r.TopP != 0 || r.PresencePenalty != 0 || r.FrequencyPenalty != 0 || len(r.LogitBias) > 0
+}
+
+// usesSchema reports whether the response format is a JSON schema.
+func (r *Request) usesSchema() bool {
+	return r.ResponseFormat != "" && r.ResponseFormat != "text" && r.ResponseFormat != "json_object"
+}
+
+// StripUnsupported removes parameters that the model doesn't support: sampling parameters and
+// functions, replaces MaxTokens with MaxCompletionTokens for reasoning models, and lowers
+// max tokens to the limit of the model.
+// Content, like images or response format, is never changed.
+// Returns names of changed parameters.
+func (r *Request) StripUnsupported() []string {
+	model := r.Model
+	if model == "" {
+		model = models.Default
+	}
+
+	var stripped []string
+	caps, ok := models.GetCapabilities(model)
+	if ok {
+		if r.usesSampling() && !caps.Sampling {
+			r.Temperature, r.TopP, r.PresencePenalty, r.FrequencyPenalty, r.LogitBias = 0, 0, 0, 0, nil
+			stripped = append(stripped, "Temperature", "TopP", "PresencePenalty", "FrequencyPenalty", "LogitBias")
+		}
+		if r.MaxTokens != 0 && caps.Reasoning {
+			r.MaxCompletionTokens = max(r.MaxCompletionTokens, r.MaxTokens)
+			r.MaxTokens = 0
+			stripped = append(stripped, "MaxTokens")
+		}
+		if len(r.Functions) > 0 && !caps.FunctionCalling {
+			r.Functions = nil
+			stripped = append(stripped, "Functions")
+		}
+	}
+
+	if limit := models.Data[model].LimitOutput; limit > 0 {
+		if r.MaxCompletionTokens > limit {
+			r.MaxCompletionTokens = limit
+			stripped = append(stripped, "MaxCompletionTokens")
+		}
+		if r.MaxTokens > limit {
+			r.MaxTokens = limit
+			stripped = append(stripped, "MaxTokens")
+		}
+	}
+
+	return stripped
+}
@@ -0,0 +1,53 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/models"
+	"github.com/unkn0wncode/openai/roles"
+)
+
+func TestRequestValidate(t *testing.T) {
+	t.Parallel()
+
+	msgs := []Message{{Role: roles.User, Content: "hi"}}
+	imageMsgs := []Message{{Role: roles.User, Content: "hi", Images: []Image{{URL: "https://example.com/a.png"}}}}
+
+	for name, tc := range map[string]struct {
+		req   Request
+		valid bool
+	}{
+		"Default":             {Request{Messages: msgs}, true},
+		"TemperatureOnGPT4o":  {Request{Model: models.GPT4Omni, Messages: msgs, Temperature: 0.5}, true},
+		"PenaltyOnO3":         {Request{Model: models.GPTO3, Messages: msgs, PresencePenalty: 1}, false},
+		"MaxTokensOnO3":       {Request{Model: models.GPTO3, Messages: msgs, MaxTokens: 100}, false},
+		"MaxCompletionTokens": {Request{Model: models.GPT4Omni, Messages: msgs, MaxCompletionTokens: 1_000_000}, false},
+		"ImageOnGPT35":        {Request{Model: models.GPT35Turbo, Messages: imageMsgs}, false},
+		"SchemaOnGPT35":       {Request{Model: models.GPT35Turbo, Messages: msgs, ResponseFormat: `{"name":"x"}`}, false},
+		"JSONObjectOnGPT35":   {Request{Model: models.GPT35Turbo, Messages: msgs, ResponseFormat: "json_object"}, true},
+		"FunctionsOnO1Mini":   {Request{Model: models.GPTO1Mini, Messages: msgs, Functions: []string{"f"}}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.req.Validate()
+			if tc.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, models.ErrUnsupported)
+		})
+	}
+
+	t.Run("StripUnsupported", func(t *testing.T) {
+		t.Parallel()
+		req := Request{Model: models.GPTO3, Messages: msgs, Temperature: 0.5, MaxTokens: 100}
+		require.Equal(t,
+			[]string{"Temperature", "TopP", "PresencePenalty", "FrequencyPenalty", "LogitBias", "MaxTokens"},
+			req.StripUnsupported(),
+		)
+		require.Zero(t, req.MaxTokens)
+		require.Equal(t, 100, req.MaxCompletionTokens)
+		require.NoError(t, req.Validate())
+	})
+}
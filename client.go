@@ -3,29 +3,53 @@ package openai
 
 import (
 	"github.com/unkn0wncode/openai/assistants"
+	"github.com/unkn0wncode/openai/audio"
+	"github.com/unkn0wncode/openai/batch"
 	"github.com/unkn0wncode/openai/chat"
 	"github.com/unkn0wncode/openai/completion"
 	"github.com/unkn0wncode/openai/embedding"
+	"github.com/unkn0wncode/openai/files"
+	"github.com/unkn0wncode/openai/images"
 	openai "github.com/unkn0wncode/openai/internal"
 	"github.com/unkn0wncode/openai/internal/inassistants"
+	"github.com/unkn0wncode/openai/internal/inaudio"
+	"github.com/unkn0wncode/openai/internal/inbatch"
 	"github.com/unkn0wncode/openai/internal/inchat"
 	"github.com/unkn0wncode/openai/internal/incompletion"
 	"github.com/unkn0wncode/openai/internal/inembedding"
+	"github.com/unkn0wncode/openai/internal/infiles"
+	"github.com/unkn0wncode/openai/internal/inimages"
+	"github.com/unkn0wncode/openai/internal/inmodels"
 	"github.com/unkn0wncode/openai/internal/inmoderation"
+	"github.com/unkn0wncode/openai/internal/inrealtime"
 	"github.com/unkn0wncode/openai/internal/inresponses"
+	"github.com/unkn0wncode/openai/internal/inuploads"
+	"github.com/unkn0wncode/openai/internal/invectorstores"
+	"github.com/unkn0wncode/openai/models"
 	"github.com/unkn0wncode/openai/moderation"
+	"github.com/unkn0wncode/openai/realtime"
 	"github.com/unkn0wncode/openai/responses"
 	"github.com/unkn0wncode/openai/tools"
+	"github.com/unkn0wncode/openai/uploads"
+	"github.com/unkn0wncode/openai/vectorstores"
 )
 
 // Client provides access to OpenAI APIs.
 type Client struct {
-	Chat       chat.Service
-	Moderation moderation.Service
-	Completion completion.Service
-	Assistants assistants.Service
-	Responses  responses.Service
-	Embedding  embedding.Service
+	Chat         chat.Service
+	Moderation   moderation.Service
+	Completion   completion.Service
+	Assistants   assistants.Service
+	Responses    responses.Service
+	Embedding    embedding.Service
+	Realtime     realtime.Service
+	Models       models.Service
+	Files        files.Service
+	Uploads      uploads.Service
+	Batch        batch.Service
+	VectorStores vectorstores.Service
+	Audio        audio.Service
+	Images       images.Service
 
 	config *openai.Config
 }
@@ -40,6 +64,14 @@ func NewClient(token string) *Client {
 	c.Assistants = inassistants.NewClient(c.config)
 	c.Responses = inresponses.NewClient(c.config)
 	c.Embedding = inembedding.NewClient(c.config)
+	c.Realtime = inrealtime.NewClient(c.config)
+	c.Models = inmodels.NewClient(c.config)
+	c.Files = infiles.NewClient(c.config)
+	c.Uploads = inuploads.NewClient(c.config)
+	c.Batch = inbatch.NewClient(c.config)
+	c.VectorStores = invectorstores.NewClient(c.config)
+	c.Audio = inaudio.NewClient(c.config)
+	c.Images = inimages.NewClient(c.config)
 	return c
 }
 
@@ -52,3 +84,8 @@ func (c *Client) Config() *openai.Config {
 func (c *Client) Tools() *tools.Registry {
 	return c.config.Tools
 }
+
+// ModelRegistry provides access to the client's model registry.
+func (c *Client) ModelRegistry() *models.Registry {
+	return c.config.Models
+}
@@ -607,14 +607,14 @@ type FunctionCall struct {
 	// required
 
 	Type      string `json:"type"` // "function_call"
-	ID        string `json:"id"`
+	ID        string `json:"id,omitempty"`
 	Name      string `json:"name"`
 	Arguments string `json:"arguments"` // has JSON object, but as a string
 
 	// optional
 
 	CallID string `json:"call_id"`
-	Status string `json:"status"` // "in_progress", "completed", "incomplete"
+	Status string `json:"status,omitempty"` // "in_progress", "completed", "incomplete"
 }
 
 // MarshalJSON implements the json.Marshaler interface.
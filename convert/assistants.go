@@ -0,0 +1,129 @@
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/unkn0wncode/openai/assistants"
+	"github.com/unkn0wncode/openai/content/input"
+	"github.com/unkn0wncode/openai/content/output"
+	"github.com/unkn0wncode/openai/roles"
+)
+
+// AssistantsToItems converts messages of an Assistants thread into Responses items.
+// Images are kept in user messages only, as file IDs or URLs.
+func AssistantsToItems(msgs []assistants.Message) ([]any, error) {
+	items := make([]any, 0, len(msgs))
+	for i, msg := range msgs {
+		switch msg.Role {
+		case roles.User, roles.Assistant:
+		default:
+			return nil, fmt.Errorf("message %d has unsupported role '%s'", i, msg.Role)
+		}
+
+		parts, err := assistantsContent(msg.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse content of message %d: %w", i, err)
+		}
+
+		var content []any
+		for _, part := range parts {
+			switch p := part.(type) {
+			case output.Text:
+				if msg.Role == roles.Assistant {
+					content = append(content, output.OutputText{Text: p.Text.Value})
+				} else {
+					content = append(content, input.InputText{Text: p.Text.Value})
+				}
+			case output.Refusal:
+				content = append(content, p)
+			case output.ImageFile:
+				if msg.Role == roles.User {
+					content = append(content, input.InputImage{FileID: p.File.FileID, Detail: p.File.Detail})
+				}
+			case output.ImageURL:
+				if msg.Role == roles.User {
+					content = append(content, input.InputImage{ImageURL: p.Image.URL, Detail: p.Image.Detail})
+				}
+			}
+		}
+
+		items = append(items, output.Message{Role: msg.Role, Content: content})
+	}
+
+	return items, nil
+}
+
+// assistantsContent parses message content given as a string or an array of content parts.
+// Text given as a string is returned as output.Text.
+func assistantsContent(content any) ([]any, error) {
+	if text, ok := content.(string); ok {
+		var t output.Text
+		t.Text.Value = text
+		return []any{t}, nil
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	var anyParts []output.Any
+	if err := json.Unmarshal(data, &anyParts); err != nil {
+		return nil, err
+	}
+
+	parts := make([]any, 0, len(anyParts))
+	for _, a := range anyParts {
+		p, err := a.Unmarshal()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, p)
+	}
+	return parts, nil
+}
+
+// ItemsToAssistants converts Responses items into messages for an Assistants thread.
+// Threads only have user and assistant messages, so other messages and function calls are skipped.
+func ItemsToAssistants[T any](items []T) ([]assistants.InputMessage, error) {
+	parsed, err := parseItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []assistants.InputMessage
+	for _, item := range parsed {
+		msg, ok := item.(output.Message)
+		if !ok || (msg.Role != roles.User && msg.Role != roles.Assistant) {
+			continue
+		}
+
+		mc := parseMessageContent(msg.Content)
+		if len(mc.images) == 0 || msg.Role != roles.User {
+			msgs = append(msgs, assistants.InputMessage{Role: msg.Role, Content: mc.text()})
+			continue
+		}
+
+		var content []any
+		if text := mc.text(); text != "" {
+			content = append(content, input.Text{Text: text})
+		}
+		for _, img := range mc.images {
+			switch {
+			case img.FileID != "":
+				var part input.ImageFile
+				part.File.FileID = img.FileID
+				part.File.Detail = img.Detail
+				content = append(content, part)
+			case img.ImageURL != "":
+				var part input.ImageURL
+				part.Image.URL = img.ImageURL
+				part.Image.Detail = img.Detail
+				content = append(content, part)
+			}
+		}
+		msgs = append(msgs, assistants.InputMessage{Role: msg.Role, Content: content})
+	}
+
+	return msgs, nil
+}
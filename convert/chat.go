@@ -0,0 +1,168 @@
+package convert
+
+import (
+	"fmt"
+
+	"github.com/unkn0wncode/openai/chat"
+	"github.com/unkn0wncode/openai/content/input"
+	"github.com/unkn0wncode/openai/content/output"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/roles"
+)
+
+// ChatToItems converts Chat API messages into Responses items.
+// Tool calls of assistant messages become FunctionCall items following the message,
+// tool messages become FunctionCallOutput items. Deprecated function calls and
+// function messages get generated call IDs to link them together.
+func ChatToItems(msgs []chat.Message) ([]any, error) {
+	var (
+		items []any
+		// call IDs of deprecated function calls waiting for their outputs, by function name
+		pendingFunctions = map[string][]string{}
+	)
+	for i, msg := range msgs {
+		switch msg.Role {
+		case roles.System, roles.Developer, roles.User:
+			items = append(items, chatInputMessage(msg))
+
+		case roles.Assistant:
+			switch {
+			case msg.Refusal != "":
+				items = append(items, output.Message{
+					Role:    roles.Assistant,
+					Content: []any{output.Refusal{Refusal: msg.Refusal}},
+				})
+			case msg.Content != "":
+				items = append(items, output.Message{
+					Role:    roles.Assistant,
+					Content: []any{output.OutputText{Text: msg.Content}},
+				})
+			}
+
+			for _, call := range msg.ToolCalls {
+				if call.Function == nil {
+					continue
+				}
+				items = append(items, output.FunctionCall{
+					CallID:    call.ID,
+					Name:      call.Function.Name,
+					Arguments: call.Function.Arguments,
+				})
+			}
+
+			if msg.FunctionCall.Name != "" {
+				callID := fmt.Sprintf("call_legacy_%d", i)
+				pendingFunctions[msg.FunctionCall.Name] = append(pendingFunctions[msg.FunctionCall.Name], callID)
+				items = append(items, output.FunctionCall{
+					CallID:    callID,
+					Name:      msg.FunctionCall.Name,
+					Arguments: msg.FunctionCall.Arguments,
+				})
+			}
+
+		case roles.Tool:
+			if msg.ToolCallID == "" {
+				return nil, fmt.Errorf("tool message %d has no tool call ID", i)
+			}
+			items = append(items, output.FunctionCallOutput{
+				CallID: msg.ToolCallID,
+				Output: msg.Content,
+			})
+
+		case roles.Function:
+			pending := pendingFunctions[msg.Name]
+			if len(pending) == 0 {
+				return nil, fmt.Errorf("function message %d has no preceding call of function '%s'", i, msg.Name)
+			}
+			pendingFunctions[msg.Name] = pending[1:]
+			items = append(items, output.FunctionCallOutput{
+				CallID: pending[0],
+				Output: msg.Content,
+			})
+
+		default:
+			return nil, fmt.Errorf("message %d has unsupported role '%s'", i, msg.Role)
+		}
+	}
+
+	return items, nil
+}
+
+// chatInputMessage converts a system, developer or user message with optional images.
+func chatInputMessage(msg chat.Message) output.Message {
+	if len(msg.Images) == 0 {
+		return output.Message{Role: msg.Role, Content: msg.Content}
+	}
+
+	var content []any
+	if msg.Content != "" {
+		content = append(content, input.InputText{Text: msg.Content})
+	}
+	for _, img := range msg.Images {
+		content = append(content, input.InputImage{ImageURL: img.URL, Detail: img.Detail})
+	}
+	return output.Message{Role: msg.Role, Content: content}
+}
+
+// ItemsToChat converts Responses items into Chat API messages.
+// Consecutive function calls are grouped into one assistant message with tool calls,
+// following the assistant text message if there's one right before them.
+// Images are kept only if they are given by URL.
+func ItemsToChat[T any](items []T) ([]chat.Message, error) {
+	parsed, err := parseItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []chat.Message
+	// lastAssistant is the index of the assistant message that can take tool calls, or -1
+	lastAssistant := -1
+	for _, item := range parsed {
+		switch it := item.(type) {
+		case output.Message:
+			mc := parseMessageContent(it.Content)
+			msg := chat.Message{Role: it.Role, Content: mc.text()}
+			if msg.Role == "" {
+				msg.Role = roles.User
+			}
+			for _, img := range mc.images {
+				if img.ImageURL != "" {
+					msg.Images = append(msg.Images, chat.Image{URL: img.ImageURL, Detail: img.Detail})
+				}
+			}
+			if len(mc.refusals) > 0 {
+				msg.Refusal = mc.refusals[0]
+			}
+
+			msgs = append(msgs, msg)
+			lastAssistant = -1
+			if msg.Role == roles.Assistant {
+				lastAssistant = len(msgs) - 1
+			}
+
+		case output.FunctionCall:
+			if lastAssistant < 0 {
+				msgs = append(msgs, chat.Message{Role: roles.Assistant})
+				lastAssistant = len(msgs) - 1
+			}
+			msgs[lastAssistant].ToolCalls = append(msgs[lastAssistant].ToolCalls, openai.ToolCallData{
+				ID:   it.CallID,
+				Type: "function",
+				Function: &openai.FunctionCallData{
+					Name:      it.Name,
+					Arguments: it.Arguments,
+				},
+			})
+
+		case output.FunctionCallOutput:
+			msgs = append(msgs, chat.Message{
+				Role:       roles.Tool,
+				ToolCallID: it.CallID,
+				Content:    it.Output,
+			})
+			lastAssistant = -1
+		}
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,91 @@
+// Package convert provides converters of conversation histories between the Chat,
+// Assistants and Responses APIs, e.g. to migrate legacy histories into Responses conversations.
+//
+// Responses items are represented by output.Message, output.FunctionCall and
+// output.FunctionCallOutput. Converters from items also accept output.Any and any other
+// values that marshal to JSON items, such as results of Service.ExportConversation.
+// Items that have no counterpart in the target API, like reasoning or built-in tool calls,
+// are skipped.
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/unkn0wncode/openai/content/input"
+	"github.com/unkn0wncode/openai/content/output"
+)
+
+// parseItems converts items to parsed types of the output package.
+func parseItems[T any](items []T) ([]any, error) {
+	parsed := make([]any, 0, len(items))
+	for i, item := range items {
+		var anyItem output.Any
+		switch v := any(item).(type) {
+		case output.Message, output.FunctionCall, output.FunctionCallOutput:
+			parsed = append(parsed, v)
+			continue
+		case output.Any:
+			anyItem = v
+		case *output.Any:
+			anyItem = *v
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal item %d: %w", i, err)
+			}
+			if err := json.Unmarshal(data, &anyItem); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal item %d: %w", i, err)
+			}
+		}
+
+		p, err := anyItem.Unmarshal()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse item %d: %w", i, err)
+		}
+		parsed = append(parsed, p)
+	}
+	return parsed, nil
+}
+
+// messageContent is content of a Responses message reduced to what other APIs support.
+type messageContent struct {
+	texts    []string
+	images   []input.InputImage
+	refusals []string
+}
+
+// text returns texts joined with newlines.
+func (mc messageContent) text() string {
+	return strings.Join(mc.texts, "\n")
+}
+
+// parseMessageContent collects texts, images and refusals from message content.
+func parseMessageContent(content any) messageContent {
+	var mc messageContent
+	switch c := content.(type) {
+	case string:
+		if c != "" {
+			mc.texts = append(mc.texts, c)
+		}
+	case []any:
+		for _, part := range c {
+			switch p := part.(type) {
+			case string:
+				mc.texts = append(mc.texts, p)
+			case output.OutputText:
+				mc.texts = append(mc.texts, p.Text)
+			case input.InputText:
+				mc.texts = append(mc.texts, p.Text)
+			case output.Text:
+				mc.texts = append(mc.texts, p.Text.Value)
+			case input.InputImage:
+				mc.images = append(mc.images, p)
+			case output.Refusal:
+				mc.refusals = append(mc.refusals, p.Refusal)
+			}
+		}
+	}
+	return mc
+}
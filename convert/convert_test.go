@@ -0,0 +1,109 @@
+package convert
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/assistants"
+	"github.com/unkn0wncode/openai/chat"
+	"github.com/unkn0wncode/openai/content/input"
+	"github.com/unkn0wncode/openai/content/output"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/roles"
+)
+
+func TestChat(t *testing.T) {
+	t.Parallel()
+
+	msgs := []chat.Message{
+		{Role: roles.System, Content: "Be brief."},
+		{Role: roles.User, Content: "What's on the picture?", Images: []chat.Image{{URL: "https://example.com/a.png", Detail: "low"}}},
+		{Role: roles.Assistant, Content: "Let me check.", ToolCalls: []openai.ToolCallData{
+			{ID: "call_1", Type: "function", Function: &openai.FunctionCallData{Name: "describe", Arguments: `{"n":1}`}},
+			{ID: "call_2", Type: "function", Function: &openai.FunctionCallData{Name: "describe", Arguments: `{"n":2}`}},
+		}},
+		{Role: roles.Tool, ToolCallID: "call_1", Content: "a cat"},
+		{Role: roles.Tool, ToolCallID: "call_2", Content: "a dog"},
+		{Role: roles.Assistant, Content: "A cat and a dog."},
+	}
+
+	items, err := ChatToItems(msgs)
+	require.NoError(t, err)
+	require.Len(t, items, 8)
+	require.Equal(t, output.Message{Role: roles.System, Content: "Be brief."}, items[0])
+	require.Equal(t, []any{
+		input.InputText{Text: "What's on the picture?"},
+		input.InputImage{ImageURL: "https://example.com/a.png", Detail: "low"},
+	}, items[1].(output.Message).Content)
+	require.Equal(t, output.FunctionCall{CallID: "call_2", Name: "describe", Arguments: `{"n":2}`}, items[4])
+	require.Equal(t, output.FunctionCallOutput{CallID: "call_1", Output: "a cat"}, items[5])
+
+	// converted calls have no status, which must not be sent as an empty enum value
+	call, err := json.Marshal(items[4])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"function_call","name":"describe","arguments":"{\"n\":2}","call_id":"call_2"}`, string(call))
+
+	// items survive a JSON round trip, like when they're exported from a conversation
+	data, err := json.Marshal(items)
+	require.NoError(t, err)
+	var exported []output.Any
+	require.NoError(t, json.Unmarshal(data, &exported))
+
+	back, err := ItemsToChat(exported)
+	require.NoError(t, err)
+	require.Equal(t, msgs, back)
+
+	t.Run("LegacyFunctions", func(t *testing.T) {
+		t.Parallel()
+		items, err := ChatToItems([]chat.Message{
+			{Role: roles.Assistant, FunctionCall: openai.FunctionCallData{Name: "now", Arguments: "{}"}},
+			{Role: roles.Function, Name: "now", Content: "noon"},
+		})
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		require.Equal(t, items[0].(output.FunctionCall).CallID, items[1].(output.FunctionCallOutput).CallID)
+
+		_, err = ChatToItems([]chat.Message{{Role: roles.Function, Name: "now", Content: "noon"}})
+		require.Error(t, err)
+	})
+}
+
+func TestAssistants(t *testing.T) {
+	t.Parallel()
+
+	// content as returned by Thread.Messages
+	var content []output.Any
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"type":"text","text":{"value":"Look at this","annotations":[]}},
+		{"type":"image_file","image_file":{"file_id":"file_1"}}
+	]`), &content))
+
+	items, err := AssistantsToItems([]assistants.Message{
+		{Role: roles.User, Content: content},
+		{Role: roles.Assistant, Content: "Nice picture."},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []any{
+		output.Message{Role: roles.User, Content: []any{
+			input.InputText{Text: "Look at this"},
+			input.InputImage{FileID: "file_1"},
+		}},
+		output.Message{Role: roles.Assistant, Content: []any{output.OutputText{Text: "Nice picture."}}},
+	}, items)
+
+	// system messages and function calls have no place in a thread
+	items = append(items,
+		output.Message{Role: roles.System, Content: "Be brief."},
+		output.FunctionCall{CallID: "call_1", Name: "f", Arguments: "{}"},
+	)
+	msgs, err := ItemsToAssistants(items)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	require.Equal(t, assistants.InputMessage{Role: roles.Assistant, Content: "Nice picture."}, msgs[1])
+
+	parts := msgs[0].Content.([]any)
+	require.Len(t, parts, 2)
+	require.Equal(t, input.Text{Text: "Look at this"}, parts[0])
+	require.Equal(t, "file_1", parts[1].(input.ImageFile).File.FileID)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/unkn0wncode/openai"
+	"github.com/unkn0wncode/openai/chat"
+	"github.com/unkn0wncode/openai/roles"
+)
+
+func main() {
+	token := os.Getenv("OPENAI_API_KEY")
+	if token == "" {
+		panic("OPENAI_API_KEY not set")
+	}
+
+	client := openai.NewClient(token)
+
+	req := chat.Request{
+		Messages:      []chat.Message{{Role: roles.User, Content: "Write a short poem about the sea."}},
+		StreamOptions: &chat.StreamOptions{IncludeUsage: true},
+	}
+
+	stream, err := client.Chat.Stream(context.Background(), req)
+	if err != nil {
+		panic(err)
+	}
+
+	for stream.Next() {
+		for _, choice := range stream.Chunk().Choices {
+			fmt.Print(choice.Delta.Content)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		panic(err)
+	}
+
+	acc := stream.Accumulator()
+	fmt.Printf("\n\nfinish reason: %s\n", acc.FinishReason())
+	if acc.Usage != nil {
+		fmt.Printf("total tokens: %d\n", acc.Usage.TotalTokens)
+	}
+}
@@ -0,0 +1,101 @@
+// Package files provides a wrapper for the OpenAI Files API.
+package files
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Purposes of files.
+const (
+	PurposeAssistants = "assistants" // for Assistants and file search
+	PurposeBatch      = "batch"      // input of the Batch API
+	PurposeFineTune   = "fine-tune"  // training data for fine-tuning
+	PurposeVision     = "vision"     // images for fine-tuning
+	PurposeUserData   = "user_data"  // flexible file type for any purpose, e.g. input files
+	PurposeEvals      = "evals"      // eval data sets
+)
+
+// Service is the service layer for OpenAI Files API.
+type Service interface {
+	// Upload uploads a file. The content is streamed as a multipart body without buffering it
+	// in memory, so unlike other requests the upload is not retried.
+	// ctx controls cancellation of the upload.
+	Upload(ctx context.Context, req *UploadRequest) (*File, error)
+
+	// List returns a page of files.
+	List(opts *ListOptions) (*FileList, error)
+
+	// ListAll returns all files with given purpose, or all files for empty purpose,
+	// fetching as many pages as needed.
+	ListAll(purpose string) ([]File, error)
+
+	// Retrieve returns a file by ID.
+	Retrieve(id string) (*File, error)
+
+	// Content returns a reader of the file content, the caller must close it.
+	// The overall Timeout of HTTPClient is not applied, ctx controls cancellation of the download.
+	Content(ctx context.Context, id string) (io.ReadCloser, error)
+
+	// Delete deletes a file.
+	Delete(id string) error
+}
+
+// UploadRequest contains a file to upload.
+type UploadRequest struct {
+	// required
+	Filename string    // name of the file, its extension defines the file type for the API
+	Purpose  string    // one of Purpose* constants
+	Content  io.Reader // content of the file, read until EOF
+
+	// optional
+	// ExpiresAfter sets expiration of the file after its creation, from 1 hour to 30 days.
+	// Files with PurposeBatch expire after 30 days by default, other files are kept until deleted.
+	ExpiresAfter time.Duration
+}
+
+// File is a file object returned by the API.
+type File struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"` // always "file"
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"` // Unix timestamp
+	ExpiresAt int64  `json:"expires_at"` // Unix timestamp, zero if the file doesn't expire
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+}
+
+// Created returns the creation time of the file.
+func (f File) Created() time.Time {
+	return time.Unix(f.CreatedAt, 0)
+}
+
+// Expires returns the expiration time of the file, or zero time if it doesn't expire.
+func (f File) Expires() time.Time {
+	if f.ExpiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(f.ExpiresAt, 0)
+}
+
+// ListOptions configures filtering and pagination when listing files.
+type ListOptions struct {
+	// Purpose filters files by purpose.
+	Purpose string
+	// Limit is the number of files per page, from 1 to 10000, default is 10000.
+	Limit int
+	// After is a file ID to list files after, use FileList.LastID for the next page.
+	After string
+	// Order is "asc" or "desc" (default, newest files first).
+	Order string
+}
+
+// FileList is a page of files.
+type FileList struct {
+	Object  string `json:"object"` // always "list"
+	Data    []File `json:"data"`
+	FirstID string `json:"first_id"`
+	LastID  string `json:"last_id"`
+	HasMore bool   `json:"has_more"`
+}
@@ -0,0 +1,260 @@
+// Package filters provides a builder of attribute filters for the file_search tool
+// and vector store search. Filters are validated when marshaled.
+//
+//	f := filters.And(
+//		filters.Eq("region", "us"),
+//		filters.Or(filters.Gte("year", 2023), filters.In("tag", "policy", "faq")),
+//	)
+package filters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Comparison operators.
+const (
+	TypeEq  = "eq"  // equal
+	TypeNe  = "ne"  // not equal
+	TypeGt  = "gt"  // greater than
+	TypeGte = "gte" // greater than or equal
+	TypeLt  = "lt"  // less than
+	TypeLte = "lte" // less than or equal
+	TypeIn  = "in"  // value is in the list
+	TypeNin = "nin" // value is not in the list
+)
+
+// Compound operators.
+const (
+	TypeAnd = "and"
+	TypeOr  = "or"
+)
+
+// Filter is a comparison or compound filter.
+type Filter interface {
+	json.Marshaler
+
+	// Validate checks the operator, the key and types of values.
+	Validate() error
+
+	filter()
+}
+
+// Comparison compares an attribute with a value.
+type Comparison struct {
+	Type  string // one of comparison Type* constants
+	Key   string
+	Value any // string, number or bool, or a slice of strings or numbers for in/nin
+}
+
+// Compound combines filters with a logical operator.
+type Compound struct {
+	Type    string // TypeAnd or TypeOr
+	Filters []Filter
+}
+
+// interface compliance checks
+var (
+	_ Filter = Comparison{}
+	_ Filter = Compound{}
+)
+
+func (Comparison) filter() {}
+func (Compound) filter()   {}
+
+// Eq matches attributes equal to the value.
+func Eq(key string, value any) Comparison {
+	return Comparison{Type: TypeEq, Key: key, Value: value}
+}
+
+// Ne matches attributes not equal to the value.
+func Ne(key string, value any) Comparison {
+	return Comparison{Type: TypeNe, Key: key, Value: value}
+}
+
+// Gt matches attributes greater than the number.
+func Gt(key string, value any) Comparison {
+	return Comparison{Type: TypeGt, Key: key, Value: value}
+}
+
+// Gte matches attributes greater than or equal to the number.
+func Gte(key string, value any) Comparison {
+	return Comparison{Type: TypeGte, Key: key, Value: value}
+}
+
+// Lt matches attributes less than the number.
+func Lt(key string, value any) Comparison {
+	return Comparison{Type: TypeLt, Key: key, Value: value}
+}
+
+// Lte matches attributes less than or equal to the number.
+func Lte(key string, value any) Comparison {
+	return Comparison{Type: TypeLte, Key: key, Value: value}
+}
+
+// In matches attributes equal to one of the values.
+// A single slice, like []string, can be passed instead of separate values.
+func In(key string, values ...any) Comparison {
+	return Comparison{Type: TypeIn, Key: key, Value: unwrap(values)}
+}
+
+// Nin matches attributes equal to none of the values.
+// A single slice, like []string, can be passed instead of separate values.
+func Nin(key string, values ...any) Comparison {
+	return Comparison{Type: TypeNin, Key: key, Value: unwrap(values)}
+}
+
+// unwrap returns values of a single slice argument, or arguments as they are.
+func unwrap(values []any) []any {
+	if len(values) == 1 {
+		if l, err := list(values[0]); err == nil {
+			return l
+		}
+	}
+	return values
+}
+
+// And matches when all filters match.
+func And(filters ...Filter) Compound {
+	return Compound{Type: TypeAnd, Filters: filters}
+}
+
+// Or matches when any of filters matches.
+func Or(filters ...Filter) Compound {
+	return Compound{Type: TypeOr, Filters: filters}
+}
+
+// Validate checks the operator, the key and types of values.
+func (c Comparison) Validate() error {
+	if c.Key == "" {
+		return fmt.Errorf("%s filter has no key", c.Type)
+	}
+
+	switch c.Type {
+	case TypeEq, TypeNe:
+		if !isScalar(c.Value) {
+			return fmt.Errorf("%s filter on '%s' needs a string, number or bool, got %T", c.Type, c.Key, c.Value)
+		}
+	case TypeGt, TypeGte, TypeLt, TypeLte:
+		if !isNumber(c.Value) {
+			return fmt.Errorf("%s filter on '%s' needs a number, got %T", c.Type, c.Key, c.Value)
+		}
+	case TypeIn, TypeNin:
+		values, err := list(c.Value)
+		if err != nil {
+			return fmt.Errorf("%s filter on '%s': %w", c.Type, c.Key, err)
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("%s filter on '%s' has no values", c.Type, c.Key)
+		}
+		for _, v := range values {
+			if !isString(v) && !isNumber(v) {
+				return fmt.Errorf("%s filter on '%s' needs strings or numbers, got %T", c.Type, c.Key, v)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown comparison filter type '%s'", c.Type)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, the filter is validated first.
+func (c Comparison) MarshalJSON() ([]byte, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	value := c.Value
+	if c.Type == TypeIn || c.Type == TypeNin {
+		value, _ = list(c.Value)
+	}
+	return json.Marshal(struct {
+		Type  string `json:"type"`
+		Key   string `json:"key"`
+		Value any    `json:"value"`
+	}{c.Type, c.Key, value})
+}
+
+// Validate checks the operator and all nested filters.
+func (c Compound) Validate() error {
+	if c.Type != TypeAnd && c.Type != TypeOr {
+		return fmt.Errorf("unknown compound filter type '%s'", c.Type)
+	}
+	if len(c.Filters) == 0 {
+		return fmt.Errorf("%s filter has no filters", c.Type)
+	}
+	for i, f := range c.Filters {
+		if f == nil {
+			return fmt.Errorf("filter %d of %s filter is nil", i, c.Type)
+		}
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("filter %d of %s filter: %w", i, c.Type, err)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, the filter is validated first.
+func (c Compound) MarshalJSON() ([]byte, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Type    string   `json:"type"`
+		Filters []Filter `json:"filters"`
+	}{c.Type, c.Filters})
+}
+
+// Validate validates the filter if it's a Filter, other values, like hand-written maps,
+// are left for the API to check.
+func Validate(f any) error {
+	if f, ok := f.(Filter); ok {
+		return f.Validate()
+	}
+	return nil
+}
+
+// list converts a slice of values to []any.
+func list(v any) ([]any, error) {
+	switch v := v.(type) {
+	case []any:
+		return v, nil
+	case []string:
+		return toAny(v), nil
+	case []int:
+		return toAny(v), nil
+	case []int64:
+		return toAny(v), nil
+	case []float64:
+		return toAny(v), nil
+	}
+	return nil, errors.New("value must be a slice")
+}
+
+// toAny converts a typed slice to []any.
+func toAny[T any](values []T) []any {
+	result := make([]any, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+
+func isScalar(v any) bool {
+	_, isBool := v.(bool)
+	return isBool || isString(v) || isNumber(v)
+}
+
+func isString(v any) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+func isNumber(v any) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package filters
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	f := And(
+		Eq("region", "us"),
+		Or(Gte("year", 2023), In("tag", []string{"policy", "faq"})),
+		Nin("status", "draft", 0),
+		Ne("archived", true),
+	)
+	b, err := json.Marshal(f)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"and","filters":[
+		{"type":"eq","key":"region","value":"us"},
+		{"type":"or","filters":[
+			{"type":"gte","key":"year","value":2023},
+			{"type":"in","key":"tag","value":["policy","faq"]}
+		]},
+		{"type":"nin","key":"status","value":["draft",0]},
+		{"type":"ne","key":"archived","value":true}
+	]}`, string(b))
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		filter Filter
+		err    string
+	}{
+		{"empty key", Eq("", "us"), "no key"},
+		{"eq slice", Eq("region", []string{"us"}), "string, number or bool"},
+		{"gt string", Gt("year", "2023"), "needs a number"},
+		{"lte bool", Lte("year", false), "needs a number"},
+		{"in empty", In("tag"), "no values"},
+		{"in bool", In("tag", true), "strings or numbers"},
+		{"in not slice", Comparison{Type: TypeIn, Key: "tag", Value: "a"}, "must be a slice"},
+		{"unknown comparison", Comparison{Type: "like", Key: "a", Value: "b"}, "unknown comparison"},
+		{"empty compound", And(), "no filters"},
+		{"unknown compound", Compound{Type: "not", Filters: []Filter{Eq("a", 1)}}, "unknown compound"},
+		{"nested", Or(Eq("a", 1), And(Gt("b", "x"))), "filter 1 of or filter: filter 0 of and filter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.ErrorContains(t, tt.filter.Validate(), tt.err)
+			_, err := json.Marshal(tt.filter)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	require.NoError(t, Validate(map[string]any{"type": "eq"}))
+	require.Error(t, Validate(Gt("year", "x")))
+}
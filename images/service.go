@@ -0,0 +1,205 @@
+// Package images provides a wrapper for the OpenAI Images API.
+package images
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Sizes of generated images. GPT image models support auto, 1024x1024, 1536x1024 and 1024x1536,
+// dall-e-2 supports 256x256, 512x512 and 1024x1024, dall-e-3 supports 1024x1024, 1792x1024 and 1024x1792.
+const (
+	SizeAuto      = "auto"
+	Size256       = "256x256"
+	Size512       = "512x512"
+	Size1024      = "1024x1024"
+	Size1536x1024 = "1536x1024"
+	Size1024x1536 = "1024x1536"
+	Size1792x1024 = "1792x1024"
+	Size1024x1792 = "1024x1792"
+)
+
+// Qualities of generated images. GPT image models support auto, low, medium and high,
+// dall-e-3 supports standard and hd, dall-e-2 only standard.
+const (
+	QualityAuto     = "auto"
+	QualityLow      = "low"
+	QualityMedium   = "medium"
+	QualityHigh     = "high"
+	QualityStandard = "standard"
+	QualityHD       = "hd"
+)
+
+// Backgrounds of images of GPT image models.
+const (
+	BackgroundAuto        = "auto"
+	BackgroundTransparent = "transparent" // requires png or webp output format
+	BackgroundOpaque      = "opaque"
+)
+
+// Output formats of images of GPT image models.
+const (
+	FormatPNG  = "png"
+	FormatJPEG = "jpeg"
+	FormatWEBP = "webp"
+)
+
+// Response formats of dall-e models. GPT image models always return base64-encoded images.
+const (
+	ResponseFormatURL     = "url" // URLs are valid for 60 minutes
+	ResponseFormatB64JSON = "b64_json"
+)
+
+// Types of stream events.
+const (
+	EventGenerationPartialImage = "image_generation.partial_image"
+	EventGenerationCompleted    = "image_generation.completed"
+	EventEditPartialImage       = "image_edit.partial_image"
+	EventEditCompleted          = "image_edit.completed"
+)
+
+// MaxPartialImages is the maximum number of partial images of a streamed image.
+const MaxPartialImages = 3
+
+// Service is the service layer for OpenAI Images API.
+type Service interface {
+	// Generate creates images from a prompt.
+	// The overall Timeout of HTTPClient is not applied, ctx controls cancellation of the request.
+	Generate(ctx context.Context, req *GenerateRequest) (*Result, error)
+
+	// GenerateStream creates an image from a prompt and streams partial images while it's generated.
+	// Only supported by GPT image models. ctx controls cancellation of the whole stream.
+	GenerateStream(ctx context.Context, req *GenerateRequest) (*Stream, error)
+
+	// Edit creates images from input images, an optional mask and a prompt.
+	Edit(ctx context.Context, req *EditRequest) (*Result, error)
+
+	// EditStream edits images and streams partial images while the result is generated.
+	// Only supported by GPT image models. ctx controls cancellation of the whole stream.
+	EditStream(ctx context.Context, req *EditRequest) (*Stream, error)
+
+	// Variation creates variations of an image. Only supported by dall-e-2.
+	Variation(ctx context.Context, req *VariationRequest) (*Result, error)
+}
+
+// GenerateRequest contains parameters of image generation.
+type GenerateRequest struct {
+	// required
+	Prompt string // up to LimitPrompt characters of the model
+
+	// optional
+	Model      string // default is models.DefaultImage
+	N          int    // number of images, up to LimitOutImages of the model, default is 1
+	Size       string // one of Size* constants
+	Quality    string // one of Quality* constants
+	Background string // one of Background* constants, GPT image models only
+	// OutputFormat is one of Format* constants, GPT image models only, default is png.
+	OutputFormat string
+	// OutputCompression is the compression level from 0 to 100 for jpeg and webp output formats.
+	OutputCompression *int
+	Moderation        string // "auto" or "low", GPT image models only
+	ResponseFormat    string // one of ResponseFormat* constants, dall-e models only
+	Style             string // "vivid" or "natural", dall-e-3 only
+	// PartialImages is the number of partial images sent by streams, up to MaxPartialImages.
+	PartialImages int
+	User          string
+}
+
+// InputImage is an image file sent with a request.
+type InputImage struct {
+	Content  io.Reader
+	Filename string // the extension tells the format: png, jpg, jpeg or webp
+}
+
+// EditRequest contains parameters of image editing.
+type EditRequest struct {
+	// required
+	// Images are edited or used as references, up to LimitInImages of the model.
+	// dall-e-2 accepts one square png image.
+	Images []InputImage
+	Prompt string
+
+	// optional
+	// Mask is a png image with transparent areas marking where to edit the first image.
+	Mask         *InputImage
+	Model        string // default is models.DefaultImage
+	N            int
+	Size         string
+	Quality      string
+	Background   string
+	OutputFormat string
+	// OutputCompression is the compression level from 0 to 100 for jpeg and webp output formats.
+	OutputCompression *int
+	// InputFidelity is "high" or "low", it controls how closely input images are matched.
+	// Not supported by gpt-image-1-mini and dall-e models.
+	InputFidelity  string
+	ResponseFormat string
+	PartialImages  int
+	User           string
+}
+
+// VariationRequest contains parameters of image variations.
+type VariationRequest struct {
+	// required
+	Image InputImage // square png image
+
+	// optional
+	Model          string // default and the only supported model is dall-e-2
+	N              int
+	Size           string
+	ResponseFormat string
+	User           string
+}
+
+// Image is a generated image.
+type Image struct {
+	B64JSON       string `json:"b64_json,omitempty"`
+	URL           string `json:"url,omitempty"`
+	RevisedPrompt string `json:"revised_prompt,omitempty"` // dall-e-3 only
+}
+
+// Bytes decodes the base64-encoded image. Images returned as URLs must be downloaded instead.
+func (i Image) Bytes() ([]byte, error) {
+	if i.B64JSON == "" {
+		if i.URL != "" {
+			return nil, errors.New("image is returned as URL")
+		}
+		return nil, errors.New("image has no data")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(i.B64JSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return b, nil
+}
+
+// Usage is the token usage of GPT image models.
+type Usage struct {
+	InputTokens        int `json:"input_tokens"`
+	OutputTokens       int `json:"output_tokens"`
+	TotalTokens        int `json:"total_tokens"`
+	InputTokensDetails struct {
+		TextTokens  int `json:"text_tokens"`
+		ImageTokens int `json:"image_tokens"`
+	} `json:"input_tokens_details"`
+}
+
+// Result is a response of the Images API.
+type Result struct {
+	Created      int64   `json:"created"`
+	Images       []Image `json:"data"`
+	Background   string  `json:"background,omitempty"`
+	OutputFormat string  `json:"output_format,omitempty"`
+	Quality      string  `json:"quality,omitempty"`
+	Size         string  `json:"size,omitempty"`
+	Usage        *Usage  `json:"usage,omitempty"`
+
+	Model string `json:"-"`
+	// Cost is the cost in USD calculated from token usage, or from the price per image
+	// when usage is not returned. Zero if pricing is unknown.
+	Cost float64 `json:"-"`
+}
@@ -0,0 +1,136 @@
+package images
+
+import (
+	"context"
+	"errors"
+
+	openai "github.com/unkn0wncode/openai/internal"
+)
+
+// errNoImage is returned by Stream.Result when the stream ended without a completed image.
+var errNoImage = errors.New("stream ended without a completed image")
+
+// Event is an event of an image stream.
+type Event struct {
+	Type string // one of Event* constants
+	// Image is a partial image of *PartialImage events or the final image of *Completed events.
+	Image Image
+	// PartialImageIndex is the index of a partial image, starting from 0.
+	PartialImageIndex int
+
+	Background   string
+	OutputFormat string
+	Quality      string
+	Size         string
+	// Usage and Cost are set for *Completed events.
+	Usage *Usage
+	Cost  float64
+}
+
+// Completed reports whether the event carries the final image.
+func (e Event) Completed() bool {
+	return e.Type == EventGenerationCompleted || e.Type == EventEditCompleted
+}
+
+// Stream iterates over events of a streamed image generation.
+type Stream struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	items   <-chan any
+	current Event
+	err     error
+	done    bool
+
+	model  string
+	result *Result
+}
+
+// NewStream creates a new Stream from a channel delivering Event values or an error for
+// the model. Channel is expected to be closed after the last event or after an error.
+// cancel, if not nil, is called to abort the underlying request when the stream ends or is closed.
+func NewStream(ctx context.Context, model string, items <-chan any, cancel context.CancelFunc) *Stream {
+	return &Stream{ctx: ctx, cancel: cancel, items: items, model: model}
+}
+
+// Next advances the stream to the next event.
+// It returns true if there is an event available, false if the stream is done or an error occurred.
+// After Next returns false, use Err() to check if it was due to an error.
+func (s *Stream) Next() bool {
+	for !s.done {
+		item, ok, err := openai.ReceiveItem(s.ctx, s.items)
+		if !ok {
+			s.err = err
+			s.finish()
+			return false
+		}
+		if e, isEvent := item.(Event); isEvent {
+			s.current = e
+			if e.Completed() {
+				s.add(e)
+			}
+			return true
+		}
+		// unexpected items are skipped
+	}
+
+	return false
+}
+
+// add accumulates a completed image into the result.
+func (s *Stream) add(e Event) {
+	if s.result == nil {
+		s.result = &Result{Model: s.model}
+	}
+	s.result.Images = append(s.result.Images, e.Image)
+	s.result.Background = e.Background
+	s.result.OutputFormat = e.OutputFormat
+	s.result.Quality = e.Quality
+	s.result.Size = e.Size
+	s.result.Cost += e.Cost
+	if e.Usage != nil {
+		if s.result.Usage == nil {
+			s.result.Usage = &Usage{}
+		}
+		s.result.Usage.InputTokens += e.Usage.InputTokens
+		s.result.Usage.OutputTokens += e.Usage.OutputTokens
+		s.result.Usage.TotalTokens += e.Usage.TotalTokens
+		s.result.Usage.InputTokensDetails.TextTokens += e.Usage.InputTokensDetails.TextTokens
+		s.result.Usage.InputTokensDetails.ImageTokens += e.Usage.InputTokensDetails.ImageTokens
+	}
+}
+
+// Event returns the current event. Only valid after Next() returns true.
+func (s *Stream) Event() Event {
+	return s.current
+}
+
+// Err returns any error that occurred during iteration.
+func (s *Stream) Err() error {
+	return s.err
+}
+
+// Close stops the iteration and aborts the underlying request.
+func (s *Stream) Close() {
+	s.finish()
+}
+
+// finish marks the stream as done and releases the underlying request.
+func (s *Stream) finish() {
+	s.done = true
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// Result reads the rest of the stream and returns the completed images.
+func (s *Stream) Result() (*Result, error) {
+	for s.Next() {
+	}
+	if s.err != nil {
+		return s.result, s.err
+	}
+	if s.result == nil {
+		return nil, errNoImage
+	}
+	return s.result, nil
+}
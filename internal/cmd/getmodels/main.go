@@ -45,6 +45,12 @@ var Data = map[string]struct {
 	// Deprecated or unused models
 	{{template "modelList" .Deprecated}}
 }
+
+// Deprecated lists models from the "Deprecated or unused models" section of Data.
+var Deprecated = map[string]bool{
+	{{range .Deprecated}}"{{.ID}}": true,
+	{{end}}
+}
 `
 
 var (
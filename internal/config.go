@@ -6,7 +6,9 @@ import (
 	"log/slog"
 	"net/http"
 	"sync"
+	"time"
 
+	"github.com/unkn0wncode/openai/models"
 	"github.com/unkn0wncode/openai/tools"
 
 	"github.com/gorilla/websocket"
@@ -21,11 +23,40 @@ type Config struct {
 	BaseAPI    string
 	Token      string
 	HTTPClient *HTTPClient
-	// WebSocketDialer is used by Responses WebSocket mode.
+	// WebSocketDialer is used for WebSocket connections.
 	// If nil, a dialer is derived from HTTPClient settings when possible.
 	WebSocketDialer *websocket.Dialer
-	Log             *slog.Logger
-	Tools           *tools.Registry
+	// WebSocket configures keepalive and reconnection of WebSocket connections.
+	WebSocket WebSocketOptions
+	Log       *slog.Logger
+	Tools     *tools.Registry
+	// Models resolves default models, aliases and fallbacks of requests.
+	Models *models.Registry
+
+	// deprecationWarnings contains deprecated models that were already warned about
+	deprecationWarnings sync.Map
+}
+
+// WebSocketOptions configures keepalive and reconnection of WebSocket connections.
+type WebSocketOptions struct {
+	// PingInterval is the interval between pings sent to the server, zero disables keepalive.
+	PingInterval time.Duration
+	// PongTimeout is the time to wait for a pong or any other message after the ping interval
+	// before the connection is considered lost.
+	PongTimeout time.Duration
+	// MaxReconnects is the number of redial attempts after the connection is lost,
+	// zero disables reconnection.
+	MaxReconnects int
+	// ReconnectBackoff is the delay before the first redial attempt, doubled after each failure.
+	ReconnectBackoff time.Duration
+}
+
+// DefaultWebSocketOptions are the WebSocket options used by NewConfig.
+var DefaultWebSocketOptions = WebSocketOptions{
+	PingInterval:     30 * time.Second,
+	PongTimeout:      10 * time.Second,
+	MaxReconnects:    5,
+	ReconnectBackoff: 500 * time.Millisecond,
 }
 
 // NewConfig creates a default configuration with the provided token.
@@ -34,11 +65,13 @@ func NewConfig(token string) *Config {
 		BaseAPI:    DefaultBaseAPI,
 		Token:      token,
 		HTTPClient: NewHTTPClient(),
+		WebSocket:  DefaultWebSocketOptions,
 		Log:        slog.Default(),
 		Tools: &tools.Registry{
 			FunctionCalls: make(map[string]tools.FunctionCall),
 			Tools:         make(map[string]tools.Tool),
 		},
+		Models: models.NewRegistry(),
 	}
 
 	c.HTTPClient.Transport.(*LoggingTransport).Log = c.Log
@@ -0,0 +1,20 @@
+// Package inaudio provides a wrapper for the OpenAI Audio API.
+package inaudio
+
+import (
+	"github.com/unkn0wncode/openai/audio"
+	openai "github.com/unkn0wncode/openai/internal"
+)
+
+// Client is the client for the Audio API.
+type Client struct {
+	*openai.Config
+}
+
+// NewClient creates a new client for the Audio API.
+func NewClient(config *openai.Config) *Client {
+	return &Client{Config: config}
+}
+
+// interface compliance checks
+var _ audio.Service = (*Client)(nil)
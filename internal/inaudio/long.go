@@ -0,0 +1,229 @@
+package inaudio
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/unkn0wncode/openai/audio"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+)
+
+const (
+	// defaultChunkDuration is the maximum duration of chunks of long audio when not specified.
+	defaultChunkDuration = 10 * time.Minute
+	// defaultConcurrency is the number of chunks transcribed at once when not specified.
+	defaultConcurrency = 4
+	// defaultPromptTail is the number of characters of a transcript carried to the next chunk.
+	defaultPromptTail = 200
+)
+
+// TranscribeLong transcribes long WAV or PCM audio in chunks split at pauses.
+func (c *Client) TranscribeLong(ctx context.Context, data *audio.LongTranscriptionRequest) (*audio.Transcription, error) {
+	switch {
+	case data == nil:
+		return nil, errors.New("long transcription request is nil")
+	case data.Audio == nil:
+		return nil, errors.New("audio is required")
+	case data.Size <= 0:
+		return nil, fmt.Errorf("invalid audio size %d", data.Size)
+	}
+
+	var (
+		format       audio.PCMFormat
+		offset, size int64
+	)
+	if data.PCM != nil {
+		format = *data.PCM
+		if err := format.Validate(); err != nil {
+			return nil, err
+		}
+		size = data.Size - data.Size%int64(format.BlockSize())
+	} else {
+		var err error
+		format, offset, size, err = audio.ReadWAVHeader(data.Audio, data.Size)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	chunks, err := audio.SplitAtSilence(data.Audio, offset, size, format, audio.SplitOptions{
+		MaxDuration:      cmp.Or(data.MaxChunkDuration, defaultChunkDuration),
+		MaxSize:          audio.MaxFileSize - int64(len(audio.WAVHeader(format, 0))),
+		MinSilence:       data.MinSilence,
+		SilenceThreshold: data.SilenceThreshold,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to split audio: %w", err)
+	}
+
+	before := time.Now()
+	results, err := c.transcribeChunks(ctx, data, format, chunks)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Log.Debug(fmt.Sprintf(
+		"Transcribed %s of audio in %d chunks in %s",
+		chunks[len(chunks)-1].End, len(chunks), time.Since(before),
+	))
+	return mergeTranscriptions(chunks, results), nil
+}
+
+// transcribeChunks transcribes chunks in consecutive runs, one run per worker,
+// carrying the tail of each transcript to the next chunk of the run.
+// First chunks of runs are not prompted with the previous transcript, it's not known yet.
+func (c *Client) transcribeChunks(
+	ctx context.Context, data *audio.LongTranscriptionRequest, format audio.PCMFormat, chunks []audio.Chunk,
+) ([]*audio.Transcription, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	model := cmp.Or(data.Model, models.Whisper1)
+	attempts := max(cmp.Or(data.ChunkAttempts, c.HTTPClient.RequestAttempts), 1)
+	tail := cmp.Or(data.PromptTail, defaultPromptTail)
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		done     int
+		wg       sync.WaitGroup
+	)
+	results := make([]*audio.Transcription, len(chunks))
+	runs := min(cmp.Or(data.Concurrency, defaultConcurrency), len(chunks))
+	for run := range runs {
+		from, to := run*len(chunks)/runs, (run+1)*len(chunks)/runs
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var previous string
+			for i := from; i < to; i++ {
+				req := &audio.TranscriptionRequest{
+					Filename:    "chunk.wav",
+					Model:       model,
+					Language:    data.Language,
+					Prompt:      carryPrompt(data.Prompt, previous, tail),
+					Temperature: data.Temperature,
+				}
+				if isWhisper(model) {
+					req.ResponseFormat = audio.ResponseFormatVerboseJSON
+					req.TimestampGranularities = []string{audio.TimestampSegment}
+				}
+
+				var result *audio.Transcription
+				err := openai.Retry(ctx, attempts, c.HTTPClient.RetryInterval, func() error {
+					req.File = io.MultiReader(
+						bytes.NewReader(audio.WAVHeader(format, chunks[i].Size)),
+						io.NewSectionReader(data.Audio, chunks[i].Offset, chunks[i].Size),
+					)
+					var err error
+					result, err = c.Transcribe(ctx, req)
+					return err
+				})
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("failed to transcribe chunk %d at %s: %w", i, chunks[i].Start, err)
+						cancel()
+					}
+					mu.Unlock()
+					return
+				}
+				results[i] = result
+				done++
+				if data.OnChunk != nil {
+					data.OnChunk(done, len(chunks))
+				}
+				mu.Unlock()
+
+				previous = result.Text
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, ctx.Err()
+}
+
+// carryPrompt returns the prompt followed by up to tail last characters of the previous
+// transcript, starting at a word boundary. Negative tail disables carrying.
+func carryPrompt(prompt, previous string, tail int) string {
+	previous = strings.TrimSpace(previous)
+	if tail < 0 || previous == "" {
+		return prompt
+	}
+
+	runes := []rune(previous)
+	if len(runes) > tail {
+		previous = string(runes[len(runes)-tail:])
+		if i := strings.IndexByte(previous, ' '); i >= 0 {
+			previous = previous[i+1:]
+		}
+	}
+	return strings.TrimSpace(prompt + " " + previous)
+}
+
+// mergeTranscriptions joins transcriptions of chunks, shifting segments to global timestamps.
+// Transcriptions without segments become one segment spanning their chunk.
+func mergeTranscriptions(chunks []audio.Chunk, results []*audio.Transcription) *audio.Transcription {
+	merged := &audio.Transcription{
+		Task:     "transcribe",
+		Language: results[0].Language,
+		Duration: chunks[len(chunks)-1].End.Seconds(),
+	}
+
+	var texts []string
+	for i, r := range results {
+		start := chunks[i].Start.Seconds()
+		text := strings.TrimSpace(r.Text)
+		if text != "" {
+			texts = append(texts, text)
+		}
+
+		if len(r.Segments) == 0 && text != "" {
+			merged.Segments = append(merged.Segments, audio.Segment{
+				ID:    len(merged.Segments),
+				Start: start,
+				End:   chunks[i].End.Seconds(),
+				Text:  text,
+			})
+		}
+		for _, s := range r.Segments {
+			s.ID = len(merged.Segments)
+			s.Start += start
+			s.End += start
+			merged.Segments = append(merged.Segments, s)
+		}
+		for _, w := range r.Words {
+			w.Start += start
+			w.End += start
+			merged.Words = append(merged.Words, w)
+		}
+
+		if u := r.Usage; u != nil {
+			if merged.Usage == nil {
+				merged.Usage = &audio.Usage{Type: u.Type}
+			}
+			merged.Usage.Seconds += u.Seconds
+			merged.Usage.InputTokens += u.InputTokens
+			merged.Usage.OutputTokens += u.OutputTokens
+			merged.Usage.TotalTokens += u.TotalTokens
+			merged.Usage.InputTokenDetails.TextTokens += u.InputTokenDetails.TextTokens
+			merged.Usage.InputTokenDetails.AudioTokens += u.InputTokenDetails.AudioTokens
+		}
+	}
+	merged.Text = strings.Join(texts, " ")
+	return merged
+}
@@ -0,0 +1,249 @@
+package inaudio
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/audio"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+)
+
+// testFormat is 16 kHz mono 16-bit PCM.
+var testFormat = audio.PCMFormat{SampleRate: 16000, Channels: 1, BitsPerSample: 16}
+
+// makeWAV returns a WAV file of alternating tone and silence sections of given durations,
+// starting with a tone.
+func makeWAV(sections ...time.Duration) []byte {
+	var samples bytes.Buffer
+	for i, d := range sections {
+		n := int(d.Seconds() * float64(testFormat.SampleRate))
+		for j := range n {
+			var v int16
+			if i%2 == 0 {
+				v = int16(16000 * math.Sin(2*math.Pi*440*float64(j)/float64(testFormat.SampleRate)))
+			}
+			_ = binary.Write(&samples, binary.LittleEndian, v)
+		}
+	}
+	return append(audio.WAVHeader(testFormat, int64(samples.Len())), samples.Bytes()...)
+}
+
+func TestSplitAtSilence(t *testing.T) {
+	t.Parallel()
+
+	wav := makeWAV(3*time.Second, time.Second, 3*time.Second, time.Second, 3*time.Second)
+	format, offset, size, err := audio.ReadWAVHeader(bytes.NewReader(wav), int64(len(wav)))
+	require.NoError(t, err)
+	require.Equal(t, testFormat, format)
+	require.EqualValues(t, 44, offset)
+	require.Equal(t, 11*time.Second, format.Duration(size))
+
+	chunks, err := audio.SplitAtSilence(bytes.NewReader(wav), offset, size, format, audio.SplitOptions{
+		MaxDuration: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	require.Len(t, chunks, 3)
+	require.Equal(t, 3500*time.Millisecond, chunks[0].End)
+	require.Equal(t, chunks[0].End, chunks[1].Start)
+	require.Equal(t, 7500*time.Millisecond, chunks[1].End)
+	require.Equal(t, 11*time.Second, chunks[2].End)
+	require.Equal(t, offset+chunks[0].Size, chunks[1].Offset)
+
+	// without pauses, chunks are cut at the quietest moments within the size limit
+	tone := makeWAV(3 * time.Second)
+	chunks, err = audio.SplitAtSilence(bytes.NewReader(tone), 44, int64(len(tone)-44), testFormat, audio.SplitOptions{
+		MaxDuration: time.Minute,
+		MaxSize:     32000,
+	})
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(chunks), 3)
+	for i, c := range chunks {
+		require.LessOrEqual(t, c.Size, int64(32000))
+		if i > 0 {
+			require.Equal(t, chunks[i-1].Offset+chunks[i-1].Size, c.Offset)
+		}
+	}
+	require.Equal(t, 3*time.Second, chunks[len(chunks)-1].End)
+}
+
+func TestTranscribeLong(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/audio/transcriptions", r.URL.Path)
+		assert.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, models.Whisper1, r.FormValue("model"))
+		assert.Equal(t, audio.ResponseFormatVerboseJSON, r.FormValue("response_format"))
+
+		n := calls.Add(1)
+		if n == 1 {
+			assert.Equal(t, "Standup.", r.FormValue("prompt"))
+		} else {
+			assert.Equal(t, fmt.Sprintf("Standup. Part %d.", n-1), r.FormValue("prompt"))
+		}
+
+		file, header, err := r.FormFile("file")
+		assert.NoError(t, err)
+		assert.Equal(t, "chunk.wav", header.Filename)
+		b, err := io.ReadAll(file)
+		assert.NoError(t, err)
+		format, _, size, err := audio.ReadWAVHeader(bytes.NewReader(b), int64(len(b)))
+		assert.NoError(t, err)
+		duration := format.Duration(size).Seconds()
+
+		fmt.Fprintf(w, `{"task":"transcribe","language":"english","duration":%g,"text":"Part %d.",`+
+			`"segments":[{"id":0,"start":0.5,"end":%g,"text":" Part %d."}],`+
+			`"usage":{"type":"duration","seconds":%d}}`, duration, n, duration-0.5, n, int(math.Ceil(duration)))
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	wav := makeWAV(3*time.Second, time.Second, 3*time.Second, time.Second, 3*time.Second)
+	var progress []int
+	result, err := client.TranscribeLong(context.Background(), &audio.LongTranscriptionRequest{
+		Audio:            bytes.NewReader(wav),
+		Size:             int64(len(wav)),
+		Prompt:           "Standup.",
+		MaxChunkDuration: 5 * time.Second,
+		Concurrency:      1,
+		OnChunk: func(done, total int) {
+			assert.Equal(t, 3, total)
+			progress = append(progress, done)
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3}, progress)
+	require.Equal(t, "Part 1. Part 2. Part 3.", result.Text)
+	require.Equal(t, "english", result.Language)
+	require.Equal(t, 11.0, result.Duration)
+	require.EqualValues(t, 12, result.Usage.Seconds)
+
+	require.Len(t, result.Segments, 3)
+	for i, s := range result.Segments {
+		require.Equal(t, i, s.ID)
+	}
+	require.InDelta(t, 4.0, result.Segments[1].Start, 1e-9)
+	require.InDelta(t, 7.0, result.Segments[1].End, 1e-9)
+	require.Equal(t, "1\n00:00:00,500 --> 00:00:03,000\nPart 1.\n\n"+
+		"2\n00:00:04,000 --> 00:00:07,000\nPart 2.\n\n"+
+		"3\n00:00:08,000 --> 00:00:10,500\nPart 3.\n\n", result.SRT())
+	require.Contains(t, result.VTT(), "00:00:08.000 --> 00:00:10.500\nPart 3.")
+}
+
+func TestTranscribeLongErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"error":{"message":"boom","type":"server_error"}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	config.HTTPClient.RetryInterval = time.Millisecond
+	client := NewClient(config)
+
+	wav := makeWAV(2 * time.Second)
+	_, err := client.TranscribeLong(context.Background(), &audio.LongTranscriptionRequest{
+		Audio:         bytes.NewReader(wav),
+		Size:          int64(len(wav)),
+		ChunkAttempts: 2,
+	})
+	require.ErrorContains(t, err, "failed to transcribe chunk 0")
+	require.EqualValues(t, 2, calls.Load())
+
+	_, err = client.TranscribeLong(context.Background(), &audio.LongTranscriptionRequest{
+		Audio: bytes.NewReader([]byte("not a wav file")),
+		Size:  14,
+	})
+	require.Error(t, err)
+	require.EqualValues(t, 2, calls.Load())
+}
+
+func TestTranscribeLongRetries(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		switch r.FormValue("prompt") {
+		case "invalid":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"message":"bad prompt","type":"invalid_request_error"}}`)
+		case "limited":
+			if n == 1 {
+				w.Header().Set("retry-after-ms", "10")
+				w.WriteHeader(http.StatusTooManyRequests)
+				fmt.Fprint(w, `{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`)
+				return
+			}
+			fmt.Fprint(w, `{"text":"Hi."}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":{"message":"boom","type":"server_error"}}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	// the interval is long enough to fail the test if the delay asked by the server is ignored
+	config.HTTPClient.RetryInterval = time.Hour
+	client := NewClient(config)
+
+	wav := makeWAV(2 * time.Second)
+	transcribe := func(ctx context.Context, prompt string) error {
+		_, err := client.TranscribeLong(ctx, &audio.LongTranscriptionRequest{
+			Audio:         bytes.NewReader(wav),
+			Size:          int64(len(wav)),
+			Model:         models.GPT4oTranscribe,
+			Prompt:        prompt,
+			ChunkAttempts: 3,
+		})
+		return err
+	}
+
+	// rate limits are retried after the delay asked by the server
+	require.NoError(t, transcribe(context.Background(), "limited"))
+	require.EqualValues(t, 2, calls.Load())
+
+	// validation errors are not retried
+	calls.Store(0)
+	require.ErrorContains(t, transcribe(context.Background(), "invalid"), "bad prompt")
+	require.EqualValues(t, 1, calls.Load())
+
+	// waiting for the next attempt ends with the context
+	calls.Store(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, transcribe(ctx, "failing"), context.DeadlineExceeded)
+	require.EqualValues(t, 1, calls.Load())
+}
+
+func TestCarryPrompt(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "Glossary.", carryPrompt("Glossary.", "", 200))
+	require.Equal(t, "Glossary.", carryPrompt("Glossary.", "some text", -1))
+	require.Equal(t, "the end.", carryPrompt("", "cut at the end.", 10))
+	require.Equal(t, "Glossary. whole text", carryPrompt("Glossary.", " whole text ", 200))
+}
@@ -0,0 +1,111 @@
+package inaudio
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/unkn0wncode/openai/audio"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+)
+
+// defaultSpeechLimit is the input limit in characters for models without known limits.
+const defaultSpeechLimit = 4096
+
+// speechFormats are supported formats of generated speech.
+var speechFormats = []string{
+	audio.SpeechFormatMP3,
+	audio.SpeechFormatOpus,
+	audio.SpeechFormatAAC,
+	audio.SpeechFormatFLAC,
+	audio.SpeechFormatWAV,
+	audio.SpeechFormatPCM,
+}
+
+// speechRequest is the request body for speech generation.
+type speechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	Instructions   string  `json:"instructions,omitempty"`
+	ResponseFormat string  `json:"response_format,omitempty"`
+	Speed          float64 `json:"speed,omitempty"`
+}
+
+// Speech generates audio from text. The audio is streamed as it's generated.
+func (c *Client) Speech(ctx context.Context, data *audio.SpeechRequest) (*audio.Speech, error) {
+	if data == nil {
+		return nil, errors.New("speech request is nil")
+	}
+
+	model := cmp.Or(data.Model, models.DefaultTTS)
+	pricing, known := models.GetTTSPricing(model)
+	limit := defaultSpeechLimit
+	if known && pricing.LimitCharacters > 0 {
+		limit = pricing.LimitCharacters
+	}
+	characters := utf8.RuneCountInString(data.Input)
+
+	switch {
+	case data.Input == "":
+		return nil, errors.New("input is required")
+	case characters > limit:
+		return nil, fmt.Errorf("input has %d characters, limit of model %s is %d", characters, model, limit)
+	case data.Voice == "":
+		return nil, errors.New("voice is required")
+	case data.ResponseFormat != "" && !slices.Contains(speechFormats, data.ResponseFormat):
+		return nil, fmt.Errorf("unsupported response format '%s', supported: %s", data.ResponseFormat, strings.Join(speechFormats, ", "))
+	case data.Speed != 0 && (data.Speed < 0.25 || data.Speed > 4):
+		return nil, fmt.Errorf("speed must be from 0.25 to 4.0, got %v", data.Speed)
+	case data.Instructions != "" && strings.HasPrefix(model, "tts-1"):
+		return nil, fmt.Errorf("instructions are not supported by model %s", model)
+	}
+
+	b, err := openai.Marshal(speechRequest{
+		Model:          model,
+		Input:          data.Input,
+		Voice:          data.Voice,
+		Instructions:   data.Instructions,
+		ResponseFormat: data.ResponseFormat,
+		Speed:          data.Speed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseAPI+"v1/audio/speech", bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.AddHeaders(req)
+
+	// the timeout of the client covers reading the body too, which lasts as long as the audio is generated
+	resp, err := c.HTTPClient.WithoutTimeout().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, openai.NewAPIError(resp)
+	}
+
+	var cost float64
+	if known {
+		cost = float64(characters) * pricing.PricePerCharacter
+	} else {
+		c.Log.Warn(fmt.Sprintf("No pricing for found model '%s'", model))
+	}
+
+	return &audio.Speech{
+		Audio:       resp.Body,
+		ContentType: resp.Header.Get("Content-Type"),
+		Model:       model,
+		Cost:        cost,
+	}, nil
+}
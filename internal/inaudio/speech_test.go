@@ -0,0 +1,99 @@
+package inaudio
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/audio"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+)
+
+func TestSpeech(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/audio/speech", r.URL.Path)
+
+		var req map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, map[string]any{
+			"model":           models.DefaultTTS,
+			"input":           "Hello, wörld",
+			"voice":           audio.VoiceCoral,
+			"instructions":    "Cheerful",
+			"response_format": audio.SpeechFormatOpus,
+			"speed":           1.5,
+		}, req)
+
+		w.Header().Set("Content-Type", "audio/ogg")
+		flusher := w.(http.Flusher)
+		for i, chunk := range []string{"chunk1", "chunk2"} {
+			if i > 0 {
+				// the stream outlasts the overall timeout of the client
+				time.Sleep(100 * time.Millisecond)
+			}
+			_, _ = io.WriteString(w, chunk)
+			flusher.Flush()
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	config.HTTPClient.Timeout = 20 * time.Millisecond
+	client := NewClient(config)
+
+	speech, err := client.Speech(context.Background(), &audio.SpeechRequest{
+		Input:          "Hello, wörld",
+		Voice:          audio.VoiceCoral,
+		Instructions:   "Cheerful",
+		ResponseFormat: audio.SpeechFormatOpus,
+		Speed:          1.5,
+	})
+	require.NoError(t, err)
+	defer speech.Audio.Close()
+
+	b, err := io.ReadAll(speech.Audio)
+	require.NoError(t, err)
+	require.Equal(t, "chunk1chunk2", string(b))
+	require.Equal(t, "audio/ogg", speech.ContentType)
+
+	// pricing of the latest snapshot is used for 12 characters
+	pricing, ok := models.GetTTSPricing(models.DefaultTTS)
+	require.True(t, ok)
+	require.InDelta(t, 12*pricing.PricePerCharacter, speech.Cost, 1e-12)
+}
+
+func TestSpeechValidation(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(openai.NewConfig("test"))
+	tests := []struct {
+		name string
+		req  audio.SpeechRequest
+		err  string
+	}{
+		{"no input", audio.SpeechRequest{Voice: audio.VoiceAlloy}, "input is required"},
+		{"no voice", audio.SpeechRequest{Input: "hi"}, "voice is required"},
+		{"long input", audio.SpeechRequest{Input: strings.Repeat("a", 16385), Voice: audio.VoiceAlloy}, "limit"},
+		{"format", audio.SpeechRequest{Input: "hi", Voice: audio.VoiceAlloy, ResponseFormat: "ogg"}, "unsupported response format"},
+		{"speed", audio.SpeechRequest{Input: "hi", Voice: audio.VoiceAlloy, Speed: 5}, "speed"},
+		{"instructions", audio.SpeechRequest{Input: "hi", Voice: audio.VoiceAlloy, Model: models.TTS1HD, Instructions: "x"}, "not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := client.Speech(context.Background(), &tt.req)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package inaudio
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unkn0wncode/openai/audio"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+)
+
+// maxSpeakers is the maximum number of known speakers of diarization models.
+const maxSpeakers = 4
+
+// errFileTooLarge is returned when audio exceeds audio.MaxFileSize.
+var errFileTooLarge = fmt.Errorf("audio file exceeds the limit of %d MB", audio.MaxFileSize>>20)
+
+// isWhisper reports whether the model is a Whisper model, which supports all response formats
+// and timestamps but not streaming.
+func isWhisper(model string) bool {
+	return strings.HasPrefix(model, "whisper")
+}
+
+// isDiarize reports whether the model identifies speakers.
+func isDiarize(model string) bool {
+	return strings.Contains(model, "-diarize")
+}
+
+// transcriptionFormats returns response formats supported by the model.
+func transcriptionFormats(model string) []string {
+	switch {
+	case isWhisper(model):
+		return []string{
+			audio.ResponseFormatJSON, audio.ResponseFormatText, audio.ResponseFormatSRT,
+			audio.ResponseFormatVTT, audio.ResponseFormatVerboseJSON,
+		}
+	case isDiarize(model):
+		return []string{audio.ResponseFormatJSON, audio.ResponseFormatText, audio.ResponseFormatDiarizedJSON}
+	default:
+		return []string{audio.ResponseFormatJSON, audio.ResponseFormatText}
+	}
+}
+
+// Transcribe transcribes audio into the input language.
+func (c *Client) Transcribe(ctx context.Context, data *audio.TranscriptionRequest) (*audio.Transcription, error) {
+	fields, err := transcriptionFields(data, false)
+	if err != nil {
+		return nil, err
+	}
+
+	before := time.Now()
+	result, err := c.postAudio(ctx, "v1/audio/transcriptions", fields, data.File, data.Filename)
+	if err != nil {
+		return nil, err
+	}
+	c.logUsage(fields.Get("model"), result.Usage, before)
+	return result, nil
+}
+
+// TranscribeStream transcribes audio and streams the text as it's recognized.
+func (c *Client) TranscribeStream(ctx context.Context, data *audio.TranscriptionRequest) (*audio.TranscriptionStream, error) {
+	fields, err := transcriptionFields(data, true)
+	if err != nil {
+		return nil, err
+	}
+	model := fields.Get("model")
+
+	// the stream aborts the request when it's closed
+	ctx, cancel := context.WithCancel(ctx)
+	before := time.Now()
+	resp, err := c.PostMultipart(ctx, "v1/audio/transcriptions", fields, audioFile(data.File, data.Filename))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := openai.CheckStreamResponse(resp); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	items := openai.StreamSSE(ctx, resp.Body, func(event *openai.SSEEvent) (any, error) {
+		var payload struct {
+			Type     string          `json:"type"`
+			Delta    string          `json:"delta"`
+			Text     string          `json:"text"`
+			Logprobs []audio.Logprob `json:"logprobs"`
+			Usage    *audio.Usage    `json:"usage"`
+			ID       string          `json:"id"`
+			Start    float64         `json:"start"`
+			End      float64         `json:"end"`
+			Speaker  string          `json:"speaker"`
+			Error    *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(event.Data, &payload); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal transcription event: %w", err)
+		}
+		if payload.Error != nil {
+			return nil, fmt.Errorf("got API error: %s", payload.Error.Message)
+		}
+
+		e := audio.TranscriptionEvent{
+			Type:     payload.Type,
+			Delta:    payload.Delta,
+			Text:     payload.Text,
+			Logprobs: payload.Logprobs,
+			Usage:    payload.Usage,
+		}
+		if payload.Type == audio.EventTextSegment {
+			e.Segment = &audio.DiarizedSegment{
+				ID:      payload.ID,
+				Start:   payload.Start,
+				End:     payload.End,
+				Text:    payload.Text,
+				Speaker: payload.Speaker,
+			}
+			e.Text = ""
+		}
+		if payload.Type == audio.EventTextDone {
+			c.logUsage(model, payload.Usage, before)
+		}
+		return e, nil
+	}, nil)
+
+	return audio.NewTranscriptionStream(ctx, items, cancel), nil
+}
+
+// Translate transcribes audio into English.
+func (c *Client) Translate(ctx context.Context, data *audio.TranslationRequest) (*audio.Transcription, error) {
+	switch {
+	case data == nil:
+		return nil, errors.New("translation request is nil")
+	case data.File == nil:
+		return nil, errors.New("file is required")
+	case data.Filename == "":
+		return nil, errors.New("filename is required")
+	}
+
+	model := cmp.Or(data.Model, models.DefaultTranslation)
+	format := cmp.Or(data.ResponseFormat, audio.ResponseFormatJSON)
+	if formats := transcriptionFormats(models.Whisper1); !slices.Contains(formats, format) {
+		return nil, fmt.Errorf("unsupported response format '%s', supported: %s", format, strings.Join(formats, ", "))
+	}
+
+	fields := url.Values{
+		"model":           {model},
+		"response_format": {format},
+	}
+	if data.Prompt != "" {
+		fields.Set("prompt", data.Prompt)
+	}
+	if data.Temperature != 0 {
+		fields.Set("temperature", strconv.FormatFloat(data.Temperature, 'f', -1, 64))
+	}
+
+	return c.postAudio(ctx, "v1/audio/translations", fields, data.File, data.Filename)
+}
+
+// transcriptionFields validates the request and returns its multipart fields.
+func transcriptionFields(data *audio.TranscriptionRequest, stream bool) (url.Values, error) {
+	switch {
+	case data == nil:
+		return nil, errors.New("transcription request is nil")
+	case data.File == nil:
+		return nil, errors.New("file is required")
+	case data.Filename == "":
+		return nil, errors.New("filename is required")
+	}
+
+	model := cmp.Or(data.Model, models.DefaultTranscription)
+	format := cmp.Or(data.ResponseFormat, audio.ResponseFormatJSON)
+	formats := transcriptionFormats(model)
+
+	switch {
+	case !slices.Contains(formats, format):
+		return nil, fmt.Errorf("model %s doesn't support response format '%s', supported: %s", model, format, strings.Join(formats, ", "))
+	case stream && isWhisper(model):
+		return nil, fmt.Errorf("model %s doesn't support streaming", model)
+	case len(data.TimestampGranularities) > 0 && format != audio.ResponseFormatVerboseJSON:
+		return nil, errors.New("timestamp granularities require verbose_json format")
+	case slices.Contains(data.Include, audio.IncludeLogprobs) && (format != audio.ResponseFormatJSON || isWhisper(model) || isDiarize(model)):
+		return nil, fmt.Errorf("logprobs require json format and are not supported by model %s", model)
+	case data.Prompt != "" && isDiarize(model):
+		return nil, fmt.Errorf("model %s doesn't support prompts", model)
+	case (len(data.KnownSpeakerNames) > 0 || len(data.KnownSpeakerReferences) > 0) && !isDiarize(model):
+		return nil, fmt.Errorf("model %s doesn't support known speakers", model)
+	case len(data.KnownSpeakerNames) != len(data.KnownSpeakerReferences):
+		return nil, fmt.Errorf("got %d known speaker names and %d references", len(data.KnownSpeakerNames), len(data.KnownSpeakerReferences))
+	case len(data.KnownSpeakerNames) > maxSpeakers:
+		return nil, fmt.Errorf("up to %d known speakers are supported, got %d", maxSpeakers, len(data.KnownSpeakerNames))
+	}
+
+	fields := url.Values{
+		"model":           {model},
+		"response_format": {format},
+	}
+	if data.Language != "" {
+		fields.Set("language", data.Language)
+	}
+	if data.Prompt != "" {
+		fields.Set("prompt", data.Prompt)
+	}
+	if data.Temperature != 0 {
+		fields.Set("temperature", strconv.FormatFloat(data.Temperature, 'f', -1, 64))
+	}
+	if len(data.TimestampGranularities) > 0 {
+		fields["timestamp_granularities[]"] = data.TimestampGranularities
+	}
+	if len(data.Include) > 0 {
+		fields["include[]"] = data.Include
+	}
+	if len(data.KnownSpeakerNames) > 0 {
+		fields["known_speaker_names[]"] = data.KnownSpeakerNames
+		fields["known_speaker_references[]"] = data.KnownSpeakerReferences
+	}
+	if cs := data.ChunkingStrategy; cs != nil {
+		if cs.Type == "auto" {
+			fields.Set("chunking_strategy", "auto")
+		} else {
+			b, err := json.Marshal(cs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal chunking strategy: %w", err)
+			}
+			fields.Set("chunking_strategy", string(b))
+		}
+	}
+	if stream {
+		fields.Set("stream", "true")
+	}
+	return fields, nil
+}
+
+// postAudio sends the audio with fields and decodes the transcription according to the response format.
+func (c *Client) postAudio(ctx context.Context, endpoint string, fields url.Values, file io.Reader, filename string) (*audio.Transcription, error) {
+	resp, err := c.PostMultipart(ctx, endpoint, fields, audioFile(file, filename))
+	if err != nil {
+		if errors.Is(err, errFileTooLarge) {
+			return nil, errFileTooLarge
+		}
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, openai.NewAPIError(resp)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	switch format := fields.Get("response_format"); format {
+	case audio.ResponseFormatText, audio.ResponseFormatSRT, audio.ResponseFormatVTT:
+		return &audio.Transcription{Text: string(body)}, nil
+	default:
+		return decodeTranscription(body, format)
+	}
+}
+
+// decodeTranscription decodes a transcription in one of JSON formats.
+func decodeTranscription(body []byte, format string) (*audio.Transcription, error) {
+	var result struct {
+		audio.Transcription
+		Segments json.RawMessage `json:"segments"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode transcription: %w", err)
+	}
+
+	t := result.Transcription
+	if len(result.Segments) > 0 && string(result.Segments) != "null" {
+		var err error
+		if format == audio.ResponseFormatDiarizedJSON {
+			err = json.Unmarshal(result.Segments, &t.SpeakerSegments)
+		} else {
+			err = json.Unmarshal(result.Segments, &t.Segments)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode segments: %w", err)
+		}
+	}
+	return &t, nil
+}
+
+// logUsage logs usage of a transcription.
+func (c *Client) logUsage(model string, usage *audio.Usage, before time.Time) {
+	switch {
+	case usage == nil:
+		c.Log.Debug(fmt.Sprintf("Transcribed audio with model '%s' in %s", model, time.Since(before)))
+	case usage.Type == "duration":
+		c.Log.Info(fmt.Sprintf("Transcribed %.1fs of audio with model '%s' in %s", usage.Seconds, model, time.Since(before)))
+	default:
+		c.Log.Info(fmt.Sprintf(
+			"Consumed OpenAI tokens: %d + %d = %d on model '%s' in %s",
+			usage.InputTokens, usage.OutputTokens, usage.TotalTokens, model, time.Since(before),
+		))
+	}
+}
+
+// audioFile returns the multipart file of audio content limited to audio.MaxFileSize.
+func audioFile(file io.Reader, filename string) openai.MultipartFile {
+	return openai.MultipartFile{
+		Field:    "file",
+		Filename: filename,
+		Content:  &openai.LimitedReader{R: file, N: audio.MaxFileSize, Err: errFileTooLarge},
+	}
+}
@@ -0,0 +1,254 @@
+package inaudio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/audio"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+)
+
+// newAudioServer starts a fake Audio API that checks the uploaded file and answers with given handler.
+func newAudioServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		assert.NoError(t, err)
+		b, err := io.ReadAll(file)
+		assert.NoError(t, err)
+		assert.Equal(t, "audio.mp3", header.Filename)
+		assert.Equal(t, "RIFF", string(b))
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	return NewClient(config)
+}
+
+func TestTranscribe(t *testing.T) {
+	t.Parallel()
+
+	client := newAudioServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/audio/transcriptions", r.URL.Path)
+		assert.Equal(t, models.Whisper1, r.FormValue("model"))
+		assert.Equal(t, "en", r.FormValue("language"))
+		assert.Equal(t, []string{"word", "segment"}, r.MultipartForm.Value["timestamp_granularities[]"])
+		fmt.Fprint(w, `{"task":"transcribe","language":"english","duration":2.5,"text":"Hello there.",`+
+			`"segments":[{"id":0,"seek":0,"start":0,"end":2.5,"text":"Hello there.","tokens":[1,2],"avg_logprob":-0.2}],`+
+			`"words":[{"word":"Hello","start":0,"end":1},{"word":"there","start":1.2,"end":2.4}],`+
+			`"usage":{"type":"duration","seconds":3}}`)
+	})
+
+	result, err := client.Transcribe(context.Background(), &audio.TranscriptionRequest{
+		File:                   strings.NewReader("RIFF"),
+		Filename:               "audio.mp3",
+		Model:                  models.Whisper1,
+		Language:               "en",
+		ResponseFormat:         audio.ResponseFormatVerboseJSON,
+		TimestampGranularities: []string{audio.TimestampWord, audio.TimestampSegment},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Hello there.", result.Text)
+	require.Equal(t, 2.5, result.Duration)
+	require.Len(t, result.Segments, 1)
+	require.Equal(t, -0.2, result.Segments[0].AvgLogprob)
+	require.Len(t, result.Words, 2)
+	require.Equal(t, "duration", result.Usage.Type)
+	require.EqualValues(t, 3, result.Usage.Seconds)
+}
+
+func TestTranscribeDiarizedAndText(t *testing.T) {
+	t.Parallel()
+
+	client := newAudioServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("response_format") {
+		case audio.ResponseFormatDiarizedJSON:
+			assert.Equal(t, "auto", r.FormValue("chunking_strategy"))
+			assert.Equal(t, []string{"agent"}, r.MultipartForm.Value["known_speaker_names[]"])
+			fmt.Fprint(w, `{"task":"transcribe","duration":4,"text":"Hi. Hello.","segments":[`+
+				`{"type":"transcript.text.segment","id":"seg_0","start":0,"end":1,"text":"Hi.","speaker":"agent"},`+
+				`{"type":"transcript.text.segment","id":"seg_1","start":1.5,"end":4,"text":"Hello.","speaker":"A"}],`+
+				`"usage":{"type":"tokens","input_tokens":10,"output_tokens":4,"total_tokens":14,"input_token_details":{"audio_tokens":10}}}`)
+		case audio.ResponseFormatSRT:
+			assert.Equal(t, "/v1/audio/translations", r.URL.Path)
+			fmt.Fprint(w, "1\n00:00:00,000 --> 00:00:01,000\nHi.\n")
+		}
+	})
+
+	result, err := client.Transcribe(context.Background(), &audio.TranscriptionRequest{
+		File:                   strings.NewReader("RIFF"),
+		Filename:               "audio.mp3",
+		Model:                  models.GPT4oTranscribeDiarize,
+		ResponseFormat:         audio.ResponseFormatDiarizedJSON,
+		ChunkingStrategy:       &audio.ChunkingStrategy{Type: "auto"},
+		KnownSpeakerNames:      []string{"agent"},
+		KnownSpeakerReferences: []string{"data:audio/wav;base64,AAAA"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []audio.DiarizedSegment{
+		{ID: "seg_0", Start: 0, End: 1, Text: "Hi.", Speaker: "agent"},
+		{ID: "seg_1", Start: 1.5, End: 4, Text: "Hello.", Speaker: "A"},
+	}, result.SpeakerSegments)
+	require.Equal(t, 10, result.Usage.InputTokenDetails.AudioTokens)
+
+	translation, err := client.Translate(context.Background(), &audio.TranslationRequest{
+		File:           strings.NewReader("RIFF"),
+		Filename:       "audio.mp3",
+		ResponseFormat: audio.ResponseFormatSRT,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "1\n00:00:00,000 --> 00:00:01,000\nHi.\n", translation.Text)
+}
+
+func TestTranscribeStream(t *testing.T) {
+	t.Parallel()
+
+	client := newAudioServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.FormValue("stream"))
+		assert.Equal(t, []string{"logprobs"}, r.MultipartForm.Value["include[]"])
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"type\":\"transcript.text.delta\",\"delta\":\"Hel\",\"logprobs\":[{\"token\":\"Hel\",\"logprob\":-0.1}]}\n\n")
+		fmt.Fprint(w, "data: {\"type\":\"transcript.text.delta\",\"delta\":\"lo\",\"logprobs\":[{\"token\":\"lo\",\"logprob\":-0.2}]}\n\n")
+		fmt.Fprint(w, "data: {\"type\":\"transcript.text.done\",\"text\":\"Hello\",\"usage\":{\"type\":\"tokens\",\"input_tokens\":5,\"output_tokens\":2,\"total_tokens\":7}}\n\n")
+	})
+
+	stream, err := client.TranscribeStream(context.Background(), &audio.TranscriptionRequest{
+		File:     strings.NewReader("RIFF"),
+		Filename: "audio.mp3",
+		Include:  []string{audio.IncludeLogprobs},
+	})
+	require.NoError(t, err)
+
+	require.True(t, stream.Next())
+	require.Equal(t, "Hel", stream.Event().Delta)
+	require.Equal(t, "Hel", stream.Text())
+
+	result, err := stream.Transcription()
+	require.NoError(t, err)
+	require.Equal(t, "Hello", result.Text)
+	require.Len(t, result.Logprobs, 2)
+	require.Equal(t, 7, result.Usage.TotalTokens)
+}
+
+func TestTranscribeStreamCancelled(t *testing.T) {
+	t.Parallel()
+
+	client := newAudioServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"type\":\"transcript.text.delta\",\"delta\":\"Hel\"}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.TranscribeStream(ctx, &audio.TranscriptionRequest{
+		File:     strings.NewReader("RIFF"),
+		Filename: "audio.mp3",
+	})
+	require.NoError(t, err)
+
+	require.True(t, stream.Next())
+	cancel()
+	result, err := stream.Transcription()
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, "Hel", result.Text)
+}
+
+func TestTranscribeStreamClose(t *testing.T) {
+	t.Parallel()
+
+	aborted := make(chan struct{})
+	client := newAudioServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"type\":\"transcript.text.delta\",\"delta\":\"Hel\"}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+		close(aborted)
+	})
+
+	stream, err := client.TranscribeStream(context.Background(), &audio.TranscriptionRequest{
+		File:     strings.NewReader("RIFF"),
+		Filename: "audio.mp3",
+	})
+	require.NoError(t, err)
+
+	require.True(t, stream.Next())
+	stream.Close()
+	require.False(t, stream.Next())
+	require.NoError(t, stream.Err())
+
+	// the request is aborted even though the context of the caller is still alive
+	select {
+	case <-aborted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close must abort the request")
+	}
+}
+
+func TestTranscribeValidation(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(openai.NewConfig("test"))
+	file := strings.NewReader("RIFF")
+	tests := []struct {
+		name   string
+		req    audio.TranscriptionRequest
+		stream bool
+		err    string
+	}{
+		{"no file", audio.TranscriptionRequest{Filename: "a.mp3"}, false, "file is required"},
+		{"format", audio.TranscriptionRequest{File: file, Filename: "a.mp3", ResponseFormat: audio.ResponseFormatSRT}, false, "doesn't support response format"},
+		{"whisper stream", audio.TranscriptionRequest{File: file, Filename: "a.mp3", Model: models.Whisper1}, true, "doesn't support streaming"},
+		{"timestamps", audio.TranscriptionRequest{File: file, Filename: "a.mp3", Model: models.Whisper1, TimestampGranularities: []string{"word"}}, false, "verbose_json"},
+		{"logprobs", audio.TranscriptionRequest{File: file, Filename: "a.mp3", Model: models.Whisper1, Include: []string{"logprobs"}}, false, "logprobs"},
+		{"diarize prompt", audio.TranscriptionRequest{File: file, Filename: "a.mp3", Model: models.GPT4oTranscribeDiarize, Prompt: "x"}, false, "prompts"},
+		{"speakers", audio.TranscriptionRequest{File: file, Filename: "a.mp3", KnownSpeakerNames: []string{"a"}, KnownSpeakerReferences: []string{"b"}}, false, "known speakers"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var err error
+			if tt.stream {
+				_, err = client.TranscribeStream(context.Background(), &tt.req)
+			} else {
+				_, err = client.Transcribe(context.Background(), &tt.req)
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestTranscribeTooLarge(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	_, err := client.Transcribe(context.Background(), &audio.TranscriptionRequest{
+		File:     bytes.NewReader(make([]byte, audio.MaxFileSize+1)),
+		Filename: "audio.mp3",
+	})
+	require.ErrorIs(t, err, errFileTooLarge)
+}
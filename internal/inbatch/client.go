@@ -0,0 +1,408 @@
+// Package inbatch provides a wrapper for the OpenAI Batch API.
+package inbatch
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unkn0wncode/openai/batch"
+	"github.com/unkn0wncode/openai/files"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/internal/inchat"
+	"github.com/unkn0wncode/openai/internal/inembedding"
+	"github.com/unkn0wncode/openai/internal/infiles"
+	"github.com/unkn0wncode/openai/internal/inresponses"
+)
+
+// Client is the client for the Batch API.
+type Client struct {
+	*openai.Config
+
+	responses *inresponses.Client
+	chat      *inchat.Client
+	embedding *inembedding.Client
+	files     *infiles.Client
+}
+
+// NewClient creates a new client for the Batch API.
+func NewClient(config *openai.Config) *Client {
+	return &Client{
+		Config:    config,
+		responses: inresponses.NewClient(config),
+		chat:      inchat.NewClient(config),
+		embedding: inembedding.NewClient(config),
+		files:     infiles.NewClient(config),
+	}
+}
+
+// interface compliance checks
+var _ batch.Service = (*Client)(nil)
+
+const (
+	// defaultPollInterval is time between polls in Run when not specified.
+	defaultPollInterval = time.Minute
+	// maxLineSize is the maximum size of a line of output files.
+	maxLineSize = 64 << 20
+)
+
+// NewInput creates an empty input file builder.
+func (c *Client) NewInput() batch.Input {
+	return &input{client: c, ids: map[string]bool{}, maxSize: batch.MaxInputSize}
+}
+
+// Create creates a batch from an uploaded input file.
+func (c *Client) Create(data *batch.CreateRequest) (*batch.Batch, error) {
+	switch {
+	case data == nil:
+		return nil, errors.New("create request is nil")
+	case data.InputFileID == "":
+		return nil, errors.New("input file ID is required")
+	case data.Endpoint == "":
+		return nil, errors.New("endpoint is required")
+	}
+
+	payload := map[string]any{
+		"input_file_id":     data.InputFileID,
+		"endpoint":          data.Endpoint,
+		"completion_window": batch.CompletionWindow,
+	}
+	if len(data.Metadata) > 0 {
+		payload["metadata"] = data.Metadata
+	}
+	if data.OutputExpiresAfter != 0 {
+		payload["output_expires_after"] = map[string]any{
+			"anchor":  "created_at",
+			"seconds": int(data.OutputExpiresAfter / time.Second),
+		}
+	}
+
+	b, err := c.do(context.Background(), http.MethodPost, "v1/batches", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBatch(b)
+}
+
+// Retrieve returns a batch by ID.
+func (c *Client) Retrieve(id string) (*batch.Batch, error) {
+	return c.retrieve(context.Background(), id)
+}
+
+// retrieve returns a batch by ID with given context.
+func (c *Client) retrieve(ctx context.Context, id string) (*batch.Batch, error) {
+	if id == "" {
+		return nil, errors.New("batch ID is empty")
+	}
+
+	b, err := c.do(ctx, http.MethodGet, "v1/batches/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBatch(b)
+}
+
+// Cancel cancels a batch, it gets status "cancelling" until running requests finish.
+func (c *Client) Cancel(id string) (*batch.Batch, error) {
+	if id == "" {
+		return nil, errors.New("batch ID is empty")
+	}
+
+	b, err := c.do(context.Background(), http.MethodPost, "v1/batches/"+url.PathEscape(id)+"/cancel", nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBatch(b)
+}
+
+// List returns a page of batches.
+func (c *Client) List(opts *batch.ListOptions) (*batch.BatchList, error) {
+	values := url.Values{}
+	if opts != nil {
+		if opts.Limit > 0 {
+			values.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.After != "" {
+			values.Set("after", opts.After)
+		}
+	}
+
+	endpoint := "v1/batches"
+	if len(values) > 0 {
+		endpoint += "?" + values.Encode()
+	}
+
+	b, err := c.do(context.Background(), http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var list batch.BatchList
+	if err := json.Unmarshal(b, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode batch list: %w", err)
+	}
+	return &list, nil
+}
+
+// Poll fetches the batch until it's finished: completed, failed, expired or cancelled.
+func (c *Client) Poll(ctx context.Context, id string, interval time.Duration) (*batch.Batch, error) {
+	for {
+		b, err := c.retrieve(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if b.Finished() {
+			return b, nil
+		}
+
+		c.Log.Debug(fmt.Sprintf(
+			"Batch %s is %s, %d/%d requests completed, %d failed",
+			b.ID, b.Status, b.RequestCounts.Completed, b.RequestCounts.Total, b.RequestCounts.Failed,
+		))
+
+		select {
+		case <-ctx.Done():
+			return b, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// outputLine is a line of batch output and error files.
+type outputLine struct {
+	ID       string `json:"id"`
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		RequestID  string          `json:"request_id"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *batch.Error `json:"error"`
+}
+
+// Results downloads output and error files of a finished batch and decodes results.
+func (c *Client) Results(ctx context.Context, b *batch.Batch) (*batch.Results, error) {
+	if b == nil {
+		return nil, errors.New("batch is nil")
+	}
+
+	results := &batch.Results{Batch: b}
+	for _, fileID := range []string{b.OutputFileID, b.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		if err := c.readResults(ctx, b.Endpoint, fileID, results); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// readResults downloads a file of results and appends its decoded lines to results.
+func (c *Client) readResults(ctx context.Context, endpoint, fileID string, results *batch.Results) error {
+	content, err := c.files.Content(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to download file '%s': %w", fileID, err)
+	}
+	defer content.Close()
+
+	scanner := bufio.NewScanner(content)
+	scanner.Buffer(nil, maxLineSize)
+	for n := 1; scanner.Scan(); n++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		// results are already paid for, so a broken line doesn't discard the others
+		var l outputLine
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			results.Items = append(results.Items, &batch.Result{
+				CustomID: l.CustomID,
+				Body:     bytes.Clone(scanner.Bytes()),
+				Error: &batch.Error{
+					Code:    "invalid_line",
+					Message: fmt.Sprintf("failed to decode line %d of file '%s': %v", n, fileID, err),
+				},
+			})
+			continue
+		}
+
+		result := c.decodeResult(endpoint, &l)
+		results.Items = append(results.Items, result)
+		results.Cost += result.Cost
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file '%s': %w", fileID, err)
+	}
+	return nil
+}
+
+// decodeResult converts a line of output into a result with a typed body for the endpoint.
+// A body that can't be decoded is reported as the error of the result.
+func (c *Client) decodeResult(endpoint string, l *outputLine) *batch.Result {
+	result := &batch.Result{CustomID: l.CustomID, Error: l.Error}
+	if l.Response == nil {
+		if result.Error == nil {
+			result.Error = &batch.Error{Code: "no_response", Message: "request has no response"}
+		}
+		return result
+	}
+
+	result.StatusCode = l.Response.StatusCode
+	result.RequestID = l.Response.RequestID
+	result.Body = l.Response.Body
+
+	if result.StatusCode != http.StatusOK {
+		if result.Error == nil {
+			result.Error = bodyError(result.StatusCode, result.Body)
+		}
+		return result
+	}
+
+	var (
+		cost float64
+		err  error
+	)
+	switch endpoint {
+	case batch.EndpointResponses:
+		result.Response, cost, err = c.responses.ParseBatchBody(result.Body)
+	case batch.EndpointChat:
+		result.Completion, cost, err = c.chat.ParseBatchBody(result.Body)
+	case batch.EndpointEmbeddings:
+		result.Embeddings, cost, err = c.embedding.ParseBatchBody(result.Body)
+	default:
+		c.Log.Warn(fmt.Sprintf("Results of batch endpoint %s are not decoded", endpoint))
+	}
+	if err != nil {
+		result.Error = &batch.Error{Code: "invalid_body", Message: err.Error()}
+		return result
+	}
+	result.Cost = cost * batch.PriceMultiplier
+	return result
+}
+
+// bodyError extracts an error from the body of a failed request.
+func bodyError(statusCode int, body json.RawMessage) *batch.Error {
+	var data struct {
+		Error struct {
+			Type    string `json:"type"`
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	_ = json.Unmarshal(body, &data)
+
+	code := cmp.Or(data.Error.Code, data.Error.Type, strconv.Itoa(statusCode))
+	message := cmp.Or(data.Error.Message, http.StatusText(statusCode))
+	return &batch.Error{Code: code, Message: message}
+}
+
+// Run uploads the input, creates a batch, polls it until it's finished and returns results.
+func (c *Client) Run(ctx context.Context, in batch.Input, opts *batch.RunOptions) (*batch.Results, error) {
+	if in == nil || in.Len() == 0 {
+		return nil, errors.New("input is empty")
+	}
+	if opts == nil {
+		opts = &batch.RunOptions{}
+	}
+
+	var content bytes.Buffer
+	if _, err := in.WriteTo(&content); err != nil {
+		return nil, fmt.Errorf("failed to write input: %w", err)
+	}
+
+	file, err := c.files.Upload(ctx, &files.UploadRequest{
+		Filename: "batch.jsonl",
+		Purpose:  files.PurposeBatch,
+		Content:  &content,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload input: %w", err)
+	}
+
+	created, err := c.Create(&batch.CreateRequest{
+		InputFileID:        file.ID,
+		Endpoint:           in.Endpoint(),
+		Metadata:           opts.Metadata,
+		OutputExpiresAfter: opts.OutputExpiresAfter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batch: %w", err)
+	}
+	c.Log.Debug(fmt.Sprintf("Created batch %s with %d requests to %s", created.ID, in.Len(), in.Endpoint()))
+
+	b, err := c.Poll(ctx, created.ID, cmp.Or(opts.PollInterval, defaultPollInterval))
+	if err != nil {
+		return nil, fmt.Errorf("failed to poll batch '%s': %w", created.ID, err)
+	}
+	if b.Status == batch.StatusFailed {
+		return nil, fmt.Errorf("batch '%s' failed: %s", b.ID, inputErrors(b.Errors))
+	}
+
+	return c.Results(ctx, b)
+}
+
+// inputErrors formats errors of input validation.
+func inputErrors(errs *batch.Errors) string {
+	if errs == nil || len(errs.Data) == 0 {
+		return "no errors reported"
+	}
+
+	messages := make([]string, len(errs.Data))
+	for i, e := range errs.Data {
+		messages[i] = fmt.Sprintf("line %d: %s: %s", e.Line, e.Code, e.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// do sends a request with optional JSON payload to the endpoint and returns the response body.
+func (c *Client) do(ctx context.Context, method, endpoint string, payload any) ([]byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		b, err := openai.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseAPI+endpoint, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.AddHeaders(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, openai.NewAPIError(resp)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, nil
+}
+
+// decodeBatch decodes a batch object.
+func decodeBatch(body []byte) (*batch.Batch, error) {
+	var b batch.Batch
+	if err := json.Unmarshal(body, &b); err != nil {
+		return nil, fmt.Errorf("failed to decode batch: %w", err)
+	}
+	return &b, nil
+}
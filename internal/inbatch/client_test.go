@@ -0,0 +1,234 @@
+package inbatch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/batch"
+	"github.com/unkn0wncode/openai/chat"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+	"github.com/unkn0wncode/openai/responses"
+)
+
+func TestInput(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(openai.NewConfig("test"))
+	in := client.NewInput()
+
+	require.NoError(t, in.AddResponses("a", &responses.Request{Input: "hi"}))
+	require.ErrorContains(t, in.AddResponses("a", &responses.Request{Input: "hi"}), "already used")
+	require.ErrorContains(t, in.AddResponses("", &responses.Request{Input: "hi"}), "empty")
+	require.ErrorContains(t, in.AddResponses("b", &responses.Request{Input: "hi", Stream: true}), "not supported")
+	require.ErrorContains(t, in.AddChat("c", chat.Request{}), batch.EndpointResponses)
+	require.Equal(t, 1, in.Len())
+	require.Equal(t, batch.EndpointResponses, in.Endpoint())
+
+	var content strings.Builder
+	_, err := in.WriteTo(&content)
+	require.NoError(t, err)
+
+	var l struct {
+		CustomID string `json:"custom_id"`
+		Method   string `json:"method"`
+		URL      string `json:"url"`
+		Body     struct {
+			Model string `json:"model"`
+			Input string `json:"input"`
+		} `json:"body"`
+	}
+	require.Equal(t, 1, strings.Count(content.String(), "\n"))
+	require.NoError(t, json.Unmarshal([]byte(content.String()), &l))
+	require.Equal(t, "a", l.CustomID)
+	require.Equal(t, "POST", l.Method)
+	require.Equal(t, batch.EndpointResponses, l.URL)
+	require.Equal(t, models.Default, l.Body.Model)
+	require.Equal(t, "hi", l.Body.Input)
+}
+
+// fakeBatches is a fake Files and Batch API that answers requests of the input by their custom IDs.
+type fakeBatches struct {
+	t *testing.T
+
+	mu     sync.Mutex
+	input  []string // custom IDs of uploaded requests
+	polls  int
+	status string
+}
+
+func (f *fakeBatches) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/v1/files":
+		assert.Equal(f.t, "batch", r.FormValue("purpose"))
+		file, _, err := r.FormFile("file")
+		if !assert.NoError(f.t, err) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var l struct {
+				CustomID string `json:"custom_id"`
+			}
+			assert.NoError(f.t, json.Unmarshal(scanner.Bytes(), &l))
+			f.input = append(f.input, l.CustomID)
+		}
+		fmt.Fprint(w, `{"id":"file-in","object":"file","purpose":"batch"}`)
+
+	case "/v1/batches":
+		var req map[string]any
+		assert.NoError(f.t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(f.t, "file-in", req["input_file_id"])
+		assert.Equal(f.t, batch.EndpointResponses, req["endpoint"])
+		assert.Equal(f.t, batch.CompletionWindow, req["completion_window"])
+		assert.Equal(f.t, map[string]any{"job": "test"}, req["metadata"])
+		fmt.Fprint(w, `{"id":"batch_1","object":"batch","endpoint":"/v1/responses","status":"validating"}`)
+
+	case "/v1/batches/batch_1":
+		f.polls++
+		if f.polls < 2 {
+			fmt.Fprint(w, `{"id":"batch_1","object":"batch","endpoint":"/v1/responses","status":"in_progress"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"id":"batch_1","object":"batch","endpoint":"/v1/responses","status":%q,`+
+			`"output_file_id":"file-out","error_file_id":"file-err","request_counts":{"total":3,"completed":1,"failed":2},`+
+			`"errors":{"object":"list","data":[{"code":"invalid_request","message":"bad line","line":1}]}}`, f.status)
+
+	case "/v1/files/file-out/content":
+		fmt.Fprintf(w, `{"id":"batch_req_1","custom_id":%q,"response":{"status_code":200,"request_id":"req_1","body":`+
+			`{"id":"resp_1","object":"response","status":"completed","model":%q,"usage":{"input_tokens":1000,"output_tokens":100},"output":[`+
+			`{"type":"message","id":"msg_1","role":"assistant","status":"completed","content":[{"type":"output_text","text":"ok","annotations":[]}]}]}`+
+			`},"error":null}`+"\n", f.input[0], models.Default)
+		if len(f.input) > 3 {
+			// a successful request with a body that can't be parsed, and a broken line
+			fmt.Fprintf(w, `{"id":"batch_req_4","custom_id":%q,"response":{"status_code":200,"request_id":"req_4","body":`+
+				`{"id":"resp_4","object":"response","status":"completed","model":%q}},"error":null}`+"\n", f.input[3], models.Default)
+			fmt.Fprint(w, `{"id":"batch_req_5",`+"\n")
+		}
+
+	case "/v1/files/file-err/content":
+		fmt.Fprintf(w, `{"id":"batch_req_2","custom_id":%q,"response":{"status_code":400,"request_id":"req_2","body":`+
+			`{"error":{"message":"bad request","type":"invalid_request_error","code":"invalid_value"}}},"error":null}`+"\n", f.input[1])
+		fmt.Fprintf(w, `{"id":"batch_req_3","custom_id":%q,"response":null,"error":{"code":"batch_expired","message":"expired"}}`+"\n", f.input[2])
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeBatches{t: t, status: batch.StatusCompleted}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	in := client.NewInput()
+	for _, id := range []string{"first", "second", "third", "fourth"} {
+		require.NoError(t, in.AddResponses(id, &responses.Request{Input: "hi " + id}))
+	}
+
+	results, err := client.Run(context.Background(), in, &batch.RunOptions{
+		Metadata:     map[string]string{"job": "test"},
+		PollInterval: time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second", "third", "fourth"}, fake.input)
+	require.Equal(t, 2, fake.polls)
+	require.Len(t, results.Items, 5)
+
+	first, ok := results.Get("first")
+	require.True(t, ok)
+	require.Nil(t, first.Error)
+	require.Equal(t, http.StatusOK, first.StatusCode)
+	require.Equal(t, "req_1", first.RequestID)
+	require.Equal(t, "ok", first.Response.JoinedTexts())
+	pricing := models.Data[models.Default]
+	require.InDelta(t, (1000*pricing.PriceIn+100*pricing.PriceOut)*batch.PriceMultiplier, first.Cost, 1e-12)
+	require.InDelta(t, first.Cost, results.Cost, 1e-12)
+
+	second, ok := results.Get("second")
+	require.True(t, ok)
+	require.Nil(t, second.Response)
+	require.Equal(t, &batch.Error{Code: "invalid_value", Message: "bad request"}, second.Error)
+
+	third, ok := results.Get("third")
+	require.True(t, ok)
+	require.Zero(t, third.StatusCode)
+	require.Equal(t, "batch_expired", third.Error.Code)
+
+	// results that can't be decoded don't discard the others
+	fourth, ok := results.Get("fourth")
+	require.True(t, ok)
+	require.Equal(t, http.StatusOK, fourth.StatusCode)
+	require.Equal(t, "invalid_body", fourth.Error.Code)
+	require.Contains(t, string(fourth.Body), "resp_4")
+	require.Nil(t, fourth.Response)
+
+	broken := results.Items[2]
+	require.Equal(t, "invalid_line", broken.Error.Code)
+	require.Contains(t, broken.Error.Message, "line 3 of file 'file-out'")
+
+	_, ok = results.Get("fifth")
+	require.False(t, ok)
+}
+
+func TestRunFailed(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeBatches{t: t, status: batch.StatusFailed}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	in := client.NewInput()
+	require.NoError(t, in.AddResponses("first", &responses.Request{Input: "hi"}))
+
+	_, err := client.Run(context.Background(), in, &batch.RunOptions{
+		Metadata:     map[string]string{"job": "test"},
+		PollInterval: time.Millisecond,
+	})
+	require.ErrorContains(t, err, "line 1: invalid_request: bad line")
+}
+
+func TestPollCancelled(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		fmt.Fprint(w, `{"id":"batch_1","object":"batch","status":"in_progress"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	b, err := client.Poll(ctx, "batch_1", time.Hour)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, batch.StatusInProgress, b.Status)
+}
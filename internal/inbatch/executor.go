@@ -0,0 +1,251 @@
+package inbatch
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/unkn0wncode/openai/batch"
+	"github.com/unkn0wncode/openai/internal/inresponses"
+	"github.com/unkn0wncode/openai/responses"
+)
+
+const (
+	// defaultExecutorRequests is the number of pending requests that triggers a batch when not specified.
+	defaultExecutorRequests = 1000
+	// defaultExecutorWait is the maximum time a request waits for its batch when not specified.
+	defaultExecutorWait = 5 * time.Minute
+)
+
+// errExecutorClosed is returned for requests submitted to a closed executor.
+var errExecutorClosed = errors.New("batch executor is closed")
+
+// executor accumulates requests to the Responses API and sends them in batches.
+type executor struct {
+	// methods other than Send use the regular API
+	*inresponses.Client
+
+	batches *Client
+	ctx     context.Context
+	opts    batch.ExecutorOptions
+
+	// limit of input files, batch.MaxInputSize except in tests
+	maxInputSize int
+
+	mu      sync.Mutex
+	input   *input
+	futures map[string]*future
+	timer   *time.Timer
+	seq     int
+	closed  bool
+	running sync.WaitGroup
+}
+
+// interface compliance checks
+var (
+	_ batch.Executor    = (*executor)(nil)
+	_ responses.Service = (*executor)(nil)
+	_ batch.Future      = (*future)(nil)
+)
+
+// NewExecutor creates an executor that sends requests to the Responses API in batches.
+func (c *Client) NewExecutor(ctx context.Context, opts *batch.ExecutorOptions) batch.Executor {
+	e := &executor{
+		Client:       c.responses,
+		batches:      c,
+		ctx:          ctx,
+		maxInputSize: batch.MaxInputSize,
+	}
+	if opts != nil {
+		e.opts = *opts
+	}
+	e.opts.MaxRequests = min(cmp.Or(e.opts.MaxRequests, defaultExecutorRequests), batch.MaxRequests)
+	e.opts.MaxWait = cmp.Or(e.opts.MaxWait, defaultExecutorWait)
+	e.reset()
+	return e
+}
+
+// Send submits the request and waits until its batch is finished.
+func (e *executor) Send(req *responses.Request) (*responses.Response, error) {
+	return e.Submit(req).Wait(e.ctx)
+}
+
+// Submit adds the request to the pending batch and returns a future of its result.
+func (e *executor) Submit(req *responses.Request) batch.Future {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.submit(req)
+}
+
+// submit adds the request to the pending batch, must be called with the lock held.
+func (e *executor) submit(req *responses.Request) *future {
+	f := newFuture()
+	if e.closed {
+		f.resolve(nil, errExecutorClosed)
+		return f
+	}
+
+	e.seq++
+	customID := "request-" + strconv.Itoa(e.seq)
+	err := e.input.AddResponses(customID, req)
+	if errors.Is(err, errInputTooLarge) && e.input.Len() > 0 {
+		// the pending batch is full, the request goes to the next one
+		e.flush()
+		err = e.input.AddResponses(customID, req)
+	}
+	if err != nil {
+		f.resolve(nil, err)
+		return f
+	}
+	e.futures[customID] = f
+
+	if e.input.Len() >= e.opts.MaxRequests {
+		e.flush()
+	} else if e.timer == nil {
+		e.startTimer()
+	}
+	return f
+}
+
+// startTimer starts the timer flushing the pending batch after MaxWait,
+// must be called with the lock held.
+func (e *executor) startTimer() {
+	var timer *time.Timer
+	timer = time.AfterFunc(e.opts.MaxWait, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		// the timer may fire while its batch is flushed, then it must not flush the next one
+		if e.timer == timer {
+			e.flush()
+		}
+	})
+	e.timer = timer
+}
+
+// Flush sends pending requests right away.
+func (e *executor) Flush() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.flush()
+}
+
+// Close sends pending requests and waits until all batches are finished.
+func (e *executor) Close() error {
+	e.mu.Lock()
+	e.closed = true
+	e.flush()
+	e.mu.Unlock()
+
+	e.running.Wait()
+	return nil
+}
+
+// flush starts a batch of pending requests, must be called with the lock held.
+func (e *executor) flush() {
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+	if e.input.Len() == 0 {
+		return
+	}
+
+	in, futures := e.input, e.futures
+	e.reset()
+
+	e.running.Add(1)
+	go func() {
+		defer e.running.Done()
+		e.run(in, futures)
+	}()
+}
+
+// reset starts a new empty pending batch.
+func (e *executor) reset() {
+	e.input = e.batches.NewInput().(*input)
+	e.input.maxSize = e.maxInputSize
+	e.futures = map[string]*future{}
+}
+
+// run sends the batch and resolves futures of its requests.
+func (e *executor) run(in *input, futures map[string]*future) {
+	results, err := e.batches.Run(e.ctx, in, &batch.RunOptions{
+		Metadata:           e.opts.Metadata,
+		OutputExpiresAfter: e.opts.OutputExpiresAfter,
+		PollInterval:       e.opts.PollInterval,
+	})
+	if err != nil {
+		e.batches.Log.Warn(fmt.Sprintf("Batch of %d requests failed: %v", in.Len(), err))
+		for _, f := range futures {
+			f.resolve(nil, err)
+		}
+		return
+	}
+
+	for _, result := range results.Items {
+		f, ok := futures[result.CustomID]
+		if !ok {
+			continue
+		}
+		delete(futures, result.CustomID)
+
+		f.result = result
+		if result.Error != nil {
+			f.resolve(nil, fmt.Errorf("request failed in batch '%s': %w", results.Batch.ID, result.Error))
+			continue
+		}
+		f.resolve(result.Response, nil)
+	}
+
+	for _, f := range futures {
+		f.resolve(nil, fmt.Errorf("no result for request in batch '%s' with status %s", results.Batch.ID, results.Batch.Status))
+	}
+}
+
+// future is the result of a request submitted to an executor.
+type future struct {
+	done     chan struct{}
+	response *responses.Response
+	result   *batch.Result
+	err      error
+}
+
+// newFuture creates an unresolved future.
+func newFuture() *future {
+	return &future{done: make(chan struct{})}
+}
+
+// resolve sets the result and wakes up waiters, must be called once.
+func (f *future) resolve(response *responses.Response, err error) {
+	f.response, f.err = response, err
+	close(f.done)
+}
+
+// Done returns a channel closed when the result is available.
+func (f *future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the result is available or ctx is done.
+func (f *future) Wait(ctx context.Context) (*responses.Response, error) {
+	select {
+	case <-f.done:
+		return f.response, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Result returns the result of the request in the batch, nil until it's available.
+func (f *future) Result() *batch.Result {
+	select {
+	case <-f.done:
+		return f.result
+	default:
+		return nil
+	}
+}
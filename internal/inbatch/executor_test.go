@@ -0,0 +1,189 @@
+package inbatch
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/batch"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/responses"
+)
+
+func TestExecutor(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeBatches{t: t, status: batch.StatusCompleted}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	e := client.NewExecutor(context.Background(), &batch.ExecutorOptions{
+		MaxRequests:  3,
+		MaxWait:      time.Hour,
+		PollInterval: time.Millisecond,
+		Metadata:     map[string]string{"job": "test"},
+	})
+
+	invalid := e.Submit(&responses.Request{Input: "hi", Stream: true})
+	_, err := invalid.Wait(context.Background())
+	require.ErrorContains(t, err, "not supported")
+
+	var futures []batch.Future
+	for range 3 {
+		futures = append(futures, e.Submit(&responses.Request{Input: "hi"}))
+	}
+
+	// the batch is sent once it's full
+	resp, err := futures[0].Wait(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp.JoinedTexts())
+	require.Equal(t, "req_1", futures[0].Result().RequestID)
+
+	_, err = futures[1].Wait(context.Background())
+	var batchErr *batch.Error
+	require.ErrorAs(t, err, &batchErr)
+	require.Equal(t, "invalid_value", batchErr.Code)
+
+	_, err = futures[2].Wait(context.Background())
+	require.ErrorContains(t, err, "batch_expired")
+
+	require.NoError(t, e.Close())
+	_, err = e.Send(&responses.Request{Input: "hi"})
+	require.ErrorIs(t, err, errExecutorClosed)
+}
+
+func TestExecutorMaxWait(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeBatches{t: t, status: batch.StatusCompleted}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	e := client.NewExecutor(context.Background(), &batch.ExecutorOptions{
+		MaxWait:      200 * time.Millisecond,
+		PollInterval: time.Millisecond,
+		Metadata:     map[string]string{"job": "test"},
+	})
+
+	// Send blocks until the batch is sent after MaxWait and finished
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		oks  int
+		errs int
+	)
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := e.Send(&responses.Request{Input: "hi"})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs++
+				return
+			}
+			oks++
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 1, oks)
+	require.Equal(t, 2, errs)
+	require.Len(t, fake.input, 3)
+	require.NoError(t, e.Close())
+}
+
+func TestExecutorMaxInputSize(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeBatches{t: t, status: batch.StatusCompleted}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	in := client.NewInput()
+	require.NoError(t, in.AddResponses("request-1", &responses.Request{Input: "hi"}))
+	lineSize := in.Size()
+
+	e := client.NewExecutor(context.Background(), &batch.ExecutorOptions{
+		MaxWait:      time.Hour,
+		PollInterval: time.Millisecond,
+		Metadata:     map[string]string{"job": "test"},
+	}).(*executor)
+	e.maxInputSize = lineSize*2 + lineSize/2
+	e.reset()
+
+	// the third request doesn't fit, so the first two are sent without it
+	var futures []batch.Future
+	for range 3 {
+		futures = append(futures, e.Submit(&responses.Request{Input: "hi"}))
+	}
+	<-futures[0].Done()
+	<-futures[1].Done()
+	require.Nil(t, futures[2].Result())
+
+	// requests that don't fit into an empty batch are rejected
+	_, err := e.Submit(&responses.Request{Input: strings.Repeat("a", e.maxInputSize)}).Wait(context.Background())
+	require.ErrorIs(t, err, errInputTooLarge)
+
+	require.NoError(t, e.Close())
+	<-futures[2].Done()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	require.Equal(t, []string{"request-1", "request-2", "request-3"}, fake.input)
+}
+
+func TestExecutorStaleTimer(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeBatches{t: t, status: batch.StatusCompleted}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	e := client.NewExecutor(context.Background(), &batch.ExecutorOptions{
+		MaxWait:      10 * time.Millisecond,
+		PollInterval: time.Millisecond,
+		Metadata:     map[string]string{"job": "test"},
+	}).(*executor)
+	first := e.Submit(&responses.Request{Input: "hi"})
+
+	// the timer fires and waits for the lock while the batch is flushed and the next one is started
+	e.mu.Lock()
+	time.Sleep(50 * time.Millisecond)
+	e.flush()
+	e.opts.MaxWait = time.Hour
+	second := e.submit(&responses.Request{Input: "hi"})
+	e.mu.Unlock()
+
+	<-first.Done()
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-second.Done():
+		t.Fatal("the next batch must not be sent by the timer of the previous one")
+	default:
+	}
+
+	require.NoError(t, e.Close())
+	<-second.Done()
+}
@@ -0,0 +1,141 @@
+package inbatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/unkn0wncode/openai/batch"
+	"github.com/unkn0wncode/openai/chat"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/internal/inembedding"
+	"github.com/unkn0wncode/openai/responses"
+)
+
+// errInputTooLarge is returned for requests that would make the input exceed its size limit.
+var errInputTooLarge = errors.New("input file would exceed the size limit")
+
+// input builds a JSONL input file of a batch.
+type input struct {
+	client   *Client
+	buf      bytes.Buffer
+	endpoint string
+	ids      map[string]bool
+	maxSize  int // limit of the content in bytes
+}
+
+// interface compliance checks
+var _ batch.Input = (*input)(nil)
+
+// line is a line of batch input.
+type line struct {
+	CustomID string          `json:"custom_id"`
+	Method   string          `json:"method"`
+	URL      string          `json:"url"`
+	Body     json.RawMessage `json:"body"`
+}
+
+// AddResponses adds a request to the Responses API.
+func (in *input) AddResponses(customID string, req *responses.Request) error {
+	if err := in.check(customID, batch.EndpointResponses); err != nil {
+		return err
+	}
+	body, err := in.client.responses.MarshalBatchBody(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request '%s': %w", customID, err)
+	}
+	return in.add(customID, batch.EndpointResponses, body)
+}
+
+// AddChat adds a request to the Chat API.
+func (in *input) AddChat(customID string, req chat.Request) error {
+	if err := in.check(customID, batch.EndpointChat); err != nil {
+		return err
+	}
+	body, err := in.client.chat.MarshalBatchBody(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request '%s': %w", customID, err)
+	}
+	return in.add(customID, batch.EndpointChat, body)
+}
+
+// AddEmbedding adds a request to the Embeddings API.
+func (in *input) AddEmbedding(customID string, req *batch.EmbeddingRequest) error {
+	if req == nil {
+		return errors.New("embedding request is nil")
+	}
+	if err := in.check(customID, batch.EndpointEmbeddings); err != nil {
+		return err
+	}
+	body, err := in.client.embedding.MarshalBatchBody(inembedding.Request{
+		Inputs:     req.Inputs,
+		Model:      req.Model,
+		Dimensions: req.Dimensions,
+		User:       req.User,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request '%s': %w", customID, err)
+	}
+	return in.add(customID, batch.EndpointEmbeddings, body)
+}
+
+// Endpoint returns the endpoint of added requests, empty if there are none.
+func (in *input) Endpoint() string {
+	return in.endpoint
+}
+
+// Len returns the number of added requests.
+func (in *input) Len() int {
+	return len(in.ids)
+}
+
+// Size returns the size of the JSONL content in bytes.
+func (in *input) Size() int {
+	return in.buf.Len()
+}
+
+// WriteTo writes the JSONL content of the input file.
+func (in *input) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(in.buf.Bytes())
+	return int64(n), err
+}
+
+// check reports whether a request with given custom ID can be added for the endpoint.
+func (in *input) check(customID, endpoint string) error {
+	switch {
+	case customID == "":
+		return errors.New("custom ID is empty")
+	case in.ids[customID]:
+		return fmt.Errorf("custom ID '%s' is already used", customID)
+	case in.endpoint != "" && in.endpoint != endpoint:
+		return fmt.Errorf("input has requests for %s, can't add a request for %s", in.endpoint, endpoint)
+	case len(in.ids) >= batch.MaxRequests:
+		return fmt.Errorf("input already has maximum of %d requests", batch.MaxRequests)
+	}
+	return nil
+}
+
+// add writes a line with the request body.
+func (in *input) add(customID, endpoint string, body []byte) error {
+	b, err := openai.Marshal(line{
+		CustomID: customID,
+		Method:   "POST",
+		URL:      endpoint,
+		Body:     body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal line '%s': %w", customID, err)
+	}
+	b = bytes.TrimSpace(b)
+	if in.buf.Len()+len(b)+1 > in.maxSize {
+		return fmt.Errorf("can't add request '%s' of %d bytes: %w of %d bytes", customID, len(b), errInputTooLarge, in.maxSize)
+	}
+
+	in.buf.Write(b)
+	in.buf.WriteByte('\n')
+	in.endpoint = endpoint
+	in.ids[customID] = true
+	return nil
+}
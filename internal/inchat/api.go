@@ -15,6 +15,7 @@ import (
 	openai "github.com/unkn0wncode/openai/internal"
 	"github.com/unkn0wncode/openai/models"
 	"github.com/unkn0wncode/openai/roles"
+	"github.com/unkn0wncode/openai/tokens"
 	"github.com/unkn0wncode/openai/tools"
 )
 
@@ -85,35 +86,15 @@ type response struct {
 	} `json:"error"`
 }
 
-// countTokens returns the number of tokens in the request.
-func countTokens(data chat.Request) int {
-	dup := data
-	dup.Messages = make([]chat.Message, len(data.Messages))
-
-	for i, msg := range data.Messages {
-		newMsg := msg
-
-		var images []chat.Image
-		for _, img := range msg.Images {
-			if !strings.HasPrefix(img.URL, "data:image/") {
-				images = append(images, img)
-			}
-		}
-		newMsg.Images = images
-
-		dup.Messages[i] = newMsg
+// countTokens returns the estimated number of tokens in the request input and its functions.
+func (c *Client) countTokens(data chat.Request) int {
+	// unregistered functions are reported when the request is marshaled
+	toolList, _ := c.functionTools(data.Functions)
+	toolDefs := make([]any, len(toolList))
+	for i, t := range toolList {
+		toolDefs[i] = t
 	}
-
-	b, err := openai.Marshal(dup)
-	if err != nil {
-		panic("failed to marshal request body: " + err.Error())
-	}
-
-	if err := openai.LoadTokenEncoders(); err != nil {
-		panic("failed to load token encoders: " + err.Error())
-	}
-
-	return len(openai.TokenEncoderChat.Encode(string(b), nil, nil))
+	return tokens.ChatRequest(&data, toolDefs...)
 }
 
 // // promptPrice returns approximate price of the request's input in USD.
@@ -137,7 +118,7 @@ func contextTokenLimit(model string) int {
 }
 
 // trimMessages cuts off the oldest messages if the request is too long.
-func trimMessages(data chat.Request) []chat.Message {
+func (c *Client) trimMessages(data chat.Request) []chat.Message {
 	hasSystemPrompt := len(data.Messages) > 0 &&
 		(data.Messages[0].Role == roles.System || data.Messages[0].Role == roles.Developer)
 	minMessages := 1
@@ -150,7 +131,7 @@ func trimMessages(data chat.Request) []chat.Message {
 	if maxTokens == 0 {
 		maxTokens = data.MaxTokens
 	}
-	for len(data.Messages) > minMessages && countTokens(data) > contextTokenLimit(data.Model)-maxTokens {
+	for len(data.Messages) > minMessages && c.countTokens(data) > contextTokenLimit(data.Model)-maxTokens {
 		messages = nil
 		if hasSystemPrompt {
 			messages = append(messages, data.Messages[0])
@@ -166,16 +147,27 @@ func trimMessages(data chat.Request) []chat.Message {
 	return messages
 }
 
-func (c *Client) execute(data chat.Request) (*response, error) {
-	if data.Model == "" {
-		data.Model = models.Default
+// prepare fills defaults and adjusts the request to fit model limits before sending.
+func (c *Client) prepare(data *chat.Request) error {
+	data.Model = c.Config.ResolveModel(data.Model)
+
+	if data.AutoStrip {
+		if stripped := data.StripUnsupported(); len(stripped) > 0 {
+			c.Config.Log.Warn(fmt.Sprintf(
+				"Stripped parameters unsupported by model '%s': %s",
+				data.Model, strings.Join(stripped, ", "),
+			))
+		}
+	}
+	if err := data.Validate(); err != nil {
+		return err
 	}
 
 	// Trim messages if the request is too long
-	data.Messages = trimMessages(data)
-	inputTokens := countTokens(data)
+	data.Messages = c.trimMessages(*data)
+	inputTokens := c.countTokens(*data)
 	if inputTokens > contextTokenLimit(data.Model) {
-		return nil, fmt.Errorf("prompt is likely too long: ~%d tokens, max %d tokens", inputTokens, contextTokenLimit(data.Model))
+		return fmt.Errorf("prompt is likely too long: ~%d tokens, max %d tokens", inputTokens, contextTokenLimit(data.Model))
 	}
 
 	// drop images of unsupported types from messages
@@ -203,6 +195,43 @@ func (c *Client) execute(data chat.Request) (*response, error) {
 		data.Messages[i].Images = newImages
 	}
 
+	return nil
+}
+
+func (c *Client) execute(data chat.Request) (*response, error) {
+	if data.Stream {
+		return nil, fmt.Errorf("request has 'stream' parameter but was invoked with Send method, use Stream method instead")
+	}
+
+	data.Model = c.Config.ResolveModel(data.Model)
+
+	var res *response
+	err := c.Config.WithFallbacks(data.Model, func(model string) error {
+		attempt := cloneRequest(data)
+		attempt.Model = model
+		var err error
+		res, err = c.post(attempt)
+		return err
+	})
+	return res, err
+}
+
+// cloneRequest returns a copy of the request with its own messages and images,
+// so that preparing it for one model doesn't change it for the next fallback model.
+func cloneRequest(data chat.Request) chat.Request {
+	data.Messages = slices.Clone(data.Messages)
+	for i, msg := range data.Messages {
+		data.Messages[i].Images = slices.Clone(msg.Images)
+	}
+	return data
+}
+
+// post prepares the request and sends it as is.
+func (c *Client) post(data chat.Request) (*response, error) {
+	if err := c.prepare(&data); err != nil {
+		return nil, err
+	}
+
 	b, err := c.marshalRequest(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
@@ -235,6 +264,11 @@ func (c *Client) execute(data chat.Request) (*response, error) {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	if resp.StatusCode != http.StatusOK {
+		c.enableLogTripper()
+		return nil, fmt.Errorf("request (model %s) failed: %w", data.Model, openai.NewAPIErrorFromBody(resp, rb))
+	}
+
 	var res response
 	if err := json.Unmarshal(rb, &res); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
@@ -327,27 +361,30 @@ func (c *Client) checkFirst(resp *response) (string, error) {
 // cost returns the resulting cost of the completed request in USD.
 // Returns zero if pricing for the model is not known.
 func (c *Client) cost(resp *response) float64 {
-	pricing, ok := models.Data[resp.Model]
+	return c.usageCost(resp.Model, resp.Usage.Prompt, resp.Usage.Completion)
+}
+
+// usageCost returns the cost of given token usage on a model in USD.
+// Returns zero if pricing for the model is not known.
+func (c *Client) usageCost(model string, promptTokens, completionTokens int) float64 {
+	pricing, ok := models.Data[model]
 	if !ok {
-		c.Config.Log.Warn(fmt.Sprintf("No pricing for found model '%s'", resp.Model))
+		c.Config.Log.Warn(fmt.Sprintf("No pricing for found model '%s'", model))
 		return 0
 	}
-	return float64(resp.Usage.Prompt)*pricing.PriceIn + float64(resp.Usage.Completion)*pricing.PriceOut
+	return float64(promptTokens)*pricing.PriceIn + float64(completionTokens)*pricing.PriceOut
 }
 
-// marshalRequest builds request body including function calls based on registered tools
-func (c *Client) marshalRequest(data chat.Request) ([]byte, error) {
-	if len(data.Functions) == 0 {
-		type Alias chat.Request
-		return openai.Marshal((*Alias)(&data))
-	}
-	// construct tools array for function calls
-	type toolEntry struct {
-		Type     string             `json:"type"`
-		Function tools.FunctionCall `json:"function"`
-	}
+// toolEntry is a function in the tool format of the API.
+type toolEntry struct {
+	Type     string             `json:"type"`
+	Function tools.FunctionCall `json:"function"`
+}
+
+// functionTools returns tool entries of registered functions by name.
+func (c *Client) functionTools(names []string) ([]toolEntry, error) {
 	var toolList []toolEntry
-	for _, name := range data.Functions {
+	for _, name := range names {
 		f, ok := c.Config.Tools.GetFunction(name)
 		if !ok {
 			return nil, fmt.Errorf("function '%s' is not registered", name)
@@ -357,6 +394,20 @@ func (c *Client) marshalRequest(data chat.Request) ([]byte, error) {
 			Function: f,
 		})
 	}
+	return toolList, nil
+}
+
+// marshalRequest builds request body including function calls based on registered tools
+func (c *Client) marshalRequest(data chat.Request) ([]byte, error) {
+	if len(data.Functions) == 0 {
+		type Alias chat.Request
+		return openai.Marshal((*Alias)(&data))
+	}
+	// construct tools array for function calls
+	toolList, err := c.functionTools(data.Functions)
+	if err != nil {
+		return nil, err
+	}
 	type Alias chat.Request
 	return openai.Marshal(&struct {
 		Tools []toolEntry `json:"tools"`
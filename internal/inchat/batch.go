@@ -0,0 +1,37 @@
+package inchat
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/unkn0wncode/openai/chat"
+	"github.com/unkn0wncode/openai/models"
+)
+
+// MarshalBatchBody returns the body of the request as Send would send it: with resolved model,
+// validated, trimmed to the context window and with functions from the registry.
+func (c *Client) MarshalBatchBody(data chat.Request) ([]byte, error) {
+	if data.Stream {
+		return nil, fmt.Errorf("Stream is not supported in batches")
+	}
+
+	if err := c.prepare(&data); err != nil {
+		return nil, err
+	}
+	return c.marshalRequest(data)
+}
+
+// ParseBatchBody parses a completion body from batch output, returns the completion
+// and its cost in USD at the regular price.
+func (c *Client) ParseBatchBody(body []byte) (*chat.Completion, float64, error) {
+	var completion chat.Completion
+	if err := json.Unmarshal(body, &completion); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode completion: %w", err)
+	}
+
+	var cost float64
+	if _, ok := models.Data[completion.Model]; ok {
+		cost = c.usageCost(completion.Model, completion.Usage.PromptTokens, completion.Usage.CompletionTokens)
+	}
+	return &completion, cost, nil
+}
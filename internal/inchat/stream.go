@@ -0,0 +1,114 @@
+// Package inchat / stream.go implements streaming of chat completions.
+package inchat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/unkn0wncode/openai/chat"
+	openai "github.com/unkn0wncode/openai/internal"
+)
+
+// openStream prepares the streaming request and sends it as is.
+// Returns the response with an event stream body.
+func (c *Client) openStream(ctx context.Context, data *chat.Request) (*http.Response, error) {
+	if err := c.prepare(data); err != nil {
+		return nil, err
+	}
+
+	b, err := c.marshalRequest(*data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.Config.BaseAPI+"v1/chat/completions",
+		bytes.NewBuffer(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.Config.AddHeaders(req)
+
+	// the timeout of the client covers reading the body too, which may take longer for streams
+	resp, err := c.Config.HTTPClient.WithoutTimeout().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	if err := openai.CheckStreamResponse(resp); err != nil {
+		return nil, fmt.Errorf("request (model %s) failed: %w", data.Model, err)
+	}
+	return resp, nil
+}
+
+// Stream sends a request with parameter "stream":true and returns a stream of chunks.
+func (c *Client) Stream(ctx context.Context, data chat.Request) (*chat.Stream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	data.Stream = true
+	data.Model = c.Config.ResolveModel(data.Model)
+
+	var resp *http.Response
+	before := time.Now()
+	err := c.Config.WithFallbacks(data.Model, func(model string) error {
+		attempt := cloneRequest(data)
+		attempt.Model = model
+		var err error
+		resp, err = c.openStream(ctx, &attempt)
+		return err
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	var (
+		chunkCount int
+		model      string
+		usage      *chat.Usage
+	)
+	items := openai.StreamSSE(ctx, resp.Body, func(event *openai.SSEEvent) (any, error) {
+		// errors may be sent mid-stream as a data payload with an error object
+		var payload struct {
+			chat.Chunk
+			Error *struct {
+				Message string `json:"message"`
+				Type    string `json:"type"`
+				Code    any    `json:"code"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(event.Data, &payload); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal chunk: %w", err)
+		}
+		if payload.Error != nil {
+			return nil, fmt.Errorf("got API error: %s", payload.Error.Message)
+		}
+
+		chunkCount++
+		if payload.Model != "" {
+			model = payload.Model
+		}
+		if payload.Usage != nil {
+			usage = payload.Usage
+		}
+		return payload.Chunk, nil
+	}, func() {
+		if usage == nil {
+			c.Config.Log.Debug(fmt.Sprintf(
+				"Chat stream finished after %s, got %d chunks",
+				time.Since(before), chunkCount,
+			))
+			return
+		}
+		c.Config.Log.Info(fmt.Sprintf(
+			"Consumed OpenAI tokens: %d + %d = %d ($%f) on model '%s' in %s",
+			usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens,
+			c.usageCost(model, usage.PromptTokens, usage.CompletionTokens),
+			model, time.Since(before),
+		))
+	})
+
+	return chat.NewStream(ctx, items, cancel), nil
+}
@@ -0,0 +1,69 @@
+package inchat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/chat"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+	"github.com/unkn0wncode/openai/roles"
+)
+
+func TestStreamAutoStripFallback(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests []map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var req map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		if req["model"] == models.GPTO1Mini {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":{"message":"The server had an error","type":"server_error"}}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"chatcmpl-1","model":"gpt-4.1","choices":[{"index":0,"delta":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	config.Models.SetFallbacks(models.GPTO1Mini, models.GPT4Quasar)
+	client := NewClient(config)
+
+	req := chat.Request{
+		Model:       models.GPTO1Mini,
+		Messages:    []chat.Message{{Role: roles.User, Content: "hi"}},
+		Temperature: 0.5,
+		AutoStrip:   true,
+	}
+	stream, err := client.Stream(context.Background(), req)
+	require.NoError(t, err)
+	msg, err := stream.Message()
+	require.NoError(t, err)
+	require.Equal(t, "ok", msg.Content)
+
+	// parameters stripped for the primary model are sent to the fallback model that supports them
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, requests, 2)
+	require.NotContains(t, requests[0], "temperature")
+	require.Equal(t, models.GPT4Quasar, requests[1]["model"])
+	require.Equal(t, 0.5, requests[1]["temperature"])
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/unkn0wncode/openai/completion"
 	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/tokens"
 )
 
 const maxTokens = 2048
@@ -52,18 +53,9 @@ type response struct {
 	} `json:"error"`
 }
 
-// countTokens returns the number of tokens in the request.
+// countTokens returns the number of tokens in the prompt.
 func (c *Client) countTokens(data completion.Request) int {
-	b, err := json.Marshal(data)
-	if err != nil {
-		panic("failed to marshal request body: " + err.Error())
-	}
-
-	if err := openai.LoadTokenEncoders(); err != nil {
-		panic("failed to load token encoders: " + err.Error())
-	}
-
-	return len(openai.TokenEncoderCompletion.Encode(string(b), nil, nil))
+	return tokens.Count(data.Model, data.Prompt)
 }
 
 // execute sends request to the Completion API and returns the response.
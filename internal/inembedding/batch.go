@@ -0,0 +1,45 @@
+package inembedding
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/unkn0wncode/openai/embedding"
+	"github.com/unkn0wncode/openai/models"
+)
+
+// MarshalBatchBody returns the body of the request with defaults used by the client.
+func (c *Client) MarshalBatchBody(data Request) ([]byte, error) {
+	if len(data.Inputs) == 0 {
+		return nil, fmt.Errorf("no inputs provided")
+	}
+
+	if data.Model == "" {
+		data.Model = models.DefaultEmbedding
+	}
+
+	if data.Dimensions == 0 {
+		data.Dimensions = defaultDimensions
+	}
+
+	return json.Marshal(data)
+}
+
+// ParseBatchBody parses a response body from batch output, returns embeddings in order
+// of inputs and their cost in USD at the regular price.
+func (c *Client) ParseBatchBody(body []byte) ([]embedding.Vector, float64, error) {
+	var res response
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	vecs := make([]embedding.Vector, len(res.Data))
+	for _, r := range res.Data {
+		if r.Index < 0 || r.Index >= len(vecs) {
+			return nil, 0, fmt.Errorf("embedding index %d is out of range", r.Index)
+		}
+		vecs[r.Index] = r.Embedding
+	}
+
+	return vecs, float64(res.Usage.Prompt) * models.DataEmbedding[res.Model], nil
+}
@@ -0,0 +1,241 @@
+// Package infiles provides a wrapper for the OpenAI Files API.
+package infiles
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/unkn0wncode/openai/files"
+	openai "github.com/unkn0wncode/openai/internal"
+)
+
+// Client is the client for the Files API.
+type Client struct {
+	*openai.Config
+}
+
+// NewClient creates a new client for the Files API.
+func NewClient(config *openai.Config) *Client {
+	return &Client{Config: config}
+}
+
+// interface compliance checks
+var _ files.Service = (*Client)(nil)
+
+const (
+	// minExpiresAfter and maxExpiresAfter are the limits of file expiration accepted by the API.
+	minExpiresAfter = time.Hour
+	maxExpiresAfter = 30 * 24 * time.Hour
+)
+
+// Upload uploads a file. The content is streamed as a multipart body without buffering it
+// in memory, so unlike other requests the upload is not retried.
+func (c *Client) Upload(ctx context.Context, data *files.UploadRequest) (*files.File, error) {
+	switch {
+	case data == nil:
+		return nil, errors.New("upload request is nil")
+	case data.Filename == "":
+		return nil, errors.New("filename is required")
+	case data.Purpose == "":
+		return nil, errors.New("purpose is required")
+	case data.Content == nil:
+		return nil, errors.New("content is required")
+	case data.ExpiresAfter != 0 && (data.ExpiresAfter < minExpiresAfter || data.ExpiresAfter > maxExpiresAfter):
+		return nil, fmt.Errorf("ExpiresAfter must be between %s and %s, got %s", minExpiresAfter, maxExpiresAfter, data.ExpiresAfter)
+	}
+
+	fields := url.Values{"purpose": {data.Purpose}}
+	if data.ExpiresAfter != 0 {
+		fields.Set("expires_after[anchor]", "created_at")
+		fields.Set("expires_after[seconds]", strconv.Itoa(int(data.ExpiresAfter/time.Second)))
+	}
+
+	before := time.Now()
+	resp, err := c.PostMultipart(ctx, "v1/files", fields, openai.MultipartFile{
+		Field:    "file",
+		Filename: data.Filename,
+		Content:  data.Content,
+	})
+	if err != nil {
+		return nil, err
+	}
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var file files.File
+	if err := json.Unmarshal(body, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode file: %w", err)
+	}
+
+	c.Log.Debug(fmt.Sprintf(
+		"Uploaded file '%s' (%d bytes) as %s in %s",
+		file.Filename, file.Bytes, file.ID, time.Since(before),
+	))
+	return &file, nil
+}
+
+// List returns a page of files.
+func (c *Client) List(opts *files.ListOptions) (*files.FileList, error) {
+	values := url.Values{}
+	if opts != nil {
+		if opts.Purpose != "" {
+			values.Set("purpose", opts.Purpose)
+		}
+		if opts.Limit > 0 {
+			values.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.After != "" {
+			values.Set("after", opts.After)
+		}
+		if opts.Order != "" {
+			values.Set("order", opts.Order)
+		}
+	}
+
+	endpoint := "v1/files"
+	if len(values) > 0 {
+		endpoint += "?" + values.Encode()
+	}
+
+	body, err := c.do(context.Background(), http.MethodGet, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var list files.FileList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode file list: %w", err)
+	}
+	return &list, nil
+}
+
+// ListAll returns all files with given purpose, or all files for empty purpose,
+// fetching as many pages as needed.
+func (c *Client) ListAll(purpose string) ([]files.File, error) {
+	opts := &files.ListOptions{Purpose: purpose}
+
+	var all []files.File
+	for {
+		page, err := c.List(opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Data...)
+
+		if !page.HasMore || page.LastID == "" {
+			return all, nil
+		}
+		opts.After = page.LastID
+	}
+}
+
+// Retrieve returns a file by ID.
+func (c *Client) Retrieve(id string) (*files.File, error) {
+	if id == "" {
+		return nil, errors.New("file ID is empty")
+	}
+
+	body, err := c.do(context.Background(), http.MethodGet, "v1/files/"+url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var file files.File
+	if err := json.Unmarshal(body, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode file: %w", err)
+	}
+	return &file, nil
+}
+
+// Content returns a reader of the file content, the caller must close it.
+func (c *Client) Content(ctx context.Context, id string) (io.ReadCloser, error) {
+	if id == "" {
+		return nil, errors.New("file ID is empty")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseAPI+"v1/files/"+url.PathEscape(id)+"/content", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.AddHeaders(req)
+
+	// the timeout of the client covers reading the body too, which may take longer for large files
+	resp, err := c.HTTPClient.WithoutTimeout().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, openai.NewAPIError(resp)
+	}
+	return resp.Body, nil
+}
+
+// Delete deletes a file.
+func (c *Client) Delete(id string) error {
+	if id == "" {
+		return errors.New("file ID is empty")
+	}
+
+	body, err := c.do(context.Background(), http.MethodDelete, "v1/files/"+url.PathEscape(id))
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Deleted bool `json:"deleted"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to decode delete result: %w", err)
+	}
+	if !result.Deleted {
+		return fmt.Errorf("file '%s' was not deleted", id)
+	}
+	return nil
+}
+
+// send sends a request without body to the endpoint.
+func (c *Client) send(ctx context.Context, method, endpoint string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseAPI+endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.AddHeaders(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	return resp, nil
+}
+
+// do sends a request without body to the endpoint and returns the response body.
+func (c *Client) do(ctx context.Context, method, endpoint string) ([]byte, error) {
+	resp, err := c.send(ctx, method, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return readBody(resp)
+}
+
+// readBody reads and closes the response body, returns an APIError for failed responses.
+func readBody(resp *http.Response) ([]byte, error) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, openai.NewAPIError(resp)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, nil
+}
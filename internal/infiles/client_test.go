@@ -0,0 +1,174 @@
+package infiles
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/files"
+	openai "github.com/unkn0wncode/openai/internal"
+)
+
+func TestUpload(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Repeat(`{"custom_id":"1"}`+"\n", 10000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST /v1/files", r.Method+" "+r.URL.Path)
+		assert.Equal(t, "Bearer test", r.Header.Get("Authorization"))
+		// streamed bodies have unknown length
+		assert.EqualValues(t, -1, r.ContentLength)
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			// the upload was aborted by the client
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, files.PurposeBatch, r.FormValue("purpose"))
+		assert.Equal(t, "created_at", r.FormValue("expires_after[anchor]"))
+		assert.Equal(t, "7200", r.FormValue("expires_after[seconds]"))
+
+		f, header, err := r.FormFile("file")
+		assert.NoError(t, err)
+		defer f.Close()
+		b, err := io.ReadAll(f)
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(b))
+
+		fmt.Fprintf(w, `{"id":"file-1","object":"file","bytes":%d,"created_at":1700000000,"expires_at":1700007200,"filename":"%s","purpose":"batch"}`,
+			len(b), header.Filename)
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	file, err := client.Upload(context.Background(), &files.UploadRequest{
+		Filename:     "input.jsonl",
+		Purpose:      files.PurposeBatch,
+		Content:      strings.NewReader(content),
+		ExpiresAfter: 2 * time.Hour,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "file-1", file.ID)
+	require.Equal(t, "input.jsonl", file.Filename)
+	require.EqualValues(t, len(content), file.Bytes)
+	require.Equal(t, 2*time.Hour, file.Expires().Sub(file.Created()))
+
+	_, err = client.Upload(context.Background(), &files.UploadRequest{
+		Filename:     "input.jsonl",
+		Purpose:      files.PurposeBatch,
+		Content:      strings.NewReader(content),
+		ExpiresAfter: time.Minute,
+	})
+	require.ErrorContains(t, err, "ExpiresAfter")
+
+	// errors of the content reader stop the upload
+	_, err = client.Upload(context.Background(), &files.UploadRequest{
+		Filename: "input.jsonl",
+		Purpose:  files.PurposeBatch,
+		Content:  io.MultiReader(strings.NewReader("partial"), errReader{}),
+	})
+	require.ErrorContains(t, err, "broken reader")
+}
+
+// errReader is a reader that always fails.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, fmt.Errorf("broken reader")
+}
+
+func TestFiles(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/files":
+			assert.Equal(t, files.PurposeUserData, r.URL.Query().Get("purpose"))
+			if r.URL.Query().Get("after") == "" {
+				fmt.Fprint(w, `{"object":"list","data":[{"id":"file-1","object":"file"},{"id":"file-2","object":"file"}],"first_id":"file-1","last_id":"file-2","has_more":true}`)
+				return
+			}
+			assert.Equal(t, "file-2", r.URL.Query().Get("after"))
+			fmt.Fprint(w, `{"object":"list","data":[{"id":"file-3","object":"file"}],"first_id":"file-3","last_id":"file-3","has_more":false}`)
+		case "GET /v1/files/file-1":
+			fmt.Fprint(w, `{"id":"file-1","object":"file","bytes":5,"filename":"a.txt","purpose":"user_data"}`)
+		case "GET /v1/files/file-1/content":
+			fmt.Fprint(w, "hello")
+		case "DELETE /v1/files/file-1":
+			fmt.Fprint(w, `{"id":"file-1","object":"file","deleted":true}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"message":"No such File object","type":"invalid_request_error"}}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	page, err := client.List(&files.ListOptions{Purpose: files.PurposeUserData})
+	require.NoError(t, err)
+	require.Len(t, page.Data, 2)
+	require.True(t, page.HasMore)
+
+	all, err := client.ListAll(files.PurposeUserData)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	require.Equal(t, "file-3", all[2].ID)
+
+	file, err := client.Retrieve("file-1")
+	require.NoError(t, err)
+	require.Equal(t, "a.txt", file.Filename)
+	require.True(t, file.Expires().IsZero())
+
+	r, err := client.Content(context.Background(), "file-1")
+	require.NoError(t, err)
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "hello", string(b))
+
+	_, err = client.Content(context.Background(), "file-missing")
+	var apiErr *openai.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+	require.NoError(t, client.Delete("file-1"))
+	require.Error(t, client.Delete("file-missing"))
+}
+
+func TestContentWithoutTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hel")
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprint(w, "lo")
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	config.HTTPClient.Timeout = 20 * time.Millisecond
+	client := NewClient(config)
+
+	// downloads outlast the overall timeout of the client
+	r, err := client.Content(context.Background(), "file-1")
+	require.NoError(t, err)
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "hello", string(b))
+}
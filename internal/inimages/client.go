@@ -0,0 +1,20 @@
+// Package inimages provides a wrapper for the OpenAI Images API.
+package inimages
+
+import (
+	"github.com/unkn0wncode/openai/images"
+	openai "github.com/unkn0wncode/openai/internal"
+)
+
+// Client is the client for the Images API.
+type Client struct {
+	*openai.Config
+}
+
+// NewClient creates a new client for the Images API.
+func NewClient(config *openai.Config) *Client {
+	return &Client{Config: config}
+}
+
+// interface compliance checks
+var _ images.Service = (*Client)(nil)
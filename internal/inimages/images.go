@@ -0,0 +1,422 @@
+package inimages
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/unkn0wncode/openai/images"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+)
+
+// generateRequest is the request body for image generation.
+type generateRequest struct {
+	Model             string `json:"model"`
+	Prompt            string `json:"prompt"`
+	N                 int    `json:"n,omitempty"`
+	Size              string `json:"size,omitempty"`
+	Quality           string `json:"quality,omitempty"`
+	Background        string `json:"background,omitempty"`
+	OutputFormat      string `json:"output_format,omitempty"`
+	OutputCompression *int   `json:"output_compression,omitempty"`
+	Moderation        string `json:"moderation,omitempty"`
+	ResponseFormat    string `json:"response_format,omitempty"`
+	Style             string `json:"style,omitempty"`
+	User              string `json:"user,omitempty"`
+	Stream            bool   `json:"stream,omitempty"`
+	PartialImages     int    `json:"partial_images,omitempty"`
+}
+
+// generateOptions validates the generation request and returns its options.
+func generateOptions(data *images.GenerateRequest, stream bool) (*options, error) {
+	if data == nil {
+		return nil, errors.New("generate request is nil")
+	}
+	o := &options{
+		model:             cmp.Or(data.Model, models.DefaultImage),
+		prompt:            data.Prompt,
+		n:                 data.N,
+		size:              data.Size,
+		quality:           data.Quality,
+		background:        data.Background,
+		outputFormat:      data.OutputFormat,
+		outputCompression: data.OutputCompression,
+		moderation:        data.Moderation,
+		responseFormat:    data.ResponseFormat,
+		style:             data.Style,
+		partialImages:     data.PartialImages,
+		stream:            stream,
+	}
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// postGenerate sends the generation request.
+func (c *Client) postGenerate(ctx context.Context, data *images.GenerateRequest, o *options) (*http.Response, error) {
+	b, err := openai.Marshal(generateRequest{
+		Model:             o.model,
+		Prompt:            data.Prompt,
+		N:                 data.N,
+		Size:              data.Size,
+		Quality:           data.Quality,
+		Background:        data.Background,
+		OutputFormat:      data.OutputFormat,
+		OutputCompression: data.OutputCompression,
+		Moderation:        data.Moderation,
+		ResponseFormat:    data.ResponseFormat,
+		Style:             data.Style,
+		User:              data.User,
+		Stream:            o.stream,
+		PartialImages:     data.PartialImages,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseAPI+"v1/images/generations", bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.AddHeaders(req)
+
+	// the timeout of the client covers reading the body too, which may take longer for generations
+	resp, err := c.HTTPClient.WithoutTimeout().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	return resp, nil
+}
+
+// Generate creates images from a prompt.
+func (c *Client) Generate(ctx context.Context, data *images.GenerateRequest) (*images.Result, error) {
+	o, err := generateOptions(data, false)
+	if err != nil {
+		return nil, err
+	}
+
+	before := time.Now()
+	resp, err := c.postGenerate(ctx, data, o)
+	if err != nil {
+		return nil, err
+	}
+	return c.decodeResult(resp, o, before)
+}
+
+// GenerateStream creates an image from a prompt and streams partial images while it's generated.
+func (c *Client) GenerateStream(ctx context.Context, data *images.GenerateRequest) (*images.Stream, error) {
+	o, err := generateOptions(data, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// the stream aborts the request when it's closed
+	ctx, cancel := context.WithCancel(ctx)
+	before := time.Now()
+	resp, err := c.postGenerate(ctx, data, o)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := openai.CheckStreamResponse(resp); err != nil {
+		cancel()
+		return nil, fmt.Errorf("request (model %s) failed: %w", o.model, err)
+	}
+	return c.stream(ctx, cancel, resp, o, before), nil
+}
+
+// editOptions validates the edit request and returns its options and multipart form.
+func editOptions(data *images.EditRequest, stream bool) (*options, url.Values, []openai.MultipartFile, error) {
+	if data == nil {
+		return nil, nil, nil, errors.New("edit request is nil")
+	}
+	o := &options{
+		model:             cmp.Or(data.Model, models.DefaultImage),
+		prompt:            data.Prompt,
+		n:                 data.N,
+		size:              data.Size,
+		quality:           data.Quality,
+		background:        data.Background,
+		outputFormat:      data.OutputFormat,
+		outputCompression: data.OutputCompression,
+		responseFormat:    data.ResponseFormat,
+		inputFidelity:     data.InputFidelity,
+		partialImages:     data.PartialImages,
+		stream:            stream,
+	}
+	if err := o.validate(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	_, inLimit, sizeLimit, _, known := limits(o.model)
+	switch {
+	case o.model == models.DALLE3:
+		return nil, nil, nil, fmt.Errorf("edits are not supported by model %s", o.model)
+	case len(data.Images) == 0:
+		return nil, nil, nil, errors.New("at least one image is required")
+	case known && len(data.Images) > inLimit:
+		return nil, nil, nil, fmt.Errorf("model %s accepts up to %d images, got %d", o.model, inLimit, len(data.Images))
+	}
+
+	// a single image is sent as "image", several as an array
+	field := "image"
+	if len(data.Images) > 1 {
+		field = "image[]"
+	}
+	var files []openai.MultipartFile
+	for i, image := range data.Images {
+		if err := checkInput(fmt.Sprintf("image %d", i), image, o.model == models.DALLE2); err != nil {
+			return nil, nil, nil, err
+		}
+		files = append(files, inputFile(field, image, sizeLimit))
+	}
+	if data.Mask != nil {
+		if err := checkInput("mask", *data.Mask, true); err != nil {
+			return nil, nil, nil, err
+		}
+		files = append(files, inputFile("mask", *data.Mask, sizeLimit))
+	}
+
+	fields := url.Values{
+		"model":  {o.model},
+		"prompt": {data.Prompt},
+	}
+	setField(fields, "size", data.Size)
+	setField(fields, "quality", data.Quality)
+	setField(fields, "background", data.Background)
+	setField(fields, "output_format", data.OutputFormat)
+	setField(fields, "input_fidelity", data.InputFidelity)
+	setField(fields, "response_format", data.ResponseFormat)
+	setField(fields, "user", data.User)
+	if data.N > 0 {
+		fields.Set("n", strconv.Itoa(data.N))
+	}
+	if data.OutputCompression != nil {
+		fields.Set("output_compression", strconv.Itoa(*data.OutputCompression))
+	}
+	if stream {
+		fields.Set("stream", "true")
+		fields.Set("partial_images", strconv.Itoa(data.PartialImages))
+	}
+	return o, fields, files, nil
+}
+
+// Edit creates images from input images, an optional mask and a prompt.
+func (c *Client) Edit(ctx context.Context, data *images.EditRequest) (*images.Result, error) {
+	o, fields, files, err := editOptions(data, false)
+	if err != nil {
+		return nil, err
+	}
+
+	before := time.Now()
+	resp, err := c.postMultipart(ctx, "v1/images/edits", fields, files...)
+	if err != nil {
+		return nil, err
+	}
+	return c.decodeResult(resp, o, before)
+}
+
+// EditStream edits images and streams partial images while the result is generated.
+func (c *Client) EditStream(ctx context.Context, data *images.EditRequest) (*images.Stream, error) {
+	o, fields, files, err := editOptions(data, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// the stream aborts the request when it's closed
+	ctx, cancel := context.WithCancel(ctx)
+	before := time.Now()
+	resp, err := c.postMultipart(ctx, "v1/images/edits", fields, files...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := openai.CheckStreamResponse(resp); err != nil {
+		cancel()
+		return nil, fmt.Errorf("request (model %s) failed: %w", o.model, err)
+	}
+	return c.stream(ctx, cancel, resp, o, before), nil
+}
+
+// Variation creates variations of an image.
+func (c *Client) Variation(ctx context.Context, data *images.VariationRequest) (*images.Result, error) {
+	if data == nil {
+		return nil, errors.New("variation request is nil")
+	}
+	o := &options{
+		model:          cmp.Or(data.Model, models.DALLE2),
+		n:              data.N,
+		size:           data.Size,
+		responseFormat: data.ResponseFormat,
+	}
+	_, _, sizeLimit, outLimit, _ := limits(o.model)
+	switch {
+	case o.model != models.DALLE2:
+		return nil, fmt.Errorf("variations are not supported by model %s", o.model)
+	case data.N < 0 || data.N > outLimit:
+		return nil, fmt.Errorf("model %s generates from 1 to %d images, got %d", o.model, outLimit, data.N)
+	}
+	for _, check := range []error{
+		oneOf("size", data.Size, sizes(o.model)),
+		oneOf("response format", data.ResponseFormat, []string{images.ResponseFormatURL, images.ResponseFormatB64JSON}),
+		checkInput("image", data.Image, true),
+	} {
+		if check != nil {
+			return nil, check
+		}
+	}
+
+	fields := url.Values{"model": {o.model}}
+	setField(fields, "size", data.Size)
+	setField(fields, "response_format", data.ResponseFormat)
+	setField(fields, "user", data.User)
+	if data.N > 0 {
+		fields.Set("n", strconv.Itoa(data.N))
+	}
+
+	before := time.Now()
+	resp, err := c.postMultipart(ctx, "v1/images/variations", fields, inputFile("image", data.Image, sizeLimit))
+	if err != nil {
+		return nil, err
+	}
+	return c.decodeResult(resp, o, before)
+}
+
+// setField sets a form field unless the value is empty.
+func setField(fields url.Values, name, value string) {
+	if value != "" {
+		fields.Set(name, value)
+	}
+}
+
+// inputFile returns the multipart file of an input image limited to size bytes, zero means no limit.
+func inputFile(field string, image images.InputImage, size int) openai.MultipartFile {
+	content := image.Content
+	if size > 0 {
+		content = &openai.LimitedReader{R: content, N: int64(size), Err: errImageTooLarge}
+	}
+	return openai.MultipartFile{Field: field, Filename: image.Filename, Content: content}
+}
+
+// postMultipart sends the multipart request, reporting oversized images as such.
+func (c *Client) postMultipart(ctx context.Context, endpoint string, fields url.Values, files ...openai.MultipartFile) (*http.Response, error) {
+	resp, err := c.PostMultipart(ctx, endpoint, fields, files...)
+	if err != nil {
+		if errors.Is(err, errImageTooLarge) {
+			return nil, errImageTooLarge
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// decodeResult reads the result from the response and calculates its cost.
+func (c *Client) decodeResult(resp *http.Response, o *options, before time.Time) (*images.Result, error) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, openai.NewAPIError(resp)
+	}
+	defer resp.Body.Close()
+
+	var result images.Result
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode images: %w", err)
+	}
+	result.Model = o.model
+	result.Cost = c.cost(o, result.Quality, result.Size, len(result.Images), result.Usage)
+	c.logUsage(o.model, len(result.Images), result.Usage, before)
+	return &result, nil
+}
+
+// cost calculates the cost of images from token usage, or from the price per image by quality
+// and size. Returned quality and size take precedence over requested ones.
+func (c *Client) cost(o *options, quality, size string, n int, usage *images.Usage) float64 {
+	if data, ok := models.ImageData[o.model]; ok && usage != nil && data.PriceOut > 0 {
+		return float64(usage.InputTokensDetails.TextTokens)*data.PriceInText +
+			float64(usage.InputTokensDetails.ImageTokens)*data.PriceInImage +
+			float64(usage.OutputTokens)*data.PriceOut
+	}
+
+	prices, ok := models.PricePerImageData[o.model]
+	if !ok {
+		c.Log.Warn(fmt.Sprintf("No pricing for found model '%s'", o.model))
+		return 0
+	}
+
+	quality, size = cmp.Or(quality, o.quality), cmp.Or(size, o.size)
+	if !isGPTImage(o.model) {
+		quality = cmp.Or(quality, images.QualityStandard)
+	}
+	if size == "" || size == images.SizeAuto {
+		size = images.Size1024
+	}
+	price, ok := prices[quality][size]
+	if !ok {
+		c.Log.Warn(fmt.Sprintf("No pricing for found model '%s' with quality '%s' and size '%s'", o.model, quality, size))
+		return 0
+	}
+	return price * float64(n)
+}
+
+// logUsage logs usage of image generation.
+func (c *Client) logUsage(model string, n int, usage *images.Usage, before time.Time) {
+	if usage == nil {
+		c.Log.Debug(fmt.Sprintf("Generated %d images with model '%s' in %s", n, model, time.Since(before)))
+		return
+	}
+	c.Log.Info(fmt.Sprintf(
+		"Consumed OpenAI tokens: %d + %d = %d on model '%s' in %s",
+		usage.InputTokens, usage.OutputTokens, usage.TotalTokens, model, time.Since(before),
+	))
+}
+
+// stream reads events of the response and returns the stream delivering them, which calls cancel when it ends.
+func (c *Client) stream(ctx context.Context, cancel context.CancelFunc, resp *http.Response, o *options, before time.Time) *images.Stream {
+	items := openai.StreamSSE(ctx, resp.Body, func(event *openai.SSEEvent) (any, error) {
+		var payload struct {
+			Type              string        `json:"type"`
+			B64JSON           string        `json:"b64_json"`
+			PartialImageIndex int           `json:"partial_image_index"`
+			Background        string        `json:"background"`
+			OutputFormat      string        `json:"output_format"`
+			Quality           string        `json:"quality"`
+			Size              string        `json:"size"`
+			Usage             *images.Usage `json:"usage"`
+			Error             *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(event.Data, &payload); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal image event: %w", err)
+		}
+		if payload.Error != nil {
+			return nil, fmt.Errorf("got API error: %s", payload.Error.Message)
+		}
+
+		e := images.Event{
+			Type:              payload.Type,
+			Image:             images.Image{B64JSON: payload.B64JSON},
+			PartialImageIndex: payload.PartialImageIndex,
+			Background:        payload.Background,
+			OutputFormat:      payload.OutputFormat,
+			Quality:           payload.Quality,
+			Size:              payload.Size,
+			Usage:             payload.Usage,
+		}
+		if e.Completed() {
+			e.Cost = c.cost(o, payload.Quality, payload.Size, 1, payload.Usage)
+			c.logUsage(o.model, 1, payload.Usage, before)
+		}
+		return e, nil
+	}, nil)
+
+	return images.NewStream(ctx, o.model, items, cancel)
+}
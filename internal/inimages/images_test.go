@@ -0,0 +1,310 @@
+package inimages
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/images"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+)
+
+// newImagesServer starts a fake Images API answering with given handler.
+func newImagesServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *Client {
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	return NewClient(config)
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	png := base64.StdEncoding.EncodeToString([]byte("png"))
+	client := newImagesServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/images/generations", r.URL.Path)
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]any{
+			"model":              models.GPTImage1,
+			"prompt":             "a red fox",
+			"n":                  float64(2),
+			"size":               images.Size1536x1024,
+			"background":         images.BackgroundOpaque,
+			"output_format":      images.FormatWEBP,
+			"output_compression": float64(0),
+		}, body)
+		fmt.Fprintf(w, `{"created":1,"data":[{"b64_json":%q},{"b64_json":%q}],"quality":"medium",`+
+			`"size":"1536x1024","output_format":"webp","usage":{"input_tokens":10,"output_tokens":2000,`+
+			`"total_tokens":2010,"input_tokens_details":{"text_tokens":10,"image_tokens":0}}}`, png, png)
+	})
+
+	compression := 0
+	result, err := client.Generate(context.Background(), &images.GenerateRequest{
+		Prompt:            "a red fox",
+		N:                 2,
+		Size:              images.Size1536x1024,
+		Background:        images.BackgroundOpaque,
+		OutputFormat:      images.FormatWEBP,
+		OutputCompression: &compression,
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.GPTImage1, result.Model)
+	require.Len(t, result.Images, 2)
+	b, err := result.Images[0].Bytes()
+	require.NoError(t, err)
+	require.Equal(t, "png", string(b))
+	require.Equal(t, "medium", result.Quality)
+	require.InDelta(t, 10*0.000005+2000*0.00004, result.Cost, 1e-9)
+}
+
+func TestGeneratePerImageCost(t *testing.T) {
+	t.Parallel()
+
+	client := newImagesServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"created":1,"data":[{"url":"https://example.com/1.png","revised_prompt":"a fox"}]}`)
+	})
+
+	result, err := client.Generate(context.Background(), &images.GenerateRequest{
+		Prompt:         "fox",
+		Model:          models.DALLE3,
+		Size:           images.Size1792x1024,
+		Quality:        images.QualityHD,
+		Style:          "natural",
+		ResponseFormat: images.ResponseFormatURL,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "a fox", result.Images[0].RevisedPrompt)
+	_, err = result.Images[0].Bytes()
+	require.Error(t, err)
+	require.Equal(t, 0.12, result.Cost)
+
+	// quality returned by the API is used for newer models without token pricing
+	client = newImagesServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"created":1,"data":[{"b64_json":"cG5n"}],"quality":"low","size":"1024x1024"}`)
+	})
+	result, err = client.Generate(context.Background(), &images.GenerateRequest{Prompt: "fox", Model: models.GPTImage15})
+	require.NoError(t, err)
+	require.Equal(t, 0.009, result.Cost)
+}
+
+func TestGenerateValidation(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(openai.NewConfig("test"))
+	compression := 50
+	for name, req := range map[string]*images.GenerateRequest{
+		"no prompt":           {},
+		"too many images":     {Prompt: "x", Model: models.DALLE3, N: 2},
+		"dall-e size":         {Prompt: "x", Model: models.DALLE2, Size: images.Size1536x1024},
+		"gpt quality":         {Prompt: "x", Quality: images.QualityHD},
+		"dall-e background":   {Prompt: "x", Model: models.DALLE3, Background: images.BackgroundTransparent},
+		"gpt response format": {Prompt: "x", ResponseFormat: images.ResponseFormatURL},
+		"transparent jpeg":    {Prompt: "x", Background: images.BackgroundTransparent, OutputFormat: images.FormatJPEG},
+		"png compression":     {Prompt: "x", OutputCompression: &compression},
+		"partial images":      {Prompt: "x", PartialImages: 2},
+		"style":               {Prompt: "x", Style: "vivid"},
+		"long prompt":         {Prompt: strings.Repeat("x", 1001), Model: models.DALLE2},
+	} {
+		_, err := client.Generate(context.Background(), req)
+		require.Error(t, err, name)
+	}
+
+	_, err := client.GenerateStream(context.Background(), &images.GenerateRequest{Prompt: "x", Model: models.DALLE3})
+	require.ErrorContains(t, err, "streaming is not supported")
+}
+
+func TestEdit(t *testing.T) {
+	t.Parallel()
+
+	client := newImagesServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/images/edits", r.URL.Path)
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			// the upload of an oversized image is aborted
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, models.GPTImage1, r.FormValue("model"))
+		assert.Equal(t, "combine", r.FormValue("prompt"))
+		assert.Equal(t, "high", r.FormValue("input_fidelity"))
+		assert.Empty(t, r.MultipartForm.Value["stream"])
+
+		var names []string
+		for _, h := range r.MultipartForm.File["image[]"] {
+			names = append(names, h.Filename)
+		}
+		assert.Equal(t, []string{"a.png", "b.jpg"}, names)
+		mask, header, err := r.FormFile("mask")
+		assert.NoError(t, err)
+		assert.Equal(t, "mask.png", header.Filename)
+		b, err := io.ReadAll(mask)
+		assert.NoError(t, err)
+		assert.Equal(t, "mask", string(b))
+
+		fmt.Fprint(w, `{"created":1,"data":[{"b64_json":"cG5n"}],"usage":{"input_tokens":300,"output_tokens":100,`+
+			`"total_tokens":400,"input_tokens_details":{"text_tokens":100,"image_tokens":200}}}`)
+	})
+
+	result, err := client.Edit(context.Background(), &images.EditRequest{
+		Images: []images.InputImage{
+			{Content: strings.NewReader("a"), Filename: "a.png"},
+			{Content: strings.NewReader("b"), Filename: "b.jpg"},
+		},
+		Mask:          &images.InputImage{Content: strings.NewReader("mask"), Filename: "mask.png"},
+		Prompt:        "combine",
+		InputFidelity: "high",
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Images, 1)
+	require.InDelta(t, 100*0.000005+200*0.00001+100*0.00004, result.Cost, 1e-9)
+
+	_, err = client.Edit(context.Background(), &images.EditRequest{
+		Images: []images.InputImage{{Content: strings.NewReader("a"), Filename: "a.jpg"}},
+		Prompt: "x",
+		Model:  models.DALLE2,
+	})
+	require.ErrorContains(t, err, "must be a png file")
+
+	_, err = client.Edit(context.Background(), &images.EditRequest{
+		Images: []images.InputImage{{Content: strings.NewReader(strings.Repeat("a", 4*1024*1024+1)), Filename: "a.png"}},
+		Prompt: "x",
+		Model:  models.DALLE2,
+	})
+	require.ErrorIs(t, err, errImageTooLarge)
+}
+
+func TestVariation(t *testing.T) {
+	t.Parallel()
+
+	client := newImagesServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/images/variations", r.URL.Path)
+		assert.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, models.DALLE2, r.FormValue("model"))
+		assert.Equal(t, images.Size512, r.FormValue("size"))
+		assert.Equal(t, "1", r.FormValue("n"))
+		fmt.Fprint(w, `{"created":1,"data":[{"b64_json":"cG5n"}]}`)
+	})
+
+	result, err := client.Variation(context.Background(), &images.VariationRequest{
+		Image:          images.InputImage{Content: strings.NewReader("png"), Filename: "fox.png"},
+		N:              1,
+		Size:           images.Size512,
+		ResponseFormat: images.ResponseFormatB64JSON,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0.018, result.Cost)
+
+	_, err = client.Variation(context.Background(), &images.VariationRequest{
+		Image: images.InputImage{Content: strings.NewReader("png"), Filename: "fox.png"},
+		Model: models.GPTImage1,
+	})
+	require.ErrorContains(t, err, "not supported")
+}
+
+func TestGenerateStream(t *testing.T) {
+	t.Parallel()
+
+	client := newImagesServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, true, body["stream"])
+		assert.Equal(t, float64(2), body["partial_images"])
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := range 2 {
+			fmt.Fprintf(w, "event: image_generation.partial_image\ndata: "+
+				`{"type":"image_generation.partial_image","b64_json":"cGFydA==","partial_image_index":%d,"size":"1024x1024","quality":"low"}`+
+				"\n\n", i)
+		}
+		// the stream outlasts the overall timeout of the client
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprint(w, "event: image_generation.completed\ndata: "+
+			`{"type":"image_generation.completed","b64_json":"cG5n","size":"1024x1024","quality":"low","output_format":"png",`+
+			`"usage":{"input_tokens":5,"output_tokens":200,"total_tokens":205,"input_tokens_details":{"text_tokens":5}}}`+"\n\n")
+	})
+
+	client.HTTPClient.Timeout = 20 * time.Millisecond
+	stream, err := client.GenerateStream(context.Background(), &images.GenerateRequest{Prompt: "fox", PartialImages: 2})
+	require.NoError(t, err)
+
+	var partial []int
+	for stream.Next() {
+		if e := stream.Event(); e.Type == images.EventGenerationPartialImage {
+			partial = append(partial, e.PartialImageIndex)
+			b, err := e.Image.Bytes()
+			require.NoError(t, err)
+			require.Equal(t, "part", string(b))
+		}
+	}
+	require.NoError(t, stream.Err())
+	require.Equal(t, []int{0, 1}, partial)
+
+	result, err := stream.Result()
+	require.NoError(t, err)
+	require.Len(t, result.Images, 1)
+	require.Equal(t, "png", result.OutputFormat)
+	require.Equal(t, 205, result.Usage.TotalTokens)
+	require.InDelta(t, 5*0.000005+200*0.00004, result.Cost, 1e-9)
+}
+
+func TestGenerateStreamCancelled(t *testing.T) {
+	t.Parallel()
+
+	client := newImagesServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: "+`{"type":"image_generation.partial_image","b64_json":"cGFydA==","partial_image_index":0}`+"\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.GenerateStream(ctx, &images.GenerateRequest{Prompt: "fox", PartialImages: 1})
+	require.NoError(t, err)
+
+	require.True(t, stream.Next())
+	cancel()
+	result, err := stream.Result()
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, result)
+}
+
+func TestGenerateStreamClose(t *testing.T) {
+	t.Parallel()
+
+	aborted := make(chan struct{})
+	client := newImagesServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: "+`{"type":"image_generation.partial_image","b64_json":"cGFydA==","partial_image_index":0}`+"\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+		close(aborted)
+	})
+
+	stream, err := client.GenerateStream(context.Background(), &images.GenerateRequest{Prompt: "fox", PartialImages: 1})
+	require.NoError(t, err)
+
+	require.True(t, stream.Next())
+	stream.Close()
+	require.False(t, stream.Next())
+	require.NoError(t, stream.Err())
+
+	// the request is aborted even though the context of the caller is still alive
+	select {
+	case <-aborted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close must abort the request")
+	}
+}
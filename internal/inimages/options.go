@@ -0,0 +1,150 @@
+package inimages
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/unkn0wncode/openai/images"
+	"github.com/unkn0wncode/openai/models"
+)
+
+// options are parameters shared by generation and edit requests.
+type options struct {
+	model             string
+	prompt            string
+	n                 int
+	size              string
+	quality           string
+	background        string
+	outputFormat      string
+	outputCompression *int
+	moderation        string
+	responseFormat    string
+	style             string
+	inputFidelity     string
+	partialImages     int
+	stream            bool
+}
+
+// isGPTImage reports whether the model is a GPT image model rather than dall-e.
+func isGPTImage(model string) bool {
+	return strings.HasPrefix(model, "gpt-image") || strings.HasPrefix(model, "chatgpt-image")
+}
+
+// limits returns limits of the model, GPT image models missing from models.ImageData
+// get limits of models.GPTImage1.
+func limits(model string) (prompt, inImages, inImageSize, outImages int, known bool) {
+	data, ok := models.ImageData[model]
+	if !ok && isGPTImage(model) {
+		data, ok = models.ImageData[models.GPTImage1]
+	}
+	return data.LimitPrompt, data.LimitInImages, data.LimitInImageSize, data.LimitOutImages, ok
+}
+
+// sizes returns supported sizes of the model.
+func sizes(model string) []string {
+	switch {
+	case isGPTImage(model):
+		return []string{images.SizeAuto, images.Size1024, images.Size1536x1024, images.Size1024x1536}
+	case model == models.DALLE2:
+		return []string{images.Size256, images.Size512, images.Size1024}
+	case model == models.DALLE3:
+		return []string{images.Size1024, images.Size1792x1024, images.Size1024x1792}
+	}
+	return nil
+}
+
+// qualities returns supported qualities of the model.
+func qualities(model string) []string {
+	switch {
+	case isGPTImage(model):
+		return []string{images.QualityAuto, images.QualityLow, images.QualityMedium, images.QualityHigh}
+	case model == models.DALLE2:
+		return []string{images.QualityStandard}
+	case model == models.DALLE3:
+		return []string{images.QualityStandard, images.QualityHD}
+	}
+	return nil
+}
+
+// oneOf checks that a non-empty value is in the list of supported values, empty lists allow anything.
+func oneOf(name, value string, supported []string) error {
+	if value == "" || len(supported) == 0 || slices.Contains(supported, value) {
+		return nil
+	}
+	return fmt.Errorf("unsupported %s '%s', supported: %s", name, value, strings.Join(supported, ", "))
+}
+
+// validate checks options against capabilities of the model.
+func (o *options) validate() error {
+	gpt := isGPTImage(o.model)
+	promptLimit, _, _, outLimit, known := limits(o.model)
+
+	switch {
+	case o.prompt == "":
+		return errors.New("prompt is required")
+	case known && len([]rune(o.prompt)) > promptLimit:
+		return fmt.Errorf("prompt has %d characters, limit of model %s is %d", len([]rune(o.prompt)), o.model, promptLimit)
+	case o.n < 0 || known && o.n > outLimit:
+		return fmt.Errorf("model %s generates from 1 to %d images, got %d", o.model, outLimit, o.n)
+	case !gpt && (o.background != "" || o.outputFormat != "" || o.outputCompression != nil || o.moderation != ""):
+		return fmt.Errorf("background, output format, compression and moderation are not supported by model %s", o.model)
+	case gpt && o.responseFormat != "":
+		return fmt.Errorf("response format is not supported by model %s, it always returns base64", o.model)
+	case o.style != "" && o.model != models.DALLE3:
+		return fmt.Errorf("style is not supported by model %s", o.model)
+	case o.inputFidelity != "" && (!gpt || o.model == models.GPTImage1Mini):
+		return fmt.Errorf("input fidelity is not supported by model %s", o.model)
+	case o.stream && !gpt:
+		return fmt.Errorf("streaming is not supported by model %s", o.model)
+	case o.partialImages < 0 || o.partialImages > images.MaxPartialImages:
+		return fmt.Errorf("partial images must be from 0 to %d, got %d", images.MaxPartialImages, o.partialImages)
+	case o.partialImages > 0 && !o.stream:
+		return errors.New("partial images are only sent by streams")
+	case o.background == images.BackgroundTransparent && o.outputFormat == images.FormatJPEG:
+		return errors.New("transparent background requires png or webp output format")
+	case o.outputCompression != nil && (*o.outputCompression < 0 || *o.outputCompression > 100):
+		return fmt.Errorf("output compression must be from 0 to 100, got %d", *o.outputCompression)
+	case o.outputCompression != nil && o.outputFormat != images.FormatJPEG && o.outputFormat != images.FormatWEBP:
+		return errors.New("output compression requires jpeg or webp output format")
+	}
+
+	for _, check := range []error{
+		oneOf("size", o.size, sizes(o.model)),
+		oneOf("quality", o.quality, qualities(o.model)),
+		oneOf("background", o.background, []string{images.BackgroundAuto, images.BackgroundTransparent, images.BackgroundOpaque}),
+		oneOf("output format", o.outputFormat, []string{images.FormatPNG, images.FormatJPEG, images.FormatWEBP}),
+		oneOf("moderation", o.moderation, []string{"auto", "low"}),
+		oneOf("response format", o.responseFormat, []string{images.ResponseFormatURL, images.ResponseFormatB64JSON}),
+		oneOf("style", o.style, []string{"vivid", "natural"}),
+		oneOf("input fidelity", o.inputFidelity, []string{"high", "low"}),
+	} {
+		if check != nil {
+			return check
+		}
+	}
+	return nil
+}
+
+// inputFormats are supported formats of input images.
+var inputFormats = []string{".png", ".jpg", ".jpeg", ".webp"}
+
+// checkInput checks an input image, pngOnly is set for dall-e-2 and masks.
+func checkInput(name string, image images.InputImage, pngOnly bool) error {
+	ext := strings.ToLower(path.Ext(image.Filename))
+	switch {
+	case image.Content == nil:
+		return fmt.Errorf("%s has no content", name)
+	case pngOnly && ext != ".png":
+		return fmt.Errorf("%s must be a png file, got '%s'", name, image.Filename)
+	case !slices.Contains(inputFormats, ext):
+		return fmt.Errorf("%s must be a png, jpg or webp file, got '%s'", name, image.Filename)
+	}
+	return nil
+}
+
+// errImageTooLarge is returned when an input image exceeds the limit of the model.
+var errImageTooLarge = errors.New("image exceeds the size limit of the model")
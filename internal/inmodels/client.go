@@ -0,0 +1,113 @@
+// Package inmodels provides a wrapper for the OpenAI Models API.
+package inmodels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+)
+
+// Client is the client for the Models API.
+type Client struct {
+	*openai.Config
+}
+
+// NewClient creates a new client for the Models API.
+func NewClient(config *openai.Config) *Client {
+	return &Client{Config: config}
+}
+
+// interface compliance checks
+var _ models.Service = (*Client)(nil)
+
+// List returns models available to the token, including fine-tuned models of the organization.
+func (c *Client) List() ([]models.Model, error) {
+	return c.ListModels(context.Background())
+}
+
+// Retrieve returns a model by ID.
+func (c *Client) Retrieve(id string) (*models.Model, error) {
+	body, err := c.do(http.MethodGet, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var model models.Model
+	if err := json.Unmarshal(body, &model); err != nil {
+		return nil, fmt.Errorf("failed to decode model: %w", err)
+	}
+	return &model, nil
+}
+
+// Delete deletes a fine-tuned model. Requires the Owner role in the organization.
+func (c *Client) Delete(id string) error {
+	body, err := c.do(http.MethodDelete, id)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Deleted bool `json:"deleted"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to decode delete result: %w", err)
+	}
+	if !result.Deleted {
+		return fmt.Errorf("model '%s' was not deleted", id)
+	}
+	return nil
+}
+
+// DiffData lists available models and compares them with data of the models package,
+// see models.Diff. Differences are logged as warnings.
+func (c *Client) DiffData() (*models.DataDiff, error) {
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	diff := models.Diff(list)
+	if len(diff.Unknown) > 0 {
+		c.Log.Warn(fmt.Sprintf("Models missing from package data: %s", strings.Join(diff.Unknown, ", ")))
+	}
+	if len(diff.Retired) > 0 {
+		c.Log.Warn(fmt.Sprintf("Models not listed by the API: %s", strings.Join(diff.Retired, ", ")))
+	}
+	return diff, nil
+}
+
+// do sends a request without body for the model and returns the response body.
+func (c *Client) do(method, id string) ([]byte, error) {
+	if id == "" {
+		return nil, errors.New("model ID is empty")
+	}
+
+	req, err := http.NewRequest(method, c.BaseAPI+"v1/models/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.AddHeaders(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, openai.NewAPIError(resp)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,69 @@
+package inmodels
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+)
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/models":
+			fmt.Fprintf(w, `{"object":"list","data":[`+
+				`{"id":"%s","object":"model","created":1686935002,"owned_by":"system"},`+
+				`{"id":"gpt-new","object":"model","created":1686935002,"owned_by":"system"},`+
+				`{"id":"%s","object":"model","created":1686935002,"owned_by":"openai-internal"},`+
+				`{"id":"ft:gpt-4o-mini:org::abc","object":"model","created":1686935002,"owned_by":"org"}]}`,
+				models.Default, models.TTS1)
+		case "GET /v1/models/" + models.Default:
+			fmt.Fprintf(w, `{"id":"%s","object":"model","created":1686935002,"owned_by":"system"}`, models.Default)
+		case "DELETE /v1/models/ft:gpt-4o-mini:org::abc":
+			fmt.Fprint(w, `{"id":"ft:gpt-4o-mini:org::abc","object":"model","deleted":true}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"message":"The model does not exist","type":"invalid_request_error","code":"model_not_found"}}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	list, err := client.List()
+	require.NoError(t, err)
+	require.Len(t, list, 4)
+	require.False(t, list[0].IsFineTuned())
+	require.False(t, list[2].IsFineTuned())
+	require.True(t, list[3].IsFineTuned())
+	require.Equal(t, 2023, list[0].CreatedAt().UTC().Year())
+
+	model, err := client.Retrieve(models.Default)
+	require.NoError(t, err)
+	require.Equal(t, models.Default, model.ID)
+
+	_, err = client.Retrieve("gpt-missing")
+	var apiErr *openai.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, "model_not_found", apiErr.Code)
+
+	require.NoError(t, client.Delete("ft:gpt-4o-mini:org::abc"))
+	require.Error(t, client.Delete("ft:gpt-4o-mini:org::missing"))
+
+	diff, err := client.DiffData()
+	require.NoError(t, err)
+	require.Equal(t, []string{"gpt-new"}, diff.Unknown)
+	require.Contains(t, diff.Retired, models.DefaultMini)
+	require.NotContains(t, diff.Retired, models.Default)
+	require.NotContains(t, diff.Retired, models.TTS1) // owned by "openai-internal"
+	require.NotContains(t, diff.Retired, "")
+	require.False(t, diff.Empty())
+}
@@ -0,0 +1,97 @@
+// Package inrealtime provides a wrapper for the OpenAI Realtime API.
+package inrealtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+	"github.com/unkn0wncode/openai/realtime"
+	"github.com/unkn0wncode/openai/tools"
+)
+
+// Client is a client for the OpenAI Realtime API.
+type Client struct {
+	*openai.Config
+}
+
+// NewClient creates a new Realtime client.
+func NewClient(config *openai.Config) *Client {
+	return &Client{Config: config}
+}
+
+// interface conformity checks
+var (
+	_ realtime.Service = (*Client)(nil)
+	_ realtime.Session = (*session)(nil)
+)
+
+// Connect opens a new realtime session.
+func (c *Client) Connect(ctx context.Context, config *realtime.SessionConfig) (realtime.Session, error) {
+	model := models.GPTRealtime
+	if config != nil && config.Model != "" {
+		model = config.Model
+	}
+
+	conn, err := c.DialWebSocket(ctx, "v1/realtime", url.Values{"model": {model}}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newSession(c, conn)
+	if config != nil {
+		if err := s.Update(config); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// functionTool is a function definition in the format of Realtime API.
+type functionTool struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+// resolveTools finds tools and functions by name in the registry.
+func (c *Client) resolveTools(names []string) ([]any, error) {
+	toolList := make([]any, 0, len(names))
+	for _, name := range names {
+		if t, ok := c.Tools.GetTool(name); ok && t.Type != "function" {
+			toolList = append(toolList, t)
+			continue
+		}
+
+		f, ok := c.Tools.GetFunction(name)
+		if !ok {
+			return nil, fmt.Errorf("tool/function '%s' is not registered", name)
+		}
+		toolList = append(toolList, functionTool{
+			Type:        "function",
+			Name:        f.Name,
+			Description: f.Description,
+			Parameters:  f.ParamsSchema,
+		})
+	}
+
+	return toolList, nil
+}
+
+// findFunction returns the function to execute for a call by name.
+// Returns false if the function is not registered or has no implementation.
+func (c *Client) findFunction(name string) (tools.FunctionCall, bool) {
+	if t, ok := c.Tools.GetTool(name); ok && t.Function.F != nil {
+		return t.Function, true
+	}
+	if f, ok := c.Tools.GetFunction(name); ok && f.F != nil {
+		return f, true
+	}
+	return tools.FunctionCall{}, false
+}
@@ -0,0 +1,394 @@
+package inrealtime
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/realtime"
+	"github.com/unkn0wncode/openai/tools"
+
+	"github.com/gorilla/websocket"
+)
+
+// session implements realtime.Session over a WebSocket connection.
+// The connection is not restored when lost because the conversation lives on the server.
+type session struct {
+	client *Client
+	conn   *websocket.Conn
+	opts   openai.WebSocketOptions
+
+	writeMu sync.Mutex
+
+	mu              sync.Mutex
+	returnToolCalls bool
+	callCounts      map[string]int // function calls in the current chain of responses
+	closed          bool
+
+	events    chan any
+	closing   chan struct{}
+	closeOnce sync.Once
+
+	current any
+	err     error
+}
+
+func newSession(c *Client, conn *websocket.Conn) *session {
+	s := &session{
+		client:     c,
+		conn:       conn,
+		opts:       c.Config.WebSocket,
+		callCounts: map[string]int{},
+		events:     make(chan any, 64),
+		closing:    make(chan struct{}),
+	}
+	if s.opts.PingInterval > 0 && s.opts.PongTimeout <= 0 {
+		s.opts.PongTimeout = s.opts.PingInterval
+	}
+
+	stop := make(chan struct{})
+	if s.opts.PingInterval > 0 {
+		s.extendReadDeadline()
+		conn.SetPongHandler(func(string) error {
+			s.extendReadDeadline()
+			return nil
+		})
+		go s.keepalive(stop)
+	}
+	go s.readLoop(stop)
+
+	return s
+}
+
+// extendReadDeadline gives the server one ping interval plus pong timeout to send anything.
+func (s *session) extendReadDeadline() {
+	if s.opts.PingInterval <= 0 {
+		return
+	}
+	//nolint:errcheck // fails only on a broken connection, which the next read reports
+	s.conn.SetReadDeadline(time.Now().Add(s.opts.PingInterval + s.opts.PongTimeout))
+}
+
+// keepalive pings the server until the read loop stops.
+func (s *session) keepalive(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.opts.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			deadline := time.Now().Add(s.opts.PongTimeout)
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				// the read loop fails on its own after the deadline
+				return
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (s *session) readLoop(stop chan<- struct{}) {
+	defer close(stop)
+	defer close(s.events)
+
+	for {
+		_, message, err := s.conn.ReadMessage()
+		if err != nil {
+			if !s.isClosed() {
+				s.push(fmt.Errorf("websocket read failed: %w", err))
+			}
+			return
+		}
+		s.extendReadDeadline()
+
+		s.client.LogWebSocketPayload("recv", message)
+
+		event, err := realtime.Unmarshal(message)
+		if err != nil {
+			s.push(err)
+			return
+		}
+
+		if !s.push(event) {
+			return
+		}
+
+		if done, ok := event.(realtime.ResponseDone); ok {
+			s.handleResponseDone(done)
+		}
+	}
+}
+
+// push delivers an event to the consumer, returns false if the session is closing.
+func (s *session) push(event any) bool {
+	select {
+	case s.events <- event:
+		return true
+	case <-s.closing:
+		return false
+	}
+}
+
+func (s *session) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// handleResponseDone executes function calls from the response, if any, in the background.
+func (s *session) handleResponseDone(done realtime.ResponseDone) {
+	s.mu.Lock()
+	returnToolCalls := s.returnToolCalls
+	s.mu.Unlock()
+	if returnToolCalls {
+		return
+	}
+
+	var calls []realtime.Item
+	for _, item := range done.Response.Output {
+		if item.Type == "function_call" {
+			calls = append(calls, item)
+		}
+	}
+	if len(calls) == 0 {
+		s.mu.Lock()
+		clear(s.callCounts)
+		s.mu.Unlock()
+		return
+	}
+
+	go s.runFunctions(calls)
+}
+
+// runFunctions executes registered functions, adds their outputs to the conversation
+// and requests a new response. Calls of unknown functions or functions without
+// implementation are left to the consumer.
+func (s *session) runFunctions(calls []realtime.Item) {
+	var (
+		executed   int
+		doNotReply bool
+		limited    bool
+	)
+	for _, call := range calls {
+		f, ok := s.client.findFunction(call.Name)
+		if !ok {
+			continue
+		}
+
+		result, err := f.F([]byte(call.Arguments))
+		switch {
+		case err == nil:
+		case errors.Is(err, tools.ErrDoNotRespond):
+			doNotReply = true
+			result = tools.TextDoNotRespond
+		default:
+			s.client.Log.Warn(fmt.Sprintf("failed to execute function '%s': %v", call.Name, err))
+			result = fmt.Sprintf("Function failed with error: %v", err)
+		}
+
+		if err := s.CreateItem(realtime.FunctionCallOutput(call.CallID, result)); err != nil {
+			s.client.Log.Warn(fmt.Sprintf("failed to send function call output: %v", err))
+			return
+		}
+		executed++
+
+		if f.CallLimit > 0 {
+			s.mu.Lock()
+			s.callCounts[f.Name]++
+			if s.callCounts[f.Name] >= f.CallLimit {
+				s.client.Log.Warn(fmt.Sprintf(
+					"Function '%s' has reached its CallLimit (%d) times, forcing a response without tools",
+					f.Name, s.callCounts[f.Name],
+				))
+				limited = true
+			}
+			s.mu.Unlock()
+		}
+	}
+
+	// wait for remaining calls to be answered by the consumer
+	if executed < len(calls) || doNotReply {
+		return
+	}
+
+	var config *realtime.ResponseConfig
+	if limited {
+		config = &realtime.ResponseConfig{ToolChoice: "none"}
+	}
+	if err := s.CreateResponse(config); err != nil {
+		s.client.Log.Warn(fmt.Sprintf("failed to request response after function calls: %v", err))
+	}
+}
+
+// SendEvent sends an arbitrary client event.
+func (s *session) SendEvent(event any) error {
+	data, err := openai.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal realtime event: %w", err)
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if s.isClosed() {
+		return errors.New("realtime session is closed")
+	}
+
+	s.client.LogWebSocketPayload("send", data)
+	if err := s.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		return fmt.Errorf("failed to send realtime event: %w", err)
+	}
+
+	return nil
+}
+
+// Update sends session.update with given config.
+func (s *session) Update(config *realtime.SessionConfig) error {
+	if config == nil {
+		return errors.New("session config is nil")
+	}
+
+	type Alias realtime.SessionConfig
+	sessionConfig := *config
+	sessionConfig.Type = "realtime"
+	payload := struct {
+		*Alias
+		Tools []any `json:"tools,omitempty"`
+	}{Alias: (*Alias)(&sessionConfig)}
+
+	if len(config.Tools) > 0 {
+		toolList, err := s.client.resolveTools(config.Tools)
+		if err != nil {
+			return err
+		}
+		payload.Tools = toolList
+	}
+
+	if err := s.SendEvent(struct {
+		Type    string `json:"type"`
+		Session any    `json:"session"`
+	}{"session.update", payload}); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.returnToolCalls = config.ReturnToolCalls
+	s.mu.Unlock()
+	return nil
+}
+
+// AppendAudio sends input_audio_buffer.append with given audio.
+func (s *session) AppendAudio(audio []byte) error {
+	return s.SendEvent(struct {
+		Type  string `json:"type"`
+		Audio string `json:"audio"`
+	}{"input_audio_buffer.append", base64.StdEncoding.EncodeToString(audio)})
+}
+
+// CommitAudio sends input_audio_buffer.commit.
+func (s *session) CommitAudio() error {
+	return s.sendType("input_audio_buffer.commit")
+}
+
+// ClearAudio sends input_audio_buffer.clear.
+func (s *session) ClearAudio() error {
+	return s.sendType("input_audio_buffer.clear")
+}
+
+// CreateItem sends conversation.item.create.
+func (s *session) CreateItem(item realtime.Item) error {
+	return s.SendEvent(struct {
+		Type string        `json:"type"`
+		Item realtime.Item `json:"item"`
+	}{"conversation.item.create", item})
+}
+
+// CreateResponse sends response.create with optional config.
+func (s *session) CreateResponse(config *realtime.ResponseConfig) error {
+	if config == nil {
+		return s.sendType("response.create")
+	}
+
+	type Alias realtime.ResponseConfig
+	payload := struct {
+		*Alias
+		Tools []any `json:"tools,omitempty"`
+	}{Alias: (*Alias)(config)}
+
+	if len(config.Tools) > 0 {
+		toolList, err := s.client.resolveTools(config.Tools)
+		if err != nil {
+			return err
+		}
+		payload.Tools = toolList
+	}
+
+	return s.SendEvent(struct {
+		Type     string `json:"type"`
+		Response any    `json:"response"`
+	}{"response.create", payload})
+}
+
+// CancelResponse sends response.cancel.
+func (s *session) CancelResponse() error {
+	return s.sendType("response.cancel")
+}
+
+// sendType sends an event without fields other than type.
+func (s *session) sendType(eventType string) error {
+	return s.SendEvent(struct {
+		Type string `json:"type"`
+	}{eventType})
+}
+
+// Next waits for the next server event.
+func (s *session) Next() bool {
+	event, ok := <-s.events
+	if !ok {
+		s.current = nil
+		return false
+	}
+	if err, isErr := event.(error); isErr {
+		s.current = nil
+		s.err = err
+		return false
+	}
+	s.current = event
+	return true
+}
+
+// Event returns the current server event.
+func (s *session) Event() any {
+	return s.current
+}
+
+// Err returns the error that ended the session.
+func (s *session) Err() error {
+	return s.err
+}
+
+// Close closes the session.
+func (s *session) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.writeMu.Lock()
+		s.mu.Lock()
+		s.closed = true
+		s.mu.Unlock()
+		close(s.closing)
+
+		//nolint:errcheck // the connection is closed anyway
+		s.conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(time.Second),
+		)
+		s.writeMu.Unlock()
+		err = s.conn.Close()
+	})
+	return err
+}
@@ -0,0 +1,172 @@
+package inrealtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+	"github.com/unkn0wncode/openai/realtime"
+	"github.com/unkn0wncode/openai/tools"
+)
+
+// clientEvent is a client event as received by the fake server.
+type clientEvent struct {
+	Type    string `json:"type"`
+	Audio   string `json:"audio"`
+	Session struct {
+		Type  string `json:"type"`
+		Tools []struct {
+			Type string `json:"type"`
+			Name string `json:"name"`
+		} `json:"tools"`
+	} `json:"session"`
+	Item realtime.Item `json:"item"`
+}
+
+func readClientEvent(t *testing.T, conn *websocket.Conn) clientEvent {
+	t.Helper()
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	var event clientEvent
+	require.NoError(t, json.Unmarshal(data, &event))
+	return event
+}
+
+func writeServerEvent(t *testing.T, conn *websocket.Conn, format string, args ...any) {
+	t.Helper()
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, fmt.Appendf(nil, format, args...)))
+}
+
+func TestSession(t *testing.T) {
+	t.Parallel()
+
+	var upgrader websocket.Upgrader
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/realtime", r.URL.Path)
+		assert.Equal(t, models.GPTRealtime, r.URL.Query().Get("model"))
+		assert.Equal(t, "Bearer test", r.Header.Get("Authorization"))
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		writeServerEvent(t, conn, `{"type":"session.created","event_id":"e1","session":{"id":"sess_1","model":%q}}`, models.GPTRealtime)
+
+		update := readClientEvent(t, conn)
+		assert.Equal(t, "session.update", update.Type)
+		assert.Equal(t, "realtime", update.Session.Type)
+		assert.Len(t, update.Session.Tools, 1)
+		assert.Equal(t, "get_weather", update.Session.Tools[0].Name)
+		writeServerEvent(t, conn, `{"type":"session.updated","event_id":"e2","session":{"id":"sess_1"}}`)
+
+		appended := readClientEvent(t, conn)
+		assert.Equal(t, "input_audio_buffer.append", appended.Type)
+		assert.Equal(t, "AQID", appended.Audio)
+		assert.Equal(t, "input_audio_buffer.commit", readClientEvent(t, conn).Type)
+		writeServerEvent(t, conn, `{"type":"input_audio_buffer.committed","item_id":"item_1"}`)
+		assert.Equal(t, "response.create", readClientEvent(t, conn).Type)
+
+		// the model calls a function, the client executes it and asks for a new response
+		writeServerEvent(t, conn, `{"type":"response.created","response":{"id":"resp_1","status":"in_progress"}}`)
+		writeServerEvent(t, conn, `{"type":"response.done","response":{"id":"resp_1","status":"completed","output":[
+			{"type":"function_call","call_id":"call_1","name":"get_weather","arguments":"{\"city\":\"Paris\"}"}
+		]}}`)
+
+		output := readClientEvent(t, conn)
+		assert.Equal(t, "conversation.item.create", output.Type)
+		assert.Equal(t, "function_call_output", output.Item.Type)
+		assert.Equal(t, "call_1", output.Item.CallID)
+		assert.Equal(t, "sunny in Paris", output.Item.Output)
+		assert.Equal(t, "response.create", readClientEvent(t, conn).Type)
+
+		writeServerEvent(t, conn, `{"type":"response.created","response":{"id":"resp_2","status":"in_progress"}}`)
+		writeServerEvent(t, conn, `{"type":"response.output_audio.delta","response_id":"resp_2","delta":"BAUG"}`)
+		writeServerEvent(t, conn, `{"type":"response.output_audio_transcript.done","response_id":"resp_2","transcript":"It's sunny."}`)
+		writeServerEvent(t, conn, `{"type":"response.new_event_type"}`)
+		writeServerEvent(t, conn, `{"type":"response.done","response":{"id":"resp_2","status":"completed","output":[
+			{"type":"message","role":"assistant","content":[{"type":"output_audio","transcript":"It's sunny."}]}
+		]}}`)
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	require.NoError(t, config.Tools.CreateFunction(tools.FunctionCall{
+		Name:         "get_weather",
+		Description:  "Returns weather in a city",
+		ParamsSchema: json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}}}`),
+		F: func(params json.RawMessage) (string, error) {
+			var args struct{ City string }
+			if err := json.Unmarshal(params, &args); err != nil {
+				return "", err
+			}
+			return "sunny in " + args.City, nil
+		},
+	}))
+
+	session, err := NewClient(config).Connect(t.Context(), &realtime.SessionConfig{
+		Instructions: "Be brief.",
+		Tools:        []string{"get_weather"},
+		Audio: &realtime.AudioConfig{
+			Input: &realtime.AudioInput{Format: &realtime.FormatPCM16},
+		},
+	})
+	require.NoError(t, err)
+
+	require.True(t, session.Next())
+	require.Equal(t, "sess_1", session.Event().(realtime.SessionCreated).Session.ID)
+	require.True(t, session.Next())
+	require.IsType(t, realtime.SessionUpdated{}, session.Event())
+
+	require.NoError(t, session.AppendAudio([]byte{1, 2, 3}))
+	require.NoError(t, session.CommitAudio())
+	require.True(t, session.Next())
+	require.Equal(t, "item_1", session.Event().(realtime.InputAudioBufferCommitted).ItemID)
+	require.NoError(t, session.CreateResponse(nil))
+
+	var (
+		done       []string
+		audio      []byte
+		transcript string
+		unknown    int
+	)
+	for len(done) < 2 && session.Next() {
+		switch e := session.Event().(type) {
+		case realtime.ResponseDone:
+			done = append(done, e.Response.ID)
+		case realtime.ResponseOutputAudioDelta:
+			chunk, err := e.Audio()
+			require.NoError(t, err)
+			audio = append(audio, chunk...)
+		case realtime.ResponseOutputAudioTranscriptDone:
+			transcript = e.Transcript
+		case realtime.UnknownEvent:
+			unknown++
+		}
+	}
+	require.NoError(t, session.Err())
+	require.Equal(t, []string{"resp_1", "resp_2"}, done)
+	require.Equal(t, []byte{4, 5, 6}, audio)
+	require.Equal(t, "It's sunny.", transcript)
+	require.Equal(t, 1, unknown)
+
+	require.NoError(t, session.Close())
+	require.False(t, session.Next())
+	require.NoError(t, session.Err())
+	require.Error(t, session.CancelResponse())
+}
@@ -0,0 +1,52 @@
+package inresponses
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/unkn0wncode/openai/models"
+	"github.com/unkn0wncode/openai/responses"
+)
+
+// MarshalBatchBody returns the body of the request as Send would send it: with resolved model,
+// validated and with tools from the registry. Features of Send that need more than
+// one request, like LocalConversation or ContextWindow, are not supported.
+func (c *Client) MarshalBatchBody(req *responses.Request) ([]byte, error) {
+	switch {
+	case req == nil:
+		return nil, fmt.Errorf("request is nil")
+	case req.Input == nil:
+		return nil, fmt.Errorf("input is required")
+	case req.Stream, req.Background:
+		return nil, fmt.Errorf("Stream and Background are not supported in batches")
+	case req.LocalConversation != nil, req.ContextWindow != nil:
+		return nil, fmt.Errorf("LocalConversation and ContextWindow are only supported by Send")
+	}
+
+	data := req.Clone()
+	data.Model = c.ResolveModel(data.Model)
+	if err := c.validateRequest(data); err != nil {
+		return nil, err
+	}
+	return c.marshalRequest(data)
+}
+
+// ParseBatchBody parses a response body from batch output, returns the response
+// and its cost in USD at the regular price.
+func (c *Client) ParseBatchBody(body []byte) (*responses.Response, float64, error) {
+	var res response
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	resp, err := res.checkResponseData()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var cost float64
+	if _, ok := models.Data[res.Model]; ok {
+		cost = c.cost(&res)
+	}
+	return resp, cost, nil
+}
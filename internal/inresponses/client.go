@@ -1,7 +1,6 @@
 package inresponses
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -12,6 +11,7 @@ import (
 	"net/http"
 	"runtime/debug"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/unkn0wncode/openai/content/output"
@@ -59,8 +59,25 @@ func (c *Client) marshalRequest(data *responses.Request) ([]byte, error) {
 		return openai.Marshal((*Alias)(data))
 	}
 
+	toolList, err := c.resolveTools(data.Tools)
+	if err != nil {
+		return nil, err
+	}
+
+	type Alias responses.Request
+	return openai.Marshal(&struct {
+		Tools []tools.Tool `json:"tools"`
+		*Alias
+	}{
+		Tools: toolList,
+		Alias: (*Alias)(data),
+	})
+}
+
+// resolveTools returns definitions of tools and functions by name from the registry.
+func (c *Client) resolveTools(names []string) ([]tools.Tool, error) {
 	var toolList []tools.Tool
-	for _, name := range data.Tools {
+	for _, name := range names {
 		// if given tool is builtin, add it by type
 		if slices.Contains(builtinTools, name) {
 			for _, t := range c.Tools.Tools {
@@ -95,14 +112,57 @@ func (c *Client) marshalRequest(data *responses.Request) ([]byte, error) {
 		return nil, fmt.Errorf("tool/function '%s' is not registered", name)
 	}
 
-	type Alias responses.Request
-	return openai.Marshal(&struct {
-		Tools []tools.Tool `json:"tools"`
-		*Alias
-	}{
-		Tools: toolList,
-		Alias: (*Alias)(data),
-	})
+	return toolList, nil
+}
+
+// validateRequest checks the request against capabilities of its model, including types of
+// tools resolved by name. With AutoStrip, unsupported parameters and tools are removed first,
+// changing the request, so callers pass a copy made for the model of the attempt.
+func (c *Client) validateRequest(data *responses.Request) error {
+	caps, known := models.GetCapabilities(data.Model)
+	// toolSupported reports whether a tool that is not listed by its built-in type is supported
+	toolSupported := func(name string) (toolType string, ok bool) {
+		if !known || slices.Contains(builtinTools, name) {
+			return "", true
+		}
+		toolList, err := c.resolveTools([]string{name})
+		if err != nil || len(toolList) == 0 {
+			// unregistered tools are reported when the request is marshaled
+			return "", true
+		}
+		return toolList[0].Type, caps.SupportsTool(toolList[0].Type)
+	}
+
+	if data.AutoStrip {
+		stripped := data.StripUnsupported()
+		var names []string
+		for _, name := range data.Tools {
+			if _, ok := toolSupported(name); !ok {
+				stripped = append(stripped, "Tools."+name)
+				continue
+			}
+			names = append(names, name)
+		}
+		data.Tools = names
+
+		if len(stripped) > 0 {
+			c.Log.Warn(fmt.Sprintf(
+				"Stripped parameters unsupported by model '%s': %s",
+				data.Model, strings.Join(stripped, ", "),
+			))
+		}
+	}
+
+	errs := []error{data.Validate()}
+	for _, name := range data.Tools {
+		if toolType, ok := toolSupported(name); !ok {
+			errs = append(errs, fmt.Errorf(
+				"%w: model '%s': tool '%s' of type '%s' is not supported",
+				models.ErrUnsupported, data.Model, name, toolType,
+			))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // execute sends request to the Responses API and returns the response.
@@ -111,9 +171,7 @@ func (c *Client) executeRequest(data *responses.Request) (*response, error) {
 		return nil, fmt.Errorf("request is nil")
 	}
 
-	if data.Model == "" {
-		data.Model = models.Default
-	}
+	data.Model = c.ResolveModel(data.Model)
 
 	// Check if we have input
 	if data.Input == nil {
@@ -124,6 +182,24 @@ func (c *Client) executeRequest(data *responses.Request) (*response, error) {
 		return nil, fmt.Errorf("request has 'stream' parameter but was invoked with Send method, use Stream method instead")
 	}
 
+	var res *response
+	err := c.WithFallbacks(data.Model, func(model string) error {
+		// each attempt gets its own copy, so the request keeps its model and parameters
+		attempt := data.Clone()
+		attempt.Model = model
+		var err error
+		res, err = c.postRequest(attempt)
+		return err
+	})
+	return res, err
+}
+
+// postRequest validates the request and sends it to the Responses API as is.
+func (c *Client) postRequest(data *responses.Request) (*response, error) {
+	if err := c.validateRequest(data); err != nil {
+		return nil, err
+	}
+
 	b, err := c.marshalRequest(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
@@ -167,7 +243,7 @@ func (c *Client) executeRequest(data *responses.Request) (*response, error) {
 		return &res, nil
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status: %s, body: %s", resp.Status, string(body))
+		return nil, openai.NewAPIErrorFromBody(resp, body)
 	}
 
 	var res response
@@ -348,7 +424,17 @@ func (c *Client) Send(req *responses.Request) (*responses.Response, error) {
 	return c.send(req, newSendContext())
 }
 func (c *Client) send(req *responses.Request, sc *sendContext) (*responses.Response, error) {
-	respData, err := c.executeRequest(req)
+	apiReq, newItems, err := prepareLocalConversation(req)
+	if err != nil {
+		return nil, err
+	}
+
+	apiReq, report, err := c.fitContext(apiReq)
+	if err != nil {
+		return nil, err
+	}
+
+	respData, err := c.executeRequest(apiReq)
 	if err != nil {
 		return nil, err
 	}
@@ -369,6 +455,14 @@ func (c *Client) send(req *responses.Request, sc *sendContext) (*responses.Respo
 	if err != nil {
 		return nil, err
 	}
+	resp.ContextReport = report
+
+	// record the turn before handling tool calls, so that follow-ups replay it
+	if lc := req.LocalConversation; lc != nil {
+		if err := lc.Store.Append(lc.ID, append(newItems, resp.Outputs...)...); err != nil {
+			return nil, fmt.Errorf("failed to save items to local conversation: %w", err)
+		}
+	}
 
 	// log refusals as warnings
 	for _, refusal := range resp.Refusals() {
@@ -377,10 +471,6 @@ func (c *Client) send(req *responses.Request, sc *sendContext) (*responses.Respo
 
 	// First pass: analyze outputs and categorize them
 	var messages []output.Message
-	var executableCalls []executableFunctionCall
-	var executableCustomCalls []executableCustomToolCall
-	var returnableCalls []output.FunctionCall
-	var returnableCustomCalls []output.CustomToolCall
 	var otherOutputs []output.Any
 	var otherParsedOutputs []any
 
@@ -388,86 +478,30 @@ func (c *Client) send(req *responses.Request, sc *sendContext) (*responses.Respo
 		switch o := anyOutput.(type) {
 		case output.Message:
 			messages = append(messages, o)
-		case output.FunctionCall:
-			if req.ReturnToolCalls {
-				returnableCalls = append(returnableCalls, o)
-				continue
-			}
-
-			// Get the tool or function from the registered function calls
-			var (
-				F         func(params json.RawMessage) (string, error)
-				callLimit int
-			)
-			if t, ok := c.Tools.GetTool(o.Name); ok {
-				if t.Function.F != nil {
-					F = t.Function.F
-					callLimit = t.Function.CallLimit
-				} else {
-					returnableCalls = append(returnableCalls, o)
-					continue
-				}
-			} else if f, ok := c.Tools.GetFunction(o.Name); ok {
-				if f.F != nil {
-					F = f.F
-					callLimit = f.CallLimit
-				} else {
-					returnableCalls = append(returnableCalls, o)
-					continue
-				}
-			} else {
-				return nil, fmt.Errorf("tool/function '%s' is not registered", o.Name)
-			}
-
-			executableCalls = append(executableCalls, executableFunctionCall{
-				Name:      o.Name,
-				CallID:    o.CallID,
-				Arguments: []byte(o.Arguments),
-				F:         F,
-				CallLimit: callLimit,
-			})
-		case output.CustomToolCall:
-			if req.ReturnToolCalls {
-				returnableCustomCalls = append(returnableCustomCalls, o)
-				continue
-			}
-
-			// Get the tool by name from the registered tools
-			t, ok := c.Tools.GetTool(o.Name)
-			if !ok {
-				return nil, fmt.Errorf("tool '%s' is not registered", o.Name)
-			}
-			if t.Type != "custom" {
-				return nil, fmt.Errorf("tool '%s' is not a custom tool", o.Name)
-			}
-			if t.Custom == nil {
-				returnableCustomCalls = append(returnableCustomCalls, o)
-				continue
-			}
-
-			executableCustomCalls = append(executableCustomCalls, executableCustomToolCall{
-				Name:   o.Name,
-				CallID: o.CallID,
-				Input:  o.Input,
-				F:      t.Custom,
-			})
+		case output.FunctionCall, output.CustomToolCall:
+			// handled by collectToolCalls
 		default:
 			otherOutputs = append(otherOutputs, resp.Outputs[i])
 			otherParsedOutputs = append(otherParsedOutputs, o)
 		}
 	}
 
+	calls, err := c.collectToolCalls(req, resp.ParsedOutputs)
+	if err != nil {
+		return nil, err
+	}
+
 	switch {
 	// Case 1: All outputs are messages/other outputs
-	case len(executableCalls) == 0 && len(executableCustomCalls) == 0 && len(returnableCalls) == 0 && len(returnableCustomCalls) == 0:
+	case !calls.found():
 		return resp, nil
 
 	// Case 2: Any returnable function/custom calls present
-	case len(returnableCalls) > 0 || len(returnableCustomCalls) > 0:
+	case calls.returnable > 0:
 		return resp, nil
 
 	// Case 3: Mix of messages and executable function/custom calls
-	case len(executableCalls) > 0 || len(executableCustomCalls) > 0:
+	case calls.executable():
 		// Handle messages with intermediate handler if set
 		if req.IntermediateMessageHandler != nil {
 			for _, msg := range messages {
@@ -476,74 +510,17 @@ func (c *Client) send(req *responses.Request, sc *sendContext) (*responses.Respo
 		}
 
 		// Execute calls and collect outputs (mixed types)
-		var toolOutputs []output.Any
-		// function calls
-		for _, call := range executableCalls {
-			fResult, err := call.F(call.Arguments)
-			switch {
-			case err == nil:
-			case errors.Is(err, tools.ErrDoNotRespond):
-				// Here we return ID despite error because this error indicates intended behavior
-				return resp, nil
-			default:
-				return nil, fmt.Errorf("failed to execute function '%s': %w", call.Name, err)
-			}
-			// Add function_call_output
-			var anyOut output.Any
-			b, _ := json.Marshal(output.FunctionCallOutput{
-				Type:   "function_call_output",
-				CallID: call.CallID,
-				Output: fResult,
-			})
-			if err := json.Unmarshal(b, &anyOut); err != nil {
-				return nil, fmt.Errorf("failed to prepare function_call_output: %w", err)
-			}
-			toolOutputs = append(toolOutputs, anyOut)
-
-			if call.CallLimit > 0 {
-				sc.callCounts[call.Name]++
-				if sc.callCounts[call.Name] >= call.CallLimit {
-					c.Log.Warn(fmt.Sprintf(
-						"Function '%s' has reached its CallLimit (%d) times, excluding from further tool calls",
-						call.Name, sc.callCounts[call.Name],
-					))
-					sc.blockedTools[call.Name] = struct{}{}
-				}
-			}
+		toolOutputs, err := c.runToolCalls(calls, sc)
+		if errors.Is(err, tools.ErrDoNotRespond) {
+			// Here we return ID despite error because this error indicates intended behavior
+			return resp, nil
 		}
-		// custom tool calls
-		for _, call := range executableCustomCalls {
-			fResult, err := call.F(call.Input)
-			switch {
-			case err == nil:
-			case errors.Is(err, tools.ErrDoNotRespond):
-				return resp, nil
-			default:
-				return nil, fmt.Errorf("failed to execute custom tool '%s': %w", call.Name, err)
-			}
-			// Add custom_tool_call_output
-			var anyOut output.Any
-			b, _ := json.Marshal(output.CustomToolCallOutput{
-				Type:   "custom_tool_call_output",
-				CallID: call.CallID,
-				Output: fResult,
-			})
-			if err := json.Unmarshal(b, &anyOut); err != nil {
-				return nil, fmt.Errorf("failed to prepare custom_tool_call_output: %w", err)
-			}
-			toolOutputs = append(toolOutputs, anyOut)
+		if err != nil {
+			return nil, err
 		}
 
 		// we have tool outputs, send them in a follow-up request
-		followUpReq := req.Clone()
-		followUpReq.Input = toolOutputs
-		followUpReq.PreviousResponseID = resp.ID
-		followUpReq.Tools = filterBlockedTools(followUpReq.Tools, sc.blockedTools)
-		if len(sc.blockedTools) > 0 {
-			followUpReq.ToolChoice = nil
-		}
-
-		followupResp, err := c.send(followUpReq, sc)
+		followupResp, err := c.send(followUpRequest(req, resp.ID, toolOutputs, sc), sc)
 		if err != nil {
 			return nil, err
 		}
@@ -581,6 +558,9 @@ func (c *Client) send(req *responses.Request, sc *sendContext) (*responses.Respo
 		resp.Outputs = combinedOutputs
 		resp.ParsedOutputs = combinedParsedOutputs
 		resp.ID = followupResp.ID
+		if resp.ContextReport == nil {
+			resp.ContextReport = followupResp.ContextReport
+		}
 
 		return resp, nil
 
@@ -593,6 +573,214 @@ func (c *Client) send(req *responses.Request, sc *sendContext) (*responses.Respo
 	return nil, fmt.Errorf("logic error: unreachable code, stack: %s", string(debug.Stack()))
 }
 
+// prepareLocalConversation returns a copy of the request with input prepended by items
+// of its local conversation, and the new input items to be saved after the response.
+// Requests without LocalConversation are returned as is.
+func prepareLocalConversation(req *responses.Request) (*responses.Request, []output.Any, error) {
+	if req == nil || req.LocalConversation == nil {
+		return req, nil, nil
+	}
+
+	switch {
+	case req.LocalConversation.Store == nil:
+		return nil, nil, fmt.Errorf("local conversation store is nil")
+	case req.Conversation != nil:
+		return nil, nil, fmt.Errorf("LocalConversation can't be used with Conversation")
+	case req.PreviousResponseID != "":
+		return nil, nil, fmt.Errorf("LocalConversation can't be used with PreviousResponseID")
+	case req.Background:
+		return nil, nil, fmt.Errorf("LocalConversation can't be used with Background")
+	}
+
+	newItems, err := responses.InputItems(req.Input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	history, err := req.LocalConversation.Items()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load local conversation: %w", err)
+	}
+
+	apiReq := req.Clone()
+	apiReq.Input = append(history, newItems...)
+	return apiReq, newItems, nil
+}
+
+// toolCalls contains tool calls found in response outputs.
+type toolCalls struct {
+	functions []executableFunctionCall
+	custom    []executableCustomToolCall
+	// returnable is the number of calls that can't be executed automatically
+	// and must be returned to the caller
+	returnable int
+}
+
+// found reports whether there are any tool calls.
+func (tc *toolCalls) found() bool {
+	return tc.executable() || tc.returnable > 0
+}
+
+// executable reports whether there are any calls that can be executed automatically.
+func (tc *toolCalls) executable() bool {
+	return len(tc.functions) > 0 || len(tc.custom) > 0
+}
+
+// collectToolCalls finds function and custom tool calls in parsed outputs
+// and looks up their implementations in the registry.
+func (c *Client) collectToolCalls(req *responses.Request, parsedOutputs []any) (*toolCalls, error) {
+	calls := &toolCalls{}
+	for _, anyOutput := range parsedOutputs {
+		switch o := anyOutput.(type) {
+		case output.FunctionCall:
+			if req.ReturnToolCalls {
+				calls.returnable++
+				continue
+			}
+
+			// Get the tool or function from the registered function calls
+			var (
+				F         func(params json.RawMessage) (string, error)
+				callLimit int
+			)
+			if t, ok := c.Tools.GetTool(o.Name); ok {
+				if t.Function.F != nil {
+					F = t.Function.F
+					callLimit = t.Function.CallLimit
+				} else {
+					calls.returnable++
+					continue
+				}
+			} else if f, ok := c.Tools.GetFunction(o.Name); ok {
+				if f.F != nil {
+					F = f.F
+					callLimit = f.CallLimit
+				} else {
+					calls.returnable++
+					continue
+				}
+			} else {
+				return nil, fmt.Errorf("tool/function '%s' is not registered", o.Name)
+			}
+
+			calls.functions = append(calls.functions, executableFunctionCall{
+				Name:      o.Name,
+				CallID:    o.CallID,
+				Arguments: []byte(o.Arguments),
+				F:         F,
+				CallLimit: callLimit,
+			})
+		case output.CustomToolCall:
+			if req.ReturnToolCalls {
+				calls.returnable++
+				continue
+			}
+
+			// Get the tool by name from the registered tools
+			t, ok := c.Tools.GetTool(o.Name)
+			if !ok {
+				return nil, fmt.Errorf("tool '%s' is not registered", o.Name)
+			}
+			if t.Type != "custom" {
+				return nil, fmt.Errorf("tool '%s' is not a custom tool", o.Name)
+			}
+			if t.Custom == nil {
+				calls.returnable++
+				continue
+			}
+
+			calls.custom = append(calls.custom, executableCustomToolCall{
+				Name:   o.Name,
+				CallID: o.CallID,
+				Input:  o.Input,
+				F:      t.Custom,
+			})
+		}
+	}
+
+	return calls, nil
+}
+
+// runToolCalls executes the calls and returns their outputs, updating call limits in sc.
+// Returns tools.ErrDoNotRespond if any tool asked to stop without responding.
+func (c *Client) runToolCalls(calls *toolCalls, sc *sendContext) ([]output.Any, error) {
+	var toolOutputs []output.Any
+	// function calls
+	for _, call := range calls.functions {
+		fResult, err := call.F(call.Arguments)
+		switch {
+		case err == nil:
+		case errors.Is(err, tools.ErrDoNotRespond):
+			return nil, err
+		default:
+			return nil, fmt.Errorf("failed to execute function '%s': %w", call.Name, err)
+		}
+		// Add function_call_output
+		var anyOut output.Any
+		b, _ := json.Marshal(output.FunctionCallOutput{
+			Type:   "function_call_output",
+			CallID: call.CallID,
+			Output: fResult,
+		})
+		if err := json.Unmarshal(b, &anyOut); err != nil {
+			return nil, fmt.Errorf("failed to prepare function_call_output: %w", err)
+		}
+		toolOutputs = append(toolOutputs, anyOut)
+
+		if call.CallLimit > 0 {
+			sc.callCounts[call.Name]++
+			if sc.callCounts[call.Name] >= call.CallLimit {
+				c.Log.Warn(fmt.Sprintf(
+					"Function '%s' has reached its CallLimit (%d) times, excluding from further tool calls",
+					call.Name, sc.callCounts[call.Name],
+				))
+				sc.blockedTools[call.Name] = struct{}{}
+			}
+		}
+	}
+	// custom tool calls
+	for _, call := range calls.custom {
+		fResult, err := call.F(call.Input)
+		switch {
+		case err == nil:
+		case errors.Is(err, tools.ErrDoNotRespond):
+			return nil, err
+		default:
+			return nil, fmt.Errorf("failed to execute custom tool '%s': %w", call.Name, err)
+		}
+		// Add custom_tool_call_output
+		var anyOut output.Any
+		b, _ := json.Marshal(output.CustomToolCallOutput{
+			Type:   "custom_tool_call_output",
+			CallID: call.CallID,
+			Output: fResult,
+		})
+		if err := json.Unmarshal(b, &anyOut); err != nil {
+			return nil, fmt.Errorf("failed to prepare custom_tool_call_output: %w", err)
+		}
+		toolOutputs = append(toolOutputs, anyOut)
+	}
+
+	return toolOutputs, nil
+}
+
+// followUpRequest creates a request sending tool outputs as a continuation of given response.
+func followUpRequest(
+	req *responses.Request, responseID string, toolOutputs []output.Any, sc *sendContext,
+) *responses.Request {
+	followUpReq := req.Clone()
+	followUpReq.Input = toolOutputs
+	// local conversations replay the history instead
+	if req.LocalConversation == nil {
+		followUpReq.PreviousResponseID = responseID
+	}
+	followUpReq.Tools = filterBlockedTools(followUpReq.Tools, sc.blockedTools)
+	if len(sc.blockedTools) > 0 {
+		followUpReq.ToolChoice = nil
+	}
+	return followUpReq
+}
+
 // filterBlockedTools removes blocked tool names from the provided list.
 func filterBlockedTools(tools []string, blocked map[string]struct{}) []string {
 	if len(tools) == 0 || len(blocked) == 0 {
@@ -669,23 +857,11 @@ func (c *Client) Poll(ctx context.Context, id string, interval time.Duration) (*
 	}
 }
 
-// streamEvents sends a request with parameter "stream":true and returns a stream of events as a channel.
-func (c *Client) streamEvents(ctx context.Context, data *responses.Request) (<-chan any, error) {
-	if data == nil {
-		return nil, fmt.Errorf("request is nil")
-	}
-
-	if data.Model == "" {
-		data.Model = models.Default
-	}
-
-	// Check if we have input
-	if data.Input == nil {
-		return nil, fmt.Errorf("input is required")
-	}
-
-	if !data.Stream {
-		return nil, fmt.Errorf("request has no 'stream' parameter but was invoked with Stream method, use Send method instead")
+// openStream validates the streaming request and sends it to the Responses API as is.
+// Returns the response with an event stream body.
+func (c *Client) openStream(ctx context.Context, data *responses.Request) (*http.Response, error) {
+	if err := c.validateRequest(data); err != nil {
+		return nil, err
 	}
 
 	b, err := c.marshalRequest(data)
@@ -699,76 +875,67 @@ func (c *Client) streamEvents(ctx context.Context, data *responses.Request) (<-c
 	}
 	c.AddHeaders(req)
 
-	var resp *http.Response
-	before := time.Now()
-	resp, err = c.HTTPClient.Do(req)
+	// the timeout of the client covers reading the body too, which may take longer for streams
+	resp, err := c.HTTPClient.WithoutTimeout().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
+	if err := openai.CheckStreamResponse(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
 
-	// Use 64KB buffer for better performance with streaming responses
-	reader := bufio.NewReaderSize(resp.Body, 64*1024)
+// streamEvents sends a request with parameter "stream":true and returns a stream of events as a channel.
+func (c *Client) streamEvents(ctx context.Context, data *responses.Request) (<-chan any, error) {
+	if data == nil {
+		return nil, fmt.Errorf("request is nil")
+	}
 
-	stream := make(chan any)
-	go func() {
-		defer resp.Body.Close()
-		defer close(stream)
+	if data.LocalConversation != nil {
+		return nil, fmt.Errorf("LocalConversation is only supported by Send")
+	}
 
-		eventCount := 0
-		defer func() {
-			duration := time.Since(before)
-			c.Log.Debug(
-				fmt.Sprintf("Stream finished after %s, got %d events",
-					duration, eventCount),
-			)
-		}()
-
-		for {
-			select {
-			case <-ctx.Done():
-				stream <- ctx.Err()
-				return
-			default:
-			}
+	if data.ContextWindow != nil {
+		return nil, fmt.Errorf("ContextWindow is only supported by Send")
+	}
 
-			chunk, err := reader.ReadBytes('\n')
-			switch {
-			case err == nil:
-			case errors.Is(err, io.EOF):
-				return
-			default:
-				stream <- err
-				return
-			}
+	data.Model = c.ResolveModel(data.Model)
 
-			// check what we got
-			switch {
-			case len(chunk) == 0, string(chunk) == "\n":
-				// separator between events, skip
-				continue
-			case bytes.HasPrefix(chunk, []byte("event: ")):
-				// event header with event type, skip
-				continue
-			case bytes.HasPrefix(chunk, []byte("data: ")):
-				// event data, handle
-			default:
-				// unexpected payload, return error
-				stream <- fmt.Errorf("unexpected payload: %s", string(chunk))
-				return
-			}
+	// Check if we have input
+	if data.Input == nil {
+		return nil, fmt.Errorf("input is required")
+	}
 
-			// trim prefix and unmarshal data
-			eventCount++
-			chunk = chunk[len("data: "):]
-			event, err := streaming.Unmarshal(chunk)
-			if err != nil {
-				stream <- fmt.Errorf("failed to unmarshal event data: %w", err)
-				return
-			}
+	if !data.Stream {
+		return nil, fmt.Errorf("request has no 'stream' parameter but was invoked with Stream method, use Send method instead")
+	}
 
-			stream <- event
+	var resp *http.Response
+	before := time.Now()
+	err := c.WithFallbacks(data.Model, func(model string) error {
+		// each attempt gets its own copy, so the request keeps its model and parameters
+		attempt := data.Clone()
+		attempt.Model = model
+		var err error
+		resp, err = c.openStream(ctx, attempt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eventCount := 0
+	stream := openai.StreamSSE(ctx, resp.Body, func(sse *openai.SSEEvent) (any, error) {
+		eventCount++
+		event, err := streaming.Unmarshal(sse.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event data: %w", err)
 		}
-	}()
+		return event, nil
+	}, func() {
+		c.Log.Debug(fmt.Sprintf("Stream finished after %s, got %d events", time.Since(before), eventCount))
+	})
 
 	return stream, nil
 }
@@ -0,0 +1,523 @@
+package inresponses
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/content/output"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/models"
+	"github.com/unkn0wncode/openai/responses"
+	"github.com/unkn0wncode/openai/responses/convstore"
+	"github.com/unkn0wncode/openai/responses/streaming"
+	"github.com/unkn0wncode/openai/roles"
+	"github.com/unkn0wncode/openai/tools"
+)
+
+func TestSendLocalConversation(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		Type    string `json:"type"`
+		Role    string `json:"role"`
+		Content any    `json:"content"`
+		CallID  string `json:"call_id"`
+	}
+	var (
+		mu       sync.Mutex
+		requests []struct {
+			Input              []item `json:"input"`
+			PreviousResponseID string `json:"previous_response_id"`
+		}
+	)
+	// replies are the outputs of consecutive responses
+	replies := []string{
+		`{"type":"message","id":"msg_1","role":"assistant","status":"completed","content":[{"type":"output_text","text":"hello","annotations":[]}]}`,
+		`{"type":"reasoning","id":"rs_1","summary":[],"encrypted_content":"secret"},
+		 {"type":"function_call","id":"fc_1","call_id":"call_1","name":"get_time","arguments":"{}","status":"completed"}`,
+		`{"type":"message","id":"msg_2","role":"assistant","status":"completed","content":[{"type":"output_text","text":"noon","annotations":[]}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var req struct {
+			Input              []item `json:"input"`
+			PreviousResponseID string `json:"previous_response_id"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+
+		n := len(requests)
+		fmt.Fprintf(w, `{"id":"resp_%d","object":"response","status":"completed","model":"gpt-5-mini","output":[%s]}`, n, replies[n-1])
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	require.NoError(t, config.Tools.CreateFunction(tools.FunctionCall{
+		Name:         "get_time",
+		Description:  "Returns current time",
+		ParamsSchema: tools.EmptyParamsSchema,
+		F:            func(json.RawMessage) (string, error) { return "12:00", nil },
+	}))
+	client := NewClient(config)
+
+	store := convstore.NewMemory()
+	lc := &responses.LocalConversation{Store: store, ID: "conv"}
+
+	resp, err := client.Send(&responses.Request{Input: "hi", LocalConversation: lc})
+	require.NoError(t, err)
+	require.Equal(t, "hello", resp.FirstText())
+
+	resp, err = client.Send(&responses.Request{Input: "what time is it?", Tools: []string{"get_time"}, LocalConversation: lc})
+	require.NoError(t, err)
+	require.Equal(t, "noon", resp.LastText())
+
+	require.Len(t, requests, 3)
+	require.Len(t, requests[0].Input, 1)
+	require.Equal(t, "hi", requests[0].Input[0].Content)
+
+	// the second request replays the first turn
+	require.Len(t, requests[1].Input, 3)
+	require.Equal(t, "assistant", requests[1].Input[1].Role)
+	require.Equal(t, "what time is it?", requests[1].Input[2].Content)
+
+	// the follow-up with the tool output replays everything instead of using previous_response_id
+	require.Empty(t, requests[2].PreviousResponseID)
+	var types []string
+	for _, in := range requests[2].Input {
+		types = append(types, in.Type)
+	}
+	require.Equal(t, []string{"message", "message", "message", "reasoning", "function_call", "function_call_output"}, types)
+
+	items, err := store.Items("conv")
+	require.NoError(t, err)
+	require.Len(t, items, 7)
+	require.Equal(t, "message", items[6].Type)
+
+	_, err = client.Send(&responses.Request{Input: "hi", LocalConversation: lc, PreviousResponseID: "resp_1"})
+	require.Error(t, err)
+}
+
+func TestSendContextWindow(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		Model           string `json:"model"`
+		Instructions    string `json:"instructions"`
+		Input           any    `json:"input"`
+		MaxOutputTokens int    `json:"max_output_tokens"`
+		Reasoning       *struct {
+			Effort string `json:"effort"`
+		} `json:"reasoning"`
+		ContextManagement []struct {
+			CompactThreshold int `json:"compact_threshold"`
+		} `json:"context_management"`
+	}
+	var (
+		mu       sync.Mutex
+		requests []request
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var req request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+
+		text := "ok"
+		if strings.Contains(req.Instructions, "Keep the summary") {
+			text = "user likes tea"
+		}
+		fmt.Fprintf(w, `{"id":"resp_%d","object":"response","status":"completed","model":%q,"output":[`+
+			`{"type":"message","id":"msg_1","role":"assistant","status":"completed","content":[{"type":"output_text","text":%q,"annotations":[]}]}]}`,
+			len(requests), req.Model, text)
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	history := []any{
+		output.Message{Role: roles.Developer, Content: "Be brief."},
+		output.Message{Role: roles.User, Content: "I like tea."},
+		output.Message{Role: roles.Assistant, Content: "Noted."},
+		output.FunctionCall{CallID: "call_1", Name: "get_time", Arguments: "{}"},
+		output.FunctionCallOutput{CallID: "call_1", Output: "12:00"},
+		output.Message{Role: roles.User, Content: "What time is it?"},
+		output.Message{Role: roles.Assistant, Content: "Noon."},
+		output.Message{Role: roles.User, Content: "What do I like?"},
+	}
+	// every item costs 100 tokens
+	countItems := func(req *responses.Request) (int, error) {
+		items, err := responses.InputItems(req.Input)
+		return 100 * len(items), err
+	}
+	inputRoles := func(req request) []string {
+		var list []string
+		for _, item := range req.Input.([]any) {
+			role, _ := item.(map[string]any)["role"].(string)
+			list = append(list, role)
+		}
+		return list
+	}
+
+	t.Run("DropOldest", func(t *testing.T) {
+		var reports []responses.ContextReport
+		resp, err := client.Send(&responses.Request{
+			Input: history,
+			ContextWindow: &responses.ContextWindow{
+				Strategy:       responses.ContextDropOldest,
+				MaxInputTokens: 450,
+				CountTokens:    countItems,
+				OnTrim:         func(r responses.ContextReport) { reports = append(reports, r) },
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp.ContextReport)
+		require.Equal(t, []responses.ContextReport{*resp.ContextReport}, reports)
+		require.Equal(t, 800, resp.ContextReport.TokensBefore)
+		require.Equal(t, 400, resp.ContextReport.TokensAfter)
+		require.Len(t, resp.ContextReport.Dropped, 4)
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, []string{"developer", "user", "assistant", "user"}, inputRoles(requests[len(requests)-1]))
+	})
+
+	t.Run("Summarize", func(t *testing.T) {
+		resp, err := client.Send(&responses.Request{
+			Input: history,
+			ContextWindow: &responses.ContextWindow{
+				Strategy:         responses.ContextSummarize,
+				MaxInputTokens:   500,
+				SummaryMaxTokens: 100,
+				CountTokens:      countItems,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "user likes tea", resp.ContextReport.Summary)
+		require.Equal(t, 500, resp.ContextReport.TokensAfter)
+
+		mu.Lock()
+		defer mu.Unlock()
+		summaryReq, req := requests[len(requests)-2], requests[len(requests)-1]
+		require.Equal(t, models.DefaultNano, summaryReq.Model)
+		require.Equal(t, 100, summaryReq.MaxOutputTokens)
+		require.NotNil(t, summaryReq.Reasoning)
+		require.Equal(t, "none", summaryReq.Reasoning.Effort)
+		require.Contains(t, summaryReq.Input, "user: I like tea.")
+		require.Contains(t, summaryReq.Input, "assistant called function get_time({})")
+		require.Equal(t, []string{"developer", "developer", "user", "assistant", "user"}, inputRoles(req))
+	})
+
+	t.Run("SummaryReasoning", func(t *testing.T) {
+		for model, want := range map[string]struct {
+			effort    string
+			maxTokens int
+		}{
+			models.GPT5Nano:  {"minimal", 100},
+			models.GPTO4Mini: {"low", 100 + summaryReasoningTokens},
+		} {
+			_, err := client.Send(&responses.Request{
+				Input: history,
+				ContextWindow: &responses.ContextWindow{
+					Strategy:         responses.ContextSummarize,
+					MaxInputTokens:   500,
+					SummaryModel:     model,
+					SummaryMaxTokens: 100,
+					CountTokens:      countItems,
+				},
+			})
+			require.NoError(t, err)
+
+			mu.Lock()
+			summaryReq := requests[len(requests)-2]
+			mu.Unlock()
+			require.Equal(t, model, summaryReq.Model)
+			require.NotNil(t, summaryReq.Reasoning)
+			require.Equal(t, want.effort, summaryReq.Reasoning.Effort)
+			require.Equal(t, want.maxTokens, summaryReq.MaxOutputTokens)
+		}
+	})
+
+	t.Run("TooLong", func(t *testing.T) {
+		_, err := client.Send(&responses.Request{
+			Input: history,
+			ContextWindow: &responses.ContextWindow{
+				Strategy:       responses.ContextDropOldest,
+				MaxInputTokens: 150,
+				CountTokens:    countItems,
+			},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("ServerCompaction", func(t *testing.T) {
+		resp, err := client.Send(&responses.Request{
+			Input: history,
+			ContextWindow: &responses.ContextWindow{
+				Strategy:       responses.ContextServerCompaction,
+				MaxInputTokens: 5000,
+			},
+		})
+		require.NoError(t, err)
+		require.Nil(t, resp.ContextReport)
+
+		mu.Lock()
+		defer mu.Unlock()
+		req := requests[len(requests)-1]
+		require.Len(t, req.Input, len(history))
+		require.Len(t, req.ContextManagement, 1)
+		require.Equal(t, 5000, req.ContextManagement[0].CompactThreshold)
+	})
+}
+
+func TestSendValidation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests []map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var req map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		fmt.Fprint(w, `{"id":"resp_1","object":"response","status":"completed","model":"o1-mini","output":[`+
+			`{"type":"message","id":"msg_1","role":"assistant","status":"completed","content":[{"type":"output_text","text":"ok","annotations":[]}]}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	require.NoError(t, config.Tools.CreateFunction(tools.FunctionCall{
+		Name:         "get_time",
+		Description:  "Returns current time",
+		ParamsSchema: tools.EmptyParamsSchema,
+	}))
+	client := NewClient(config)
+
+	// functions are resolved by name to check their type
+	_, err := client.Send(&responses.Request{Model: models.GPTO1Mini, Input: "hi", Temperature: 0.5, Tools: []string{"get_time"}})
+	require.ErrorIs(t, err, models.ErrUnsupported)
+	require.ErrorContains(t, err, "get_time")
+	require.Empty(t, requests)
+
+	resp, err := client.Send(&responses.Request{
+		Model:       models.GPTO1Mini,
+		Input:       "hi",
+		Temperature: 0.5,
+		Tools:       []string{"get_time"},
+		AutoStrip:   true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp.FirstText())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, requests, 1)
+	require.NotContains(t, requests[0], "temperature")
+	require.NotContains(t, requests[0], "tools")
+}
+
+func TestSendAutoStripFallback(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests []map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var req map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		if req["model"] == models.GPTO1Mini {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":{"message":"The server had an error","type":"server_error"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"resp_1","object":"response","status":"completed","model":"gpt-4.1","output":[`+
+			`{"type":"message","id":"msg_1","role":"assistant","status":"completed","content":[{"type":"output_text","text":"ok","annotations":[]}]}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	config.Models.SetFallbacks(models.GPTO1Mini, models.GPT4Quasar)
+	require.NoError(t, config.Tools.CreateFunction(tools.FunctionCall{
+		Name:         "get_time",
+		Description:  "Returns current time",
+		ParamsSchema: tools.EmptyParamsSchema,
+	}))
+	client := NewClient(config)
+
+	req := &responses.Request{
+		Model:       models.GPTO1Mini,
+		Input:       "hi",
+		Temperature: 0.5,
+		Tools:       []string{"get_time"},
+		AutoStrip:   true,
+	}
+	resp, err := client.Send(req)
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp.FirstText())
+
+	// parameters stripped for the primary model are sent to the fallback model that supports them
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, requests, 2)
+	require.NotContains(t, requests[0], "temperature")
+	require.NotContains(t, requests[0], "tools")
+	require.Equal(t, 0.5, requests[1]["temperature"])
+	require.Len(t, requests[1]["tools"], 1)
+
+	// the request of the caller is not changed
+	require.Equal(t, 0.5, req.Temperature)
+	require.Equal(t, []string{"get_time"}, req.Tools)
+}
+
+func TestSendFallback(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu        sync.Mutex
+		requested []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var req struct {
+			Model string `json:"model"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requested = append(requested, req.Model)
+		if req.Model == "gpt-missing" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"message":"The model does not exist","type":"invalid_request_error","code":"model_not_found"}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"id":"resp_1","object":"response","status":"completed","model":"%s","output":[`+
+			`{"type":"message","id":"msg_1","role":"assistant","status":"completed","content":[{"type":"output_text","text":"ok","annotations":[]}]}]}`,
+			req.Model)
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	config.Models.SetAlias("smart", "gpt-missing")
+	config.Models.SetAlias("fast", "gpt-present")
+	config.Models.SetFallbacks("gpt-missing", "fast")
+	client := NewClient(config)
+
+	resp, err := client.Send(&responses.Request{Model: "smart", Input: "hi"})
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp.FirstText())
+
+	// without fallbacks the API error is returned
+	config.Models.SetFallbacks("gpt-missing")
+	_, err = client.Send(&responses.Request{Model: "smart", Input: "hi"})
+	var apiErr *openai.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, "model_not_found", apiErr.Code)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"gpt-missing", "gpt-present", "gpt-missing"}, requested)
+}
+
+func TestSendFallbackReusedRequest(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu        sync.Mutex
+		requested []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var req struct {
+			Model string `json:"model"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requested = append(requested, req.Model)
+		if req.Model == "gpt-primary" {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":{"message":"The server had an error","type":"server_error"}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"id":"resp_1","object":"response","status":"completed","model":"%s","output":[`+
+			`{"type":"message","id":"msg_1","role":"assistant","status":"completed","content":[{"type":"output_text","text":"ok","annotations":[]}]}]}`,
+			req.Model)
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	config.Models.SetFallbacks("gpt-primary", "gpt-backup")
+	client := NewClient(config)
+
+	// the fallback model is not kept in the request, so each Send tries the primary model first
+	req := &responses.Request{Model: "gpt-primary", Input: "hi"}
+	for range 2 {
+		resp, err := client.Send(req)
+		require.NoError(t, err)
+		require.Equal(t, "ok", resp.FirstText())
+		require.Equal(t, "gpt-primary", req.Model)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"gpt-primary", "gpt-backup", "gpt-primary", "gpt-backup"}, requested)
+}
+
+func TestStreamWithoutTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"type\":\"response.output_text.delta\",\"sequence_number\":1,\"delta\":\"Hel\"}\n\n")
+		w.(http.Flusher).Flush()
+		// the stream outlasts the overall timeout of the client
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprint(w, "data: {\"type\":\"response.output_text.delta\",\"sequence_number\":2,\"delta\":\"lo\"}\n\n")
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	config.HTTPClient.Timeout = 20 * time.Millisecond
+	client := NewClient(config)
+
+	stream, err := client.Stream(context.Background(), &responses.Request{Input: "hi", Stream: true})
+	require.NoError(t, err)
+
+	var text string
+	for stream.Next() {
+		if delta, ok := stream.Event().(streaming.ResponseOutputTextDelta); ok {
+			text += delta.Delta
+		}
+	}
+	require.NoError(t, stream.Err())
+	require.Equal(t, "Hello", text)
+}
@@ -0,0 +1,290 @@
+package inresponses
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/unkn0wncode/openai/content/input"
+	"github.com/unkn0wncode/openai/content/output"
+	"github.com/unkn0wncode/openai/models"
+	"github.com/unkn0wncode/openai/responses"
+	"github.com/unkn0wncode/openai/roles"
+	"github.com/unkn0wncode/openai/tokens"
+)
+
+const (
+	// defaultSummaryTokens is the default budget reserved for a summary of dropped turns.
+	defaultSummaryTokens = 1000
+	// summaryReasoningTokens is the headroom for reasoning of summary models that can't turn it off.
+	summaryReasoningTokens = 2000
+	// minCompactThreshold is the lowest compaction threshold accepted by the API.
+	minCompactThreshold = 1000
+)
+
+// contextTokenLimit returns the context window of the model, or of the default model if unknown.
+func contextTokenLimit(model string) int {
+	modelData, ok := models.Data[model]
+	if !ok {
+		return models.Data[""].LimitContext
+	}
+	return modelData.LimitContext
+}
+
+// countTokens estimates the number of tokens in the request input and its tools.
+func (c *Client) countTokens(req *responses.Request) (int, error) {
+	toolList, err := c.resolveTools(req.Tools)
+	if err != nil {
+		return 0, err
+	}
+
+	toolDefs := make([]any, len(toolList))
+	for i, t := range toolList {
+		toolDefs[i] = t
+	}
+	return tokens.ResponsesRequest(req, toolDefs...)
+}
+
+// fitContext returns a copy of the request with input trimmed according to its ContextWindow,
+// and a report if anything was trimmed. Requests that fit are returned as is.
+func (c *Client) fitContext(req *responses.Request) (*responses.Request, *responses.ContextReport, error) {
+	cw := req.ContextWindow
+	if cw == nil {
+		return req, nil, nil
+	}
+
+	limit := cw.MaxInputTokens
+	if limit == 0 {
+		limit = contextTokenLimit(c.Models.Resolve(req.Model)) - req.MaxOutputTokens
+	}
+
+	switch cw.Strategy {
+	case responses.ContextServerCompaction:
+		if len(req.ContextManagement) > 0 {
+			return req, nil, nil
+		}
+		apiReq := req.Clone()
+		apiReq.ContextManagement = []responses.ContextConfig{{CompactThreshold: max(limit, minCompactThreshold)}}
+		return apiReq, nil, nil
+	case responses.ContextDropOldest, responses.ContextSummarize:
+	default:
+		return nil, nil, fmt.Errorf("unknown context strategy '%s'", cw.Strategy)
+	}
+
+	count := cw.CountTokens
+	if count == nil {
+		count = c.countTokens
+	}
+
+	before, err := count(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count tokens: %w", err)
+	}
+	if before <= limit {
+		return req, nil, nil
+	}
+
+	items, err := responses.InputItems(req.Input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// leading system and developer messages are kept
+	pinned := 0
+	for pinned < len(items) && slices.Contains([]string{roles.System, roles.Developer}, itemRole(items[pinned])) {
+		pinned++
+	}
+
+	reserve := 0
+	if cw.Strategy == responses.ContextSummarize {
+		reserve = cmp.Or(cw.SummaryMaxTokens, defaultSummaryTokens)
+	}
+
+	// find the earliest user message after which the rest of the input fits
+	apiReq := req.Clone()
+	cut, after := -1, before
+	for i := pinned + 1; i < len(items); i++ {
+		if itemRole(items[i]) != roles.User {
+			continue
+		}
+
+		apiReq.Input = slices.Concat(items[:pinned], items[i:])
+		if after, err = count(apiReq); err != nil {
+			return nil, nil, fmt.Errorf("failed to count tokens: %w", err)
+		}
+		cut = i
+		if after+reserve <= limit {
+			break
+		}
+	}
+	if cut < 0 || after+reserve > limit {
+		return nil, nil, fmt.Errorf("input is likely too long: ~%d tokens after trimming, max %d tokens", after+reserve, limit)
+	}
+
+	report := &responses.ContextReport{
+		Strategy:     cw.Strategy,
+		Limit:        limit,
+		TokensBefore: before,
+		TokensAfter:  after,
+		Dropped:      slices.Clone(items[pinned:cut]),
+	}
+
+	if cw.Strategy == responses.ContextSummarize {
+		if report.Summary, err = c.summarize(cw, report.Dropped); err != nil {
+			return nil, nil, fmt.Errorf("failed to summarize dropped input: %w", err)
+		}
+
+		summary, err := toAny(output.Message{
+			Role:    roles.Developer,
+			Content: []any{input.InputText{Text: "Summary of the earlier conversation:\n" + report.Summary}},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		apiReq.Input = slices.Concat(items[:pinned], []output.Any{summary}, items[cut:])
+		if report.TokensAfter, err = count(apiReq); err != nil {
+			return nil, nil, fmt.Errorf("failed to count tokens: %w", err)
+		}
+		if report.TokensAfter > limit {
+			return nil, nil, fmt.Errorf("input with summary is likely too long: ~%d tokens, max %d tokens", report.TokensAfter, limit)
+		}
+	}
+
+	c.Log.Debug(fmt.Sprintf(
+		"Trimmed %d input items with strategy '%s': ~%d -> ~%d tokens, max %d",
+		len(report.Dropped), report.Strategy, report.TokensBefore, report.TokensAfter, limit,
+	))
+	if cw.OnTrim != nil {
+		cw.OnTrim(*report)
+	}
+
+	return apiReq, report, nil
+}
+
+// summarize asks the model to summarize given items.
+func (c *Client) summarize(cw *responses.ContextWindow, items []output.Any) (string, error) {
+	store := false
+	model := cmp.Or(cw.SummaryModel, models.DefaultNano)
+	maxTokens := cmp.Or(cw.SummaryMaxTokens, defaultSummaryTokens)
+	reasoning, headroom := summaryReasoning(model)
+	resp, err := c.Send(&responses.Request{
+		Model: model,
+		Instructions: fmt.Sprintf(
+			"%s Keep the summary under %d words.",
+			cmp.Or(cw.SummaryInstructions, responses.DefaultSummaryInstructions),
+			// a token is about 3/4 of a word
+			maxTokens*3/4,
+		),
+		Input: transcript(items),
+		// the instruction is a soft limit, the summary must fit into the reserved budget
+		MaxOutputTokens: maxTokens + headroom,
+		Reasoning:       reasoning,
+		Store:           &store,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	summary := resp.JoinedTexts()
+	if summary == "" {
+		return "", fmt.Errorf("got empty summary")
+	}
+	return summary, nil
+}
+
+// summaryReasoning returns the lowest reasoning effort of the summary model, so that reasoning
+// doesn't use up the output tokens of the summary, and the tokens to add for the rest of it.
+func summaryReasoning(model string) (*responses.ReasoningConfig, int) {
+	caps, _ := models.GetCapabilities(model)
+	switch {
+	case !caps.Reasoning:
+		return nil, 0
+	case caps.Sampling:
+		// GPT-5.1 and newer can turn reasoning off
+		return &responses.ReasoningConfig{Effort: "none"}, 0
+	case strings.HasPrefix(model, "gpt-5") && !strings.Contains(model, "codex") && !strings.Contains(model, "-pro"):
+		return &responses.ReasoningConfig{Effort: "minimal"}, 0
+	default:
+		return &responses.ReasoningConfig{Effort: "low"}, summaryReasoningTokens
+	}
+}
+
+// transcript renders items as plain text for summarization.
+func transcript(items []output.Any) string {
+	var sb strings.Builder
+	for _, item := range items {
+		parsed, err := item.Unmarshal()
+		if err != nil {
+			// unknown items are given as is
+			fmt.Fprintf(&sb, "%s\n\n", item)
+			continue
+		}
+
+		switch it := parsed.(type) {
+		case output.Message:
+			fmt.Fprintf(&sb, "%s: %s\n\n", cmp.Or(it.Role, roles.User), messageText(it.Content))
+		case output.FunctionCall:
+			fmt.Fprintf(&sb, "assistant called function %s(%s) [%s]\n\n", it.Name, it.Arguments, it.CallID)
+		case output.FunctionCallOutput:
+			fmt.Fprintf(&sb, "function result [%s]: %s\n\n", it.CallID, it.Output)
+		case output.Reasoning, output.Compaction:
+			// opaque to the summarizer
+		default:
+			fmt.Fprintf(&sb, "%s\n\n", item)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// messageText returns texts and refusals from message content joined with newlines.
+func messageText(content any) string {
+	if text, ok := content.(string); ok {
+		return text
+	}
+
+	parts, _ := content.([]any)
+	var texts []string
+	for _, part := range parts {
+		switch p := part.(type) {
+		case input.InputText:
+			texts = append(texts, p.Text)
+		case output.OutputText:
+			texts = append(texts, p.Text)
+		case output.Refusal:
+			texts = append(texts, p.Refusal)
+		case input.InputImage, input.InputFile:
+			texts = append(texts, "[attachment]")
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// itemRole returns the role of a message item, or an empty string for other items.
+func itemRole(item output.Any) string {
+	if item.Type != "" && item.Type != "message" {
+		return ""
+	}
+
+	var msg struct {
+		Role string `json:"role"`
+	}
+	if err := item.UnmarshalToTarget(&msg); err != nil {
+		return ""
+	}
+	return msg.Role
+}
+
+// toAny converts a value to output.Any through JSON.
+func toAny(v any) (output.Any, error) {
+	var a output.Any
+	b, err := json.Marshal(v)
+	if err != nil {
+		return a, fmt.Errorf("failed to marshal item: %w", err)
+	}
+	if err := json.Unmarshal(b, &a); err != nil {
+		return a, fmt.Errorf("failed to unmarshal item: %w", err)
+	}
+	return a, nil
+}
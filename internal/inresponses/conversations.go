@@ -121,6 +121,54 @@ func (c *Client) Conversation(conversationID string) (*responses.Conversation, e
 	return &conv, nil
 }
 
+const (
+	// conversationItemsPerCall is the maximum number of items in one create or append call.
+	conversationItemsPerCall = 20
+	// conversationItemsPerPage is the maximum number of items in one page of the list.
+	conversationItemsPerPage = 100
+)
+
+// ExportConversation retrieves all items of a conversation in chronological order.
+func (c *Client) ExportConversation(conversationID string) ([]output.Any, error) {
+	conv, err := c.Conversation(conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []output.Any
+	opts := &responses.ConversationListOptions{Limit: conversationItemsPerPage, Order: "asc"}
+	for {
+		list, err := conv.ListItems(opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list items after %d exported: %w", len(items), err)
+		}
+		items = append(items, list.Data...)
+
+		if !list.HasMore || list.LastID == "" {
+			return items, nil
+		}
+		opts.After = list.LastID
+	}
+}
+
+// ImportConversation creates a new conversation with given items, sending them in batches.
+func (c *Client) ImportConversation(metadata map[string]string, items ...any) (*responses.Conversation, error) {
+	first := items[:min(len(items), conversationItemsPerCall)]
+	conv, err := c.CreateConversation(metadata, first...)
+	if err != nil {
+		return nil, err
+	}
+
+	for start := len(first); start < len(items); start += conversationItemsPerCall {
+		batch := items[start:min(start+conversationItemsPerCall, len(items))]
+		if _, err := conv.AppendItems(nil, batch...); err != nil {
+			return conv, fmt.Errorf("failed to append items %d-%d: %w", start, start+len(batch)-1, err)
+		}
+	}
+
+	return conv, nil
+}
+
 // Update sends the current metadata of the conversation to the API.
 func (c conversationCli) Update() error {
 	payload := struct {
@@ -178,6 +226,12 @@ func (c conversationCli) ListItems(opts *responses.ConversationListOptions) (*re
 		if opts.LastID != "" {
 			values.Set("last_id", opts.LastID)
 		}
+		if opts.After != "" {
+			values.Set("after", opts.After)
+		}
+		if opts.Order != "" {
+			values.Set("order", opts.Order)
+		}
 		addInclude(values, opts.Include)
 		endpoint.RawQuery = values.Encode()
 	}
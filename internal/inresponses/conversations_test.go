@@ -0,0 +1,116 @@
+package inresponses
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/content/output"
+	openai "github.com/unkn0wncode/openai/internal"
+)
+
+func TestConversationExportImport(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		stored  []json.RawMessage
+		ids     []string
+		batches []int
+		pages   []string
+	)
+	writeList := func(w http.ResponseWriter, items []json.RawMessage, hasMore bool) {
+		lastID := ""
+		if len(items) > 0 {
+			var item struct {
+				ID string `json:"id"`
+			}
+			require.NoError(t, json.Unmarshal(items[len(items)-1], &item))
+			lastID = item.ID
+		}
+		data, err := json.Marshal(items)
+		require.NoError(t, err)
+		fmt.Fprintf(w, `{"object":"list","data":%s,"last_id":%q,"has_more":%t}`, data, lastID, hasMore)
+	}
+	// addItems stores items with IDs assigned by their position
+	addItems := func(items []map[string]any) []json.RawMessage {
+		var added []json.RawMessage
+		for _, item := range items {
+			item["id"] = fmt.Sprintf("msg_%d", len(stored))
+			ids = append(ids, item["id"].(string))
+			data, err := json.Marshal(item)
+			require.NoError(t, err)
+			stored = append(stored, data)
+			added = append(added, data)
+		}
+		batches = append(batches, len(items))
+		return added
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var req struct {
+			Items []map[string]any `json:"items"`
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/conversations":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			addItems(req.Items)
+			fmt.Fprint(w, `{"id":"conv_1","object":"conversation","created_at":1}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/conversations/conv_1":
+			fmt.Fprint(w, `{"id":"conv_1","object":"conversation","created_at":1}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/conversations/conv_1/items":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			writeList(w, addItems(req.Items), false)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/conversations/conv_1/items":
+			q := r.URL.Query()
+			assert.Equal(t, "asc", q.Get("order"))
+			pages = append(pages, q.Get("after"))
+
+			start := 0
+			if after := q.Get("after"); after != "" {
+				start = slices.Index(ids, after) + 1
+			}
+			// a small page to exercise pagination
+			limit, err := strconv.Atoi(q.Get("limit"))
+			assert.NoError(t, err)
+			end := min(start+min(limit, 30), len(stored))
+			writeList(w, stored[start:end], end < len(stored))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	items := make([]any, 45)
+	for i := range items {
+		items[i] = output.Message{Role: "user", Content: strconv.Itoa(i)}
+	}
+	conv, err := client.ImportConversation(map[string]string{"source": "test"}, items...)
+	require.NoError(t, err)
+	require.Equal(t, "conv_1", conv.ID)
+	require.Equal(t, []int{20, 20, 5}, batches)
+
+	exported, err := client.ExportConversation(conv.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "msg_29"}, pages)
+	require.Len(t, exported, len(items))
+	for i, item := range exported {
+		parsed, err := item.Unmarshal()
+		require.NoError(t, err)
+		require.Equal(t, strconv.Itoa(i), parsed.(output.Message).Content)
+	}
+}
@@ -5,190 +5,262 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
-	"net/url"
+	"slices"
 	"sync"
+	"time"
 
 	openai "github.com/unkn0wncode/openai/internal"
-	"github.com/unkn0wncode/openai/models"
 	"github.com/unkn0wncode/openai/responses"
 	"github.com/unkn0wncode/openai/responses/streaming"
+	"github.com/unkn0wncode/openai/tools"
 
 	"github.com/gorilla/websocket"
 )
 
 type wsClient struct {
 	client *Client
-	conn   *websocket.Conn
+	opts   openai.WebSocketOptions
+
+	// ctx is canceled when the client is closed for good, aborting redials
+	ctx    context.Context
+	cancel context.CancelFunc
+	state  *openai.StateNotifier[responses.WSState]
 
 	mu      sync.Mutex
 	writeMu sync.Mutex
+	conn    *websocket.Conn // nil while redialing
 	closed  bool
 	turns   []*wsTurn
 }
 
+// wsTurn is a request sent over the connection and the stream of its events.
+// Events are queued by the read loop and delivered by a pump goroutine of the turn,
+// so that a slow consumer doesn't hold up turns after it. A consumer that stops reading
+// without canceling its context makes the turn buffer its whole response in memory.
 type wsTurn struct {
-	events       chan any
+	payload []byte
+	started bool // got the first event, so it can't be sent again; guarded by wsClient.mu
+
+	events       chan any // fed by pump
 	consumerDone chan struct{}
 	finished     chan struct{}
+	wake         chan struct{} // signals pump about queued events
 	consumerOnce sync.Once
 	finishOnce   sync.Once
+
+	mu    sync.Mutex
+	queue []any // events not delivered yet, unbounded, so that a slow consumer never blocks the read loop
+	done  bool  // no more events are queued
 }
 
-func newWSTurn() *wsTurn {
-	return &wsTurn{
-		events:       make(chan any, 64),
+func newWSTurn(payload []byte) *wsTurn {
+	t := &wsTurn{
+		payload:      payload,
+		events:       make(chan any),
 		consumerDone: make(chan struct{}),
 		finished:     make(chan struct{}),
+		wake:         make(chan struct{}, 1),
 	}
+	go t.pump()
+	return t
 }
 
+// send queues an event for the consumer without waiting for it.
+// Returns false if the turn is finished or the consumer is gone.
 func (t *wsTurn) send(event any) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.done {
+		return false
+	}
 	select {
 	case <-t.consumerDone:
 		return false
-	case t.events <- event:
-		return true
+	default:
 	}
+
+	t.queue = append(t.queue, event)
+	t.signal()
+	return true
 }
 
-func (t *wsTurn) stopConsumer(err error) {
-	t.consumerOnce.Do(func() {
-		if err != nil {
+// signal wakes up pump if it's waiting.
+func (t *wsTurn) signal() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// pump delivers queued events to the consumer in order and closes the channel
+// after the turn is finished and all its events are delivered, or when the consumer is gone.
+func (t *wsTurn) pump() {
+	defer close(t.events)
+
+	for {
+		t.mu.Lock()
+		if len(t.queue) == 0 {
+			done := t.done
+			t.mu.Unlock()
+			if done {
+				return
+			}
 			select {
-			case t.events <- err:
-			default:
+			case <-t.wake:
+				continue
+			case <-t.consumerDone:
+				return
 			}
 		}
+		event := t.queue[0]
+		t.queue[0] = nil
+		t.queue = t.queue[1:]
+		t.mu.Unlock()
+
+		select {
+		case t.events <- event:
+		case <-t.consumerDone:
+			return
+		}
+	}
+}
+
+// stopConsumer drops events that are not delivered yet, as nobody reads them anymore.
+func (t *wsTurn) stopConsumer() {
+	t.consumerOnce.Do(func() {
 		close(t.consumerDone)
 	})
 }
 
+// complete finishes the turn. A non-nil error is delivered to the consumer after the queued events.
 func (t *wsTurn) complete(err error) {
 	t.finishOnce.Do(func() {
-		t.stopConsumer(err)
+		if err != nil {
+			t.send(err)
+		}
+
+		t.mu.Lock()
+		t.done = true
+		t.signal()
+		t.mu.Unlock()
 		close(t.finished)
-		close(t.events)
 	})
 }
 
 // WebSocket opens a persistent WebSocket connection for response.create events.
 // Context is only used for the dialer and doesn't limit connection lifetime.
 func (c *Client) WebSocket(ctx context.Context) (responses.WSConn, error) {
-	targetURL, err := wsURLFromBase(c.BaseAPI)
+	conn, err := c.dialWebSocket(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	headers := http.Header{}
-	headers.Set("Authorization", "Bearer "+c.Token)
-
-	dialer := newWebSocketDialer(c)
-	conn, _, err := dialer.DialContext(ctx, targetURL, headers)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect websocket: %w", err)
+	opts := c.Config.WebSocket
+	if opts.PingInterval > 0 && opts.PongTimeout <= 0 {
+		opts.PongTimeout = opts.PingInterval
+	}
+	if opts.MaxReconnects > 0 && opts.ReconnectBackoff <= 0 {
+		opts.ReconnectBackoff = openai.DefaultWebSocketOptions.ReconnectBackoff
 	}
 
+	wsCtx, cancel := context.WithCancel(context.Background())
 	ws := &wsClient{
 		client: c,
+		opts:   opts,
+		ctx:    wsCtx,
+		cancel: cancel,
+		state:  openai.NewStateNotifier(responses.WSStateOpen),
 		conn:   conn,
 	}
-	go ws.readLoop()
+	ws.start(conn)
 
 	return ws, nil
 }
 
-func newWebSocketDialer(c *Client) *websocket.Dialer {
-	if c.WebSocketDialer != nil {
-		dialerCopy := *c.WebSocketDialer
-		return &dialerCopy
-	}
-
-	dialer := websocket.Dialer{
-		Proxy: http.ProxyFromEnvironment,
-	}
-
-	if c.HTTPClient == nil || c.HTTPClient.Client == nil {
-		return &dialer
-	}
-
-	httpClient := c.HTTPClient.Client
-	if httpClient.Timeout > 0 {
-		dialer.HandshakeTimeout = httpClient.Timeout
-	}
-
-	if transport, ok := httpClient.Transport.(*http.Transport); ok && transport != nil {
-		if transport.Proxy != nil {
-			dialer.Proxy = transport.Proxy
-		}
-		dialer.NetDialContext = transport.DialContext
-		dialer.TLSClientConfig = transport.TLSClientConfig
-	}
+// dialWebSocket opens a new connection to the Responses API.
+func (c *Client) dialWebSocket(ctx context.Context) (*websocket.Conn, error) {
+	return c.DialWebSocket(ctx, "v1/responses", nil, nil)
+}
 
-	return &dialer
+// start runs the read loop and keepalive for a newly established connection.
+func (w *wsClient) start(conn *websocket.Conn) {
+	stop := make(chan struct{})
+	if w.opts.PingInterval > 0 {
+		w.extendReadDeadline(conn)
+		conn.SetPongHandler(func(string) error {
+			w.extendReadDeadline(conn)
+			return nil
+		})
+		go w.keepalive(conn, stop)
+	}
+	go w.readLoop(conn, stop)
 }
 
-func wsURLFromBase(base string) (string, error) {
-	rawURL, err := url.JoinPath(base, "v1/responses")
-	if err != nil {
-		return "", fmt.Errorf("failed to build websocket URL: %w", err)
+// extendReadDeadline gives the server one ping interval plus pong timeout to send anything.
+func (w *wsClient) extendReadDeadline(conn *websocket.Conn) {
+	if w.opts.PingInterval <= 0 {
+		return
 	}
+	//nolint:errcheck // fails only on a broken connection, which the next read reports
+	conn.SetReadDeadline(time.Now().Add(w.opts.PingInterval + w.opts.PongTimeout))
+}
 
-	u, err := url.Parse(rawURL)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse websocket URL: %w", err)
-	}
+// keepalive pings the server until the connection's read loop stops.
+func (w *wsClient) keepalive(conn *websocket.Conn, stop <-chan struct{}) {
+	ticker := time.NewTicker(w.opts.PingInterval)
+	defer ticker.Stop()
 
-	switch u.Scheme {
-	case "https":
-		u.Scheme = "wss"
-	case "http":
-		u.Scheme = "ws"
-	case "wss", "ws":
-	default:
-		return "", fmt.Errorf("unsupported base API scheme: %s", u.Scheme)
+	for {
+		select {
+		case <-ticker.C:
+			deadline := time.Now().Add(w.opts.PongTimeout)
+			if err := conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				w.connLost(conn, fmt.Errorf("websocket ping failed: %w", err))
+				return
+			}
+		case <-stop:
+			return
+		}
 	}
-
-	return u.String(), nil
 }
 
-func (w *wsClient) logPayload(direction string, data []byte) {
-	lt, ok := w.client.HTTPClient.Transport.(*openai.LoggingTransport)
-	if !ok || !lt.EnableLog {
-		return
-	}
-	w.client.Log.Debug(fmt.Sprintf("websocket %s:\n%s", direction, string(data)))
-}
+func (w *wsClient) readLoop(conn *websocket.Conn, stop chan<- struct{}) {
+	defer close(stop)
 
-func (w *wsClient) readLoop() {
 	for {
-		_, message, err := w.conn.ReadMessage()
+		_, message, err := conn.ReadMessage()
 		if err != nil {
-			w.failAllTurns(fmt.Errorf("websocket read failed: %w", err))
+			w.connLost(conn, fmt.Errorf("websocket read failed: %w", err))
 			return
 		}
+		w.extendReadDeadline(conn)
 
-		w.logPayload("recv", message)
+		w.client.LogWebSocketPayload("recv", message)
 
 		event, err := streaming.Unmarshal(message)
 		if err != nil {
-			w.failAllTurns(fmt.Errorf("failed to unmarshal websocket event: %w", err))
+			w.terminate(fmt.Errorf("failed to unmarshal websocket event: %w", err))
 			return
 		}
 
-		w.pushEvent(event)
+		w.pushEvent(conn, event)
 	}
 }
 
-func (w *wsClient) pushEvent(event any) {
-	turn := w.getHeadTurn()
-	if turn == nil {
+func (w *wsClient) pushEvent(conn *websocket.Conn, event any) {
+	w.mu.Lock()
+	if w.conn != conn || len(w.turns) == 0 {
+		w.mu.Unlock()
 		return
 	}
+	turn := w.turns[0]
+	turn.started = true
+	w.mu.Unlock()
 
 	turn.send(event)
 	if isTerminalEvent(event) {
-		w.finishTurn(nil)
+		w.finishTurn(turn)
 	}
 }
 
@@ -205,64 +277,176 @@ func isTerminalEvent(event any) bool {
 	}
 }
 
-func (w *wsClient) getHeadTurn() *wsTurn {
+func (w *wsClient) finishTurn(turn *wsTurn) {
 	w.mu.Lock()
-	defer w.mu.Unlock()
-	if len(w.turns) == 0 {
-		return nil
+	if len(w.turns) > 0 && w.turns[0] == turn {
+		w.turns = w.turns[1:]
 	}
-	return w.turns[0]
+	w.mu.Unlock()
+
+	turn.complete(nil)
 }
 
-func (w *wsClient) finishTurn(err error) {
+// connLost handles a failure of given connection. Turns that already started are failed,
+// the rest are kept and sent again after a redial. If reconnection is disabled,
+// the client is closed. Failures of previous connections are ignored.
+func (w *wsClient) connLost(conn *websocket.Conn, cause error) {
+	if w.opts.MaxReconnects <= 0 {
+		w.mu.Lock()
+		current := w.conn == conn
+		w.mu.Unlock()
+		if current {
+			w.terminate(cause)
+		}
+		return
+	}
+
 	w.mu.Lock()
-	turn := w.turns[0]
-	w.turns = w.turns[1:]
+	if w.closed || w.conn != conn {
+		w.mu.Unlock()
+		return
+	}
+	w.conn = nil
+	var failed, kept []*wsTurn
+	for _, turn := range w.turns {
+		if turn.started {
+			failed = append(failed, turn)
+		} else {
+			kept = append(kept, turn)
+		}
+	}
+	w.turns = kept
+	w.state.Set(responses.WSStateDegraded, false)
 	w.mu.Unlock()
 
-	turn.complete(err)
+	conn.Close()
+	for _, turn := range failed {
+		go turn.complete(fmt.Errorf("websocket connection lost: %w", cause))
+	}
+
+	w.client.Log.Warn(fmt.Sprintf("websocket connection lost, reconnecting: %v", cause))
+	go w.redial(cause)
 }
 
-func (w *wsClient) finishAllTurns(err error) {
+// redial tries to establish a new connection with exponential backoff.
+// The client is closed if all attempts fail.
+func (w *wsClient) redial(cause error) {
+	backoff := w.opts.ReconnectBackoff
+	var err error
+	for attempt := 1; attempt <= w.opts.MaxReconnects; attempt++ {
+		select {
+		case <-time.After(backoff):
+		case <-w.ctx.Done():
+			return
+		}
+		backoff *= 2
+
+		w.mu.Lock()
+		if w.closed {
+			w.mu.Unlock()
+			return
+		}
+		w.state.Set(responses.WSStateConnecting, false)
+		w.mu.Unlock()
+
+		var conn *websocket.Conn
+		conn, err = w.client.dialWebSocket(w.ctx)
+		if err != nil {
+			w.client.Log.Warn(fmt.Sprintf("websocket reconnect attempt %d failed: %v", attempt, err))
+			continue
+		}
+
+		w.resume(conn)
+		return
+	}
+
+	w.terminate(fmt.Errorf(
+		"failed to reconnect websocket after %d attempts: %w",
+		w.opts.MaxReconnects, errors.Join(cause, err),
+	))
+}
+
+// resume switches the client to a new connection and sends pending turns again.
+func (w *wsClient) resume(conn *websocket.Conn) {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
 	w.mu.Lock()
-	turns := w.turns
-	w.turns = nil
+	if w.closed {
+		w.mu.Unlock()
+		conn.Close()
+		return
+	}
+	w.conn = conn
+	pending := slices.Clone(w.turns)
+	w.state.Set(responses.WSStateOpen, false)
 	w.mu.Unlock()
 
-	for _, turn := range turns {
-		turn.complete(err)
+	w.client.Log.Info(fmt.Sprintf("websocket reconnected, resending %d pending turns", len(pending)))
+	w.start(conn)
+
+	for _, turn := range pending {
+		w.client.LogWebSocketPayload("send", turn.payload)
+		if err := conn.WriteMessage(websocket.TextMessage, turn.payload); err != nil {
+			w.connLost(conn, fmt.Errorf("websocket write failed: %w", err))
+			return
+		}
 	}
 }
 
-// failAllTurns atomically marks the websocket closed before draining all pending turns,
-// preventing new turns from being queued in between.
-func (w *wsClient) failAllTurns(err error) {
+// terminate closes the client for good, failing all pending turns with given error.
+func (w *wsClient) terminate(err error) error {
 	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
 	w.closed = true
 	conn := w.conn
+	w.conn = nil
+	turns := w.turns
+	w.turns = nil
+	w.state.Set(responses.WSStateClosed, true)
 	w.mu.Unlock()
 
-	w.finishAllTurns(errors.Join(err, conn.Close()))
+	w.cancel()
+	for _, turn := range turns {
+		go turn.complete(err)
+	}
+	if conn == nil {
+		return nil
+	}
+	return conn.Close()
 }
 
 // Send wraps req as a response.create event, writes it to the WebSocket, and
 // returns a streaming iterator for the server's reply. Requests on the same
 // connection are queued and processed sequentially by the server.
+// While the connection is being reestablished, requests are queued and sent after the redial.
 func (w *wsClient) Send(ctx context.Context, req *responses.Request) (*streaming.StreamIterator, error) {
 	if req == nil {
 		return nil, fmt.Errorf("request is nil")
 	}
+	if req.LocalConversation != nil {
+		return nil, fmt.Errorf("LocalConversation is only supported by Send")
+	}
 
-	data := req.Clone()
-	if data.Model == "" {
-		data.Model = models.Default
+	if req.ContextWindow != nil {
+		return nil, fmt.Errorf("ContextWindow is only supported by Send")
 	}
+
+	data := req.Clone()
+	data.Model = w.client.ResolveModel(data.Model)
 	if data.Input == nil {
 		return nil, fmt.Errorf("input is required")
 	}
 	data.Stream = false
 	data.Background = false
 
+	if err := w.client.validateRequest(data); err != nil {
+		return nil, err
+	}
+
 	reqBytes, err := w.client.marshalRequest(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -279,31 +463,40 @@ func (w *wsClient) Send(ctx context.Context, req *responses.Request) (*streaming
 		return nil, fmt.Errorf("failed to marshal websocket payload: %w", err)
 	}
 
-	turn := newWSTurn()
-
-	w.logPayload("send", eventBytes)
+	turn := newWSTurn(eventBytes)
 
+	// write lock is held from queueing to writing, so that a redial can't send the turn twice
+	w.writeMu.Lock()
 	w.mu.Lock()
 	if w.closed {
 		w.mu.Unlock()
-		return nil, fmt.Errorf("websocket connection is closed")
+		w.writeMu.Unlock()
+		return nil, responses.ErrWSConnClosed
 	}
 	w.turns = append(w.turns, turn)
+	conn := w.conn
 	w.mu.Unlock()
 
-	w.writeMu.Lock()
-	writeErr := w.conn.WriteMessage(websocket.TextMessage, eventBytes)
+	var writeErr error
+	if conn != nil {
+		w.client.LogWebSocketPayload("send", eventBytes)
+		writeErr = conn.WriteMessage(websocket.TextMessage, eventBytes)
+	}
 	w.writeMu.Unlock()
+
 	if writeErr != nil {
-		w.failAllTurns(fmt.Errorf("websocket write failed: %w", writeErr))
-		return nil, fmt.Errorf("failed to send websocket payload: %w", writeErr)
+		// the turn is kept and sent again after a redial, unless reconnection is disabled
+		w.connLost(conn, fmt.Errorf("websocket write failed: %w", writeErr))
+		if w.State() == responses.WSStateClosed {
+			return nil, fmt.Errorf("failed to send websocket payload, %w: %w", responses.ErrWSConnClosed, writeErr)
+		}
 	}
 
 	if done := ctx.Done(); done != nil {
 		go func() {
 			select {
 			case <-done:
-				turn.stopConsumer(ctx.Err())
+				turn.stopConsumer()
 			case <-turn.finished:
 			}
 		}()
@@ -341,16 +534,103 @@ func (w *wsClient) Warmup(ctx context.Context, req *responses.Request) (string,
 	return responseID, nil
 }
 
-// Close closes the WebSocket connection and completes any pending turns.
-func (w *wsClient) Close() error {
-	w.mu.Lock()
-	if w.closed {
-		w.mu.Unlock()
-		return nil
+// SendAuto works like Send, but executes registered tools when a turn completes with
+// function or custom tool calls, and sends their outputs in a follow-up turn on the same
+// connection. Events of all turns are delivered through one iterator, each turn starting with
+// its own response.created event. The loop stops when a turn has no executable calls or has
+// any calls that must be returned (see Request.ReturnToolCalls), when a turn doesn't complete,
+// or when a tool returns tools.ErrDoNotRespond.
+func (w *wsClient) SendAuto(ctx context.Context, req *responses.Request) (*streaming.StreamIterator, error) {
+	stream, err := w.Send(ctx, req)
+	if err != nil {
+		return nil, err
 	}
-	w.closed = true
-	conn := w.conn
-	w.mu.Unlock()
 
-	return conn.Close()
+	events := make(chan any)
+	go w.runTools(ctx, req, stream, events)
+
+	return streaming.NewStreamIterator(ctx, events), nil
+}
+
+// runTools forwards events of turns to the channel, executing tool calls between turns.
+func (w *wsClient) runTools(
+	ctx context.Context, req *responses.Request, stream *streaming.StreamIterator, events chan<- any,
+) {
+	defer close(events)
+
+	send := func(item any) bool {
+		return openai.SendItem(ctx, events, item)
+	}
+
+	sc := newSendContext()
+	for {
+		var completed *streaming.ResponseCompleted
+		for stream.Next() {
+			event := stream.Event()
+			if e, ok := event.(streaming.ResponseCompleted); ok {
+				completed = &e
+			}
+			if !send(event) {
+				return
+			}
+		}
+		if err := stream.Err(); err != nil {
+			send(err)
+			return
+		}
+		if completed == nil {
+			// the turn failed or is incomplete, the consumer got the reason in events
+			return
+		}
+
+		resp := &responses.Response{ID: completed.Response.ID}
+		if err := json.Unmarshal(completed.Response.Output, &resp.Outputs); err != nil {
+			send(fmt.Errorf("failed to unmarshal outputs: %w", err))
+			return
+		}
+		if err := resp.Parse(); err != nil {
+			send(fmt.Errorf("failed to parse output: %w", err))
+			return
+		}
+
+		calls, err := w.client.collectToolCalls(req, resp.ParsedOutputs)
+		if err != nil {
+			send(err)
+			return
+		}
+		if calls.returnable > 0 || !calls.executable() {
+			return
+		}
+
+		toolOutputs, err := w.client.runToolCalls(calls, sc)
+		if errors.Is(err, tools.ErrDoNotRespond) {
+			return
+		}
+		if err != nil {
+			send(err)
+			return
+		}
+
+		req = followUpRequest(req, resp.ID, toolOutputs, sc)
+		stream, err = w.Send(ctx, req)
+		if err != nil {
+			send(fmt.Errorf("failed to send tool outputs: %w", err))
+			return
+		}
+	}
+}
+
+// State returns the current state of the connection.
+func (w *wsClient) State() responses.WSState {
+	return w.state.Get()
+}
+
+// States returns a channel receiving state changes of the connection.
+func (w *wsClient) States() <-chan responses.WSState {
+	return w.state.Chan()
+}
+
+// Close closes the WebSocket connection and completes any pending turns.
+func (w *wsClient) Close() error {
+	return w.terminate(responses.ErrWSConnClosed)
 }
@@ -0,0 +1,294 @@
+package inresponses
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/responses"
+	"github.com/unkn0wncode/openai/responses/streaming"
+	"github.com/unkn0wncode/openai/tools"
+)
+
+// readTurn reads a response.create event and returns its input, or "" on failure.
+// It's called from server handlers, so failures are reported without stopping the goroutine.
+func readTurn(t *testing.T, conn *websocket.Conn) string {
+	t.Helper()
+	var payload struct {
+		Type  string `json:"type"`
+		Input string `json:"input"`
+	}
+	_, data, err := conn.ReadMessage()
+	if !assert.NoError(t, err) ||
+		!assert.NoError(t, json.Unmarshal(data, &payload)) ||
+		!assert.Equal(t, "response.create", payload.Type) {
+		return ""
+	}
+	return payload.Input
+}
+
+// writeEvent writes a response event with given type and response ID, and reports whether it succeeded.
+func writeEvent(t *testing.T, conn *websocket.Conn, eventType, id string) bool {
+	t.Helper()
+	return assert.NoError(t, conn.WriteMessage(websocket.TextMessage, fmt.Appendf(nil,
+		`{"type":%q,"sequence_number":0,"response":{"id":%q}}`, eventType, id,
+	)))
+}
+
+func TestWebSocketReconnect(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		dials    int
+		release  = make(chan struct{})
+		upgrader websocket.Upgrader
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		mu.Lock()
+		dials++
+		n := dials
+		mu.Unlock()
+
+		switch n {
+		case 1:
+			// starts the first turn, receives the second one and stops responding, even to pings
+			assert.Equal(t, "first", readTurn(t, conn))
+			writeEvent(t, conn, "response.created", "resp_1")
+			assert.Equal(t, "second", readTurn(t, conn))
+			<-release
+		default:
+			// the second turn is sent again after the redial
+			assert.Equal(t, "second", readTurn(t, conn))
+			writeEvent(t, conn, "response.created", "resp_2")
+			writeEvent(t, conn, "response.completed", "resp_2")
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	config.WebSocket = openai.WebSocketOptions{
+		PingInterval:     20 * time.Millisecond,
+		PongTimeout:      20 * time.Millisecond,
+		MaxReconnects:    3,
+		ReconnectBackoff: 10 * time.Millisecond,
+	}
+	ws, err := NewClient(config).WebSocket(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, responses.WSStateOpen, ws.State())
+
+	var states []responses.WSState
+	statesDone := make(chan struct{})
+	go func() {
+		defer close(statesDone)
+		for state := range ws.States() {
+			states = append(states, state)
+		}
+	}()
+
+	first, err := ws.Send(t.Context(), &responses.Request{Input: "first"})
+	require.NoError(t, err)
+	second, err := ws.Send(t.Context(), &responses.Request{Input: "second"})
+	require.NoError(t, err)
+
+	// the first turn started before the connection was lost, so it can't be retried
+	require.True(t, first.Next())
+	require.IsType(t, streaming.ResponseCreated{}, first.Event())
+	require.False(t, first.Next())
+	require.ErrorContains(t, first.Err(), "websocket connection lost")
+
+	var events []any
+	for second.Next() {
+		events = append(events, second.Event())
+	}
+	require.NoError(t, second.Err())
+	require.Len(t, events, 2)
+	require.Equal(t, "resp_2", events[1].(streaming.ResponseCompleted).Response.ID)
+	require.Equal(t, responses.WSStateOpen, ws.State())
+
+	require.NoError(t, ws.Close())
+	<-statesDone
+	require.Contains(t, states, responses.WSStateConnecting)
+	require.Equal(t, responses.WSStateClosed, states[len(states)-1])
+
+	_, err = ws.Send(t.Context(), &responses.Request{Input: "third"})
+	require.ErrorIs(t, err, responses.ErrWSConnClosed)
+}
+
+func TestWebSocketSendAuto(t *testing.T) {
+	t.Parallel()
+
+	var upgrader websocket.Upgrader
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		assert.Equal(t, "add 1 and 2", readTurn(t, conn))
+		writeEvent(t, conn, "response.created", "resp_1")
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{
+			"type": "response.completed", "sequence_number": 1,
+			"response": {"id": "resp_1", "output": [{
+				"type": "function_call", "id": "fc_1", "call_id": "call_1",
+				"name": "add", "arguments": "{\"a\":1,\"b\":2}", "status": "completed"
+			}]}
+		}`)))
+
+		var followUp struct {
+			PreviousResponseID string `json:"previous_response_id"`
+			Input              []struct {
+				Type   string `json:"type"`
+				CallID string `json:"call_id"`
+				Output string `json:"output"`
+			} `json:"input"`
+		}
+		_, data, err := conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(data, &followUp))
+		assert.Equal(t, "resp_1", followUp.PreviousResponseID)
+		assert.Len(t, followUp.Input, 1)
+		assert.Equal(t, "function_call_output", followUp.Input[0].Type)
+		assert.Equal(t, "call_1", followUp.Input[0].CallID)
+		assert.Equal(t, "3", followUp.Input[0].Output)
+
+		writeEvent(t, conn, "response.created", "resp_2")
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{
+			"type": "response.completed", "sequence_number": 1,
+			"response": {"id": "resp_2", "output": [{
+				"type": "message", "id": "msg_1", "role": "assistant", "status": "completed",
+				"content": [{"type": "output_text", "text": "3", "annotations": []}]
+			}]}
+		}`)))
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	require.NoError(t, config.Tools.CreateFunction(tools.FunctionCall{
+		Name:         "add",
+		Description:  "Adds two numbers",
+		ParamsSchema: json.RawMessage(`{"type":"object","properties":{"a":{"type":"number"},"b":{"type":"number"}}}`),
+		F: func(params json.RawMessage) (string, error) {
+			var args struct{ A, B int }
+			if err := json.Unmarshal(params, &args); err != nil {
+				return "", err
+			}
+			return fmt.Sprint(args.A + args.B), nil
+		},
+	}))
+
+	ws, err := NewClient(config).WebSocket(t.Context())
+	require.NoError(t, err)
+	defer ws.Close()
+
+	stream, err := ws.SendAuto(t.Context(), &responses.Request{Input: "add 1 and 2", Tools: []string{"add"}})
+	require.NoError(t, err)
+
+	var ids []string
+	for stream.Next() {
+		if e, ok := stream.Event().(streaming.ResponseCompleted); ok {
+			ids = append(ids, e.Response.ID)
+		}
+	}
+	require.NoError(t, stream.Err())
+	require.Equal(t, []string{"resp_1", "resp_2"}, ids)
+}
+
+func TestWebSocketStalledTurn(t *testing.T) {
+	t.Parallel()
+
+	var upgrader websocket.Upgrader
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// the first turn gets more events than its consumer reads, then the second turn is answered
+		assert.Equal(t, "first", readTurn(t, conn))
+		assert.Equal(t, "second", readTurn(t, conn))
+		writeEvent(t, conn, "response.created", "resp_1")
+		for range 200 {
+			assert.NoError(t, conn.WriteMessage(websocket.TextMessage,
+				[]byte(`{"type":"response.output_text.delta","sequence_number":1,"delta":"a"}`)))
+		}
+		writeEvent(t, conn, "response.completed", "resp_1")
+		writeEvent(t, conn, "response.created", "resp_2")
+		writeEvent(t, conn, "response.completed", "resp_2")
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	ws, err := NewClient(config).WebSocket(t.Context())
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+
+	first, err := ws.Send(t.Context(), &responses.Request{Input: "first"})
+	require.NoError(t, err)
+	second, err := ws.Send(t.Context(), &responses.Request{Input: "second"})
+	require.NoError(t, err)
+
+	// the first stream is not read, which doesn't hold up the second one
+	done := make(chan error, 1)
+	go func() {
+		var last any
+		for second.Next() {
+			last = second.Event()
+		}
+		if _, ok := last.(streaming.ResponseCompleted); !ok {
+			done <- fmt.Errorf("unexpected last event %T", last)
+			return
+		}
+		done <- second.Err()
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the second turn is blocked by the stalled first one")
+	}
+
+	// the events of the first turn are buffered until they're read
+	var count int
+	for first.Next() {
+		count++
+	}
+	require.NoError(t, first.Err())
+	require.Equal(t, 202, count)
+}
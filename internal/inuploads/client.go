@@ -0,0 +1,368 @@
+// Package inuploads provides a wrapper for the OpenAI Uploads API.
+package inuploads
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/unkn0wncode/openai/files"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/uploads"
+)
+
+// Client is the client for the Uploads API.
+type Client struct {
+	*openai.Config
+}
+
+// NewClient creates a new client for the Uploads API.
+func NewClient(config *openai.Config) *Client {
+	return &Client{Config: config}
+}
+
+// interface compliance checks
+var _ uploads.Service = (*Client)(nil)
+
+// defaultConcurrency is the number of parts uploaded at once when not specified.
+const defaultConcurrency = 4
+
+// Create creates an upload session that accepts parts.
+func (c *Client) Create(data *uploads.CreateRequest) (*uploads.Upload, error) {
+	if data == nil {
+		return nil, errors.New("create request is nil")
+	}
+
+	payload := map[string]any{
+		"filename":  data.Filename,
+		"purpose":   data.Purpose,
+		"bytes":     data.Bytes,
+		"mime_type": data.MimeType,
+	}
+	if data.ExpiresAfter != 0 {
+		payload["expires_after"] = map[string]any{
+			"anchor":  "created_at",
+			"seconds": int(data.ExpiresAfter / time.Second),
+		}
+	}
+
+	return c.post(context.Background(), "v1/uploads", payload)
+}
+
+// AddPart uploads a part of the upload. The content is streamed without buffering
+// and the request is not retried.
+func (c *Client) AddPart(ctx context.Context, uploadID string, data io.Reader) (*uploads.Part, error) {
+	if uploadID == "" {
+		return nil, errors.New("upload ID is empty")
+	}
+
+	resp, err := c.PostMultipart(ctx, "v1/uploads/"+url.PathEscape(uploadID)+"/parts", nil, openai.MultipartFile{
+		Field:    "data",
+		Filename: "part",
+		Content:  data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var part uploads.Part
+	if err := json.Unmarshal(body, &part); err != nil {
+		return nil, fmt.Errorf("failed to decode part: %w", err)
+	}
+	return &part, nil
+}
+
+// Complete completes the upload with parts in given order and returns the upload with the created file.
+func (c *Client) Complete(uploadID string, partIDs []string, md5 string) (*uploads.Upload, error) {
+	if uploadID == "" {
+		return nil, errors.New("upload ID is empty")
+	}
+
+	payload := map[string]any{"part_ids": partIDs}
+	if md5 != "" {
+		payload["md5"] = md5
+	}
+	return c.post(context.Background(), "v1/uploads/"+url.PathEscape(uploadID)+"/complete", payload)
+}
+
+// Cancel cancels the upload, no parts can be added after it.
+func (c *Client) Cancel(uploadID string) (*uploads.Upload, error) {
+	if uploadID == "" {
+		return nil, errors.New("upload ID is empty")
+	}
+	return c.post(context.Background(), "v1/uploads/"+url.PathEscape(uploadID)+"/cancel", nil)
+}
+
+// UploadFile uploads the content in parts concurrently, retrying failed parts,
+// and completes the upload with its MD5 checksum. Returns the created file.
+func (c *Client) UploadFile(ctx context.Context, content io.ReaderAt, data *uploads.FileRequest) (*files.File, error) {
+	switch {
+	case data == nil:
+		return nil, errors.New("file request is nil")
+	case content == nil:
+		return nil, errors.New("content is nil")
+	case data.Size <= 0 || data.Size > uploads.MaxUploadSize:
+		return nil, fmt.Errorf("size must be between 1 and %d bytes, got %d", int64(uploads.MaxUploadSize), data.Size)
+	case data.PartSize < 0 || data.PartSize > uploads.MaxPartSize:
+		return nil, fmt.Errorf("part size must be up to %d bytes, got %d", uploads.MaxPartSize, data.PartSize)
+	}
+
+	state, err := c.startUpload(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// the checksum is calculated while parts are uploaded and stops with them
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	checksum := make(chan string, 1)
+	checksumErr := make(chan error, 1)
+	go func() {
+		h := md5.New()
+		if _, err := io.Copy(h, &ctxReader{ctx: ctx, r: io.NewSectionReader(content, 0, data.Size)}); err != nil {
+			checksumErr <- fmt.Errorf("failed to calculate checksum: %w", err)
+			return
+		}
+		checksum <- hex.EncodeToString(h.Sum(nil))
+	}()
+
+	before := time.Now()
+	if err := c.uploadParts(ctx, content, data, state); err != nil {
+		return nil, err
+	}
+
+	var sum string
+	select {
+	case sum = <-checksum:
+	case err := <-checksumErr:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	upload, err := c.Complete(state.UploadID, state.PartIDs, sum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete upload: %w", err)
+	}
+	if upload.Status != uploads.StatusCompleted || upload.File == nil {
+		return nil, fmt.Errorf("upload '%s' is not completed, status: %s", upload.ID, upload.Status)
+	}
+
+	c.Log.Debug(fmt.Sprintf(
+		"Uploaded file '%s' (%d bytes) in %d parts as %s in %s",
+		upload.File.Filename, upload.File.Bytes, len(state.PartIDs), upload.File.ID, time.Since(before),
+	))
+	return upload.File, nil
+}
+
+// ctxReader is a reader that stops reading when the context is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// startUpload creates a new upload or checks the state to resume, returns the state to continue with.
+func (c *Client) startUpload(data *uploads.FileRequest) (*uploads.State, error) {
+	if s := data.Resume; s != nil {
+		state := *s
+		state.PartIDs = slices.Clone(s.PartIDs)
+
+		switch {
+		case state.UploadID == "":
+			return nil, errors.New("resumed state has no upload ID")
+		case state.Size != data.Size:
+			return nil, fmt.Errorf("size %d differs from size of resumed upload %d", data.Size, state.Size)
+		case state.PartSize <= 0 || len(state.PartIDs) != partCount(state.Size, state.PartSize):
+			return nil, fmt.Errorf("resumed state has %d parts of %d bytes for %d bytes", len(state.PartIDs), state.PartSize, state.Size)
+		case !state.ExpiresAt.IsZero() && time.Now().After(state.ExpiresAt):
+			return nil, fmt.Errorf("upload '%s' expired at %s", state.UploadID, state.ExpiresAt)
+		}
+		return &state, nil
+	}
+
+	upload, err := c.Create(&uploads.CreateRequest{
+		Filename:     data.Filename,
+		Purpose:      data.Purpose,
+		Bytes:        data.Size,
+		MimeType:     data.MimeType,
+		ExpiresAfter: data.ExpiresAfter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	partSize := cmp.Or(data.PartSize, uploads.MaxPartSize)
+	state := &uploads.State{
+		UploadID: upload.ID,
+		Size:     data.Size,
+		PartSize: partSize,
+		PartIDs:  make([]string, partCount(data.Size, partSize)),
+	}
+	if upload.ExpiresAt != 0 {
+		state.ExpiresAt = time.Unix(upload.ExpiresAt, 0)
+	}
+	if data.OnState != nil {
+		data.OnState(*state)
+	}
+	return state, nil
+}
+
+// partCount returns the number of parts of given size needed for the content.
+func partCount(size, partSize int64) int {
+	return int((size + partSize - 1) / partSize)
+}
+
+// uploadParts uploads parts missing in the state concurrently and fills their IDs.
+// Callbacks are called one at a time. Stops on the first failed part.
+func (c *Client) uploadParts(ctx context.Context, content io.ReaderAt, data *uploads.FileRequest, state *uploads.State) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	progress := uploads.Progress{TotalBytes: state.Size, TotalParts: len(state.PartIDs)}
+	var missing []int
+	for i, id := range state.PartIDs {
+		if id == "" {
+			missing = append(missing, i)
+			continue
+		}
+		progress.Parts++
+		progress.Bytes += partLength(state, i)
+	}
+
+	attempts := max(cmp.Or(data.PartAttempts, c.HTTPClient.RequestAttempts), 1)
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	indexes := make(chan int)
+	for range min(cmp.Or(data.Concurrency, defaultConcurrency), len(missing)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				var part *uploads.Part
+				err := openai.Retry(ctx, attempts, c.HTTPClient.RetryInterval, func() error {
+					var err error
+					section := io.NewSectionReader(content, int64(i)*state.PartSize, partLength(state, i))
+					part, err = c.AddPart(ctx, state.UploadID, section)
+					return err
+				})
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("failed to upload part %d of upload '%s': %w", i, state.UploadID, err)
+						cancel()
+					}
+					mu.Unlock()
+					continue
+				}
+
+				state.PartIDs[i] = part.ID
+				progress.Parts++
+				progress.Bytes += partLength(state, i)
+				if data.OnState != nil {
+					snapshot := *state
+					snapshot.PartIDs = slices.Clone(state.PartIDs)
+					data.OnState(snapshot)
+				}
+				if data.OnProgress != nil {
+					data.OnProgress(progress)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, i := range missing {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// partLength returns the length of the part with given index.
+func partLength(state *uploads.State, i int) int64 {
+	return min(state.PartSize, state.Size-int64(i)*state.PartSize)
+}
+
+// post sends a JSON payload to the endpoint and decodes an upload from the response.
+func (c *Client) post(ctx context.Context, endpoint string, payload any) (*uploads.Upload, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		b, err := openai.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseAPI+endpoint, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.AddHeaders(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var upload uploads.Upload
+	if err := json.Unmarshal(body, &upload); err != nil {
+		return nil, fmt.Errorf("failed to decode upload: %w", err)
+	}
+	return &upload, nil
+}
+
+// readBody reads and closes the response body, returns an APIError for failed responses.
+func readBody(resp *http.Response) ([]byte, error) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, openai.NewAPIError(resp)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,332 @@
+package inuploads
+
+import (
+	"cmp"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/files"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/uploads"
+)
+
+// fakeUploads is a fake Uploads API that keeps received parts.
+type fakeUploads struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	parts    map[string]string // part ID -> content
+	failures map[string]int    // part content -> number of requests to fail
+	status   int               // status of failed requests, default is 500
+	received []string          // contents of received parts
+	delay    time.Duration     // delay of responses to parts
+}
+
+func (f *fakeUploads) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/v1/uploads":
+		var req map[string]any
+		assert.NoError(f.t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(f.t, "data.jsonl", req["filename"])
+		assert.Equal(f.t, map[string]any{"anchor": "created_at", "seconds": float64(7200)}, req["expires_after"])
+		fmt.Fprintf(w, `{"id":"upload_1","object":"upload","bytes":%v,"status":"pending","expires_at":4102444800}`, req["bytes"])
+
+	case r.URL.Path == "/v1/uploads/upload_1/parts":
+		file, _, err := r.FormFile("data")
+		if !assert.NoError(f.t, err) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		b, err := io.ReadAll(file)
+		assert.NoError(f.t, err)
+		f.received = append(f.received, string(b))
+		time.Sleep(f.delay)
+		if f.failures[string(b)] > 0 {
+			f.failures[string(b)]--
+			w.WriteHeader(cmp.Or(f.status, http.StatusInternalServerError))
+			fmt.Fprint(w, `{"error":{"message":"part failed","type":"server_error"}}`)
+			return
+		}
+		id := fmt.Sprintf("part_%d", len(f.parts))
+		f.parts[id] = string(b)
+		fmt.Fprintf(w, `{"id":"%s","object":"upload.part","upload_id":"upload_1"}`, id)
+
+	case r.URL.Path == "/v1/uploads/upload_1/complete":
+		var req struct {
+			PartIDs []string `json:"part_ids"`
+			MD5     string   `json:"md5"`
+		}
+		assert.NoError(f.t, json.NewDecoder(r.Body).Decode(&req))
+		var content strings.Builder
+		for _, id := range req.PartIDs {
+			content.WriteString(f.parts[id])
+		}
+		sum := md5.Sum([]byte(content.String()))
+		assert.Equal(f.t, hex.EncodeToString(sum[:]), req.MD5)
+		fmt.Fprintf(w, `{"id":"upload_1","object":"upload","status":"completed",`+
+			`"file":{"id":"file-1","object":"file","bytes":%d,"filename":"data.jsonl","purpose":"batch"}}`, content.Len())
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestUploadFile(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeUploads{
+		t:        t,
+		parts:    map[string]string{},
+		failures: map[string]int{"def": 1},
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	config.HTTPClient.RetryInterval = 0
+	client := NewClient(config)
+
+	content := "abcdefghij"
+	req := &uploads.FileRequest{
+		Filename:     "data.jsonl",
+		Purpose:      files.PurposeBatch,
+		MimeType:     "application/jsonl",
+		Size:         int64(len(content)),
+		ExpiresAfter: 2 * time.Hour,
+		PartSize:     3,
+		Concurrency:  2,
+	}
+	var progress []uploads.Progress
+	req.OnProgress = func(p uploads.Progress) {
+		progress = append(progress, p)
+	}
+
+	file, err := client.UploadFile(context.Background(), strings.NewReader(content), req)
+	require.NoError(t, err)
+	require.Equal(t, "file-1", file.ID)
+	require.EqualValues(t, len(content), file.Bytes)
+
+	// the failed part is retried
+	require.ElementsMatch(t, []string{"abc", "def", "def", "ghi", "j"}, fake.received)
+	require.Len(t, progress, 4)
+	require.Equal(t, uploads.Progress{Bytes: 10, TotalBytes: 10, Parts: 4, TotalParts: 4}, progress[3])
+}
+
+func TestUploadFileResume(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeUploads{
+		t:        t,
+		parts:    map[string]string{},
+		failures: map[string]int{"ghi": 1},
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	content := "abcdefghij"
+	var saved []byte
+	req := &uploads.FileRequest{
+		Filename:     "data.jsonl",
+		Purpose:      files.PurposeBatch,
+		MimeType:     "application/jsonl",
+		Size:         int64(len(content)),
+		ExpiresAfter: 2 * time.Hour,
+		PartSize:     3,
+		Concurrency:  1,
+		PartAttempts: 1,
+		OnState: func(s uploads.State) {
+			var err error
+			saved, err = json.Marshal(s)
+			assert.NoError(t, err)
+		},
+	}
+
+	_, err := client.UploadFile(context.Background(), strings.NewReader(content), req)
+	var apiErr *openai.APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+
+	var state uploads.State
+	require.NoError(t, json.Unmarshal(saved, &state))
+	require.Equal(t, "upload_1", state.UploadID)
+	require.Equal(t, []string{"part_0", "part_1", ""}, state.PartIDs[:3])
+	require.False(t, state.Done())
+
+	req.Resume = &state
+	file, err := client.UploadFile(context.Background(), strings.NewReader(content), req)
+	require.NoError(t, err)
+	require.Equal(t, "file-1", file.ID)
+	require.Equal(t, []string{"abc", "def", "ghi", "ghi", "j"}, fake.received)
+
+	// states for other content are rejected
+	req.Size = 5
+	_, err = client.UploadFile(context.Background(), strings.NewReader(content), req)
+	require.ErrorContains(t, err, "differs")
+}
+
+func TestUploadFileRetries(t *testing.T) {
+	t.Parallel()
+
+	upload := func(t *testing.T, ctx context.Context, fake *fakeUploads, interval time.Duration) error {
+		server := httptest.NewServer(fake)
+		t.Cleanup(server.Close)
+
+		config := openai.NewConfig("test")
+		config.BaseAPI = server.URL + "/"
+		config.HTTPClient.RetryInterval = interval
+		client := NewClient(config)
+
+		_, err := client.UploadFile(ctx, strings.NewReader("abc"), &uploads.FileRequest{
+			Filename:     "data.jsonl",
+			Purpose:      files.PurposeBatch,
+			MimeType:     "application/jsonl",
+			Size:         3,
+			ExpiresAfter: 2 * time.Hour,
+			PartSize:     3,
+			PartAttempts: 3,
+		})
+		return err
+	}
+
+	t.Run("ClientError", func(t *testing.T) {
+		t.Parallel()
+
+		fake := &fakeUploads{
+			t:        t,
+			parts:    map[string]string{},
+			failures: map[string]int{"abc": 3},
+			status:   http.StatusBadRequest,
+		}
+		err := upload(t, context.Background(), fake, 0)
+		var apiErr *openai.APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+
+		// client errors are not retried
+		require.Equal(t, []string{"abc"}, fake.received)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		t.Parallel()
+
+		fake := &fakeUploads{
+			t:        t,
+			parts:    map[string]string{},
+			failures: map[string]int{"abc": 3},
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// the wait between attempts ends with the context
+		start := time.Now()
+		err := upload(t, ctx, fake, time.Hour)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), 5*time.Second)
+		require.Equal(t, []string{"abc"}, fake.received)
+	})
+}
+
+func TestUploadFileSlowParts(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeUploads{
+		t:     t,
+		parts: map[string]string{},
+		delay: 100 * time.Millisecond,
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	config.HTTPClient.Timeout = 50 * time.Millisecond
+	client := NewClient(config)
+
+	req := &uploads.FileRequest{
+		Filename:     "data.jsonl",
+		Purpose:      files.PurposeBatch,
+		MimeType:     "application/jsonl",
+		Size:         6,
+		ExpiresAfter: 2 * time.Hour,
+		PartSize:     3,
+		PartAttempts: 1,
+	}
+
+	// parts taking longer than the timeout of the client are not cut off
+	file, err := client.UploadFile(context.Background(), strings.NewReader("abcdef"), req)
+	require.NoError(t, err)
+	require.Equal(t, "file-1", file.ID)
+
+	// the context limits them instead
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.UploadFile(ctx, strings.NewReader("abcdef"), req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// countingReaderAt counts bytes read from the underlying reader.
+type countingReaderAt struct {
+	r     io.ReaderAt
+	count atomic.Int64
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.r.ReadAt(p, off)
+	c.count.Add(int64(n))
+	return n, err
+}
+
+func TestUploadFileCancelledChecksum(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeUploads{
+		t:     t,
+		parts: map[string]string{},
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	size := 1 << 16
+	content := &countingReaderAt{r: strings.NewReader(strings.Repeat("a", size))}
+	req := &uploads.FileRequest{
+		Filename:     "data.jsonl",
+		Purpose:      files.PurposeBatch,
+		MimeType:     "application/jsonl",
+		Size:         int64(size),
+		ExpiresAfter: 2 * time.Hour,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.UploadFile(ctx, content, req)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// the checksum is not calculated for a failed upload
+	time.Sleep(50 * time.Millisecond)
+	require.Less(t, content.count.Load(), int64(size))
+}
@@ -0,0 +1,410 @@
+// Package invectorstores provides a wrapper for the OpenAI Vector Stores API.
+package invectorstores
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/unkn0wncode/openai/filters"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/vectorstores"
+)
+
+// Client is the client for the Vector Stores API.
+type Client struct {
+	*openai.Config
+}
+
+// NewClient creates a new client for the Vector Stores API.
+func NewClient(config *openai.Config) *Client {
+	return &Client{Config: config}
+}
+
+// interface compliance checks
+var _ vectorstores.Service = (*Client)(nil)
+
+// Create creates a vector store, optionally with files.
+func (c *Client) Create(data *vectorstores.CreateRequest) (*vectorstores.VectorStore, error) {
+	if data == nil {
+		data = &vectorstores.CreateRequest{}
+	}
+	if err := data.ChunkingStrategy.Validate(); err != nil {
+		return nil, err
+	}
+
+	var store vectorstores.VectorStore
+	if err := c.do(context.Background(), http.MethodPost, "v1/vector_stores", data, &store); err != nil {
+		return nil, err
+	}
+	return &store, nil
+}
+
+// Retrieve returns a vector store by ID.
+func (c *Client) Retrieve(id string) (*vectorstores.VectorStore, error) {
+	if id == "" {
+		return nil, errors.New("vector store ID is empty")
+	}
+
+	var store vectorstores.VectorStore
+	if err := c.do(context.Background(), http.MethodGet, storePath(id), nil, &store); err != nil {
+		return nil, err
+	}
+	return &store, nil
+}
+
+// Update changes name, expiration or metadata of a vector store.
+func (c *Client) Update(id string, data *vectorstores.UpdateRequest) (*vectorstores.VectorStore, error) {
+	switch {
+	case id == "":
+		return nil, errors.New("vector store ID is empty")
+	case data == nil:
+		return nil, errors.New("update request is nil")
+	}
+
+	var store vectorstores.VectorStore
+	if err := c.do(context.Background(), http.MethodPost, storePath(id), data, &store); err != nil {
+		return nil, err
+	}
+	return &store, nil
+}
+
+// Delete deletes a vector store, files themselves are not deleted.
+func (c *Client) Delete(id string) error {
+	if id == "" {
+		return errors.New("vector store ID is empty")
+	}
+	return c.delete(storePath(id))
+}
+
+// List returns a page of vector stores.
+func (c *Client) List(opts *vectorstores.ListOptions) (*vectorstores.List, error) {
+	endpoint := "v1/vector_stores"
+	if opts != nil {
+		endpoint += listQuery(opts, nil)
+	}
+
+	var list vectorstores.List
+	if err := c.do(context.Background(), http.MethodGet, endpoint, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// AddFile attaches an uploaded file to a vector store, the file is ingested asynchronously.
+func (c *Client) AddFile(storeID string, data *vectorstores.FileRequest) (*vectorstores.File, error) {
+	switch {
+	case storeID == "":
+		return nil, errors.New("vector store ID is empty")
+	case data == nil:
+		return nil, errors.New("file request is nil")
+	}
+	if err := validateFile(data); err != nil {
+		return nil, err
+	}
+
+	var file vectorstores.File
+	if err := c.do(context.Background(), http.MethodPost, storePath(storeID)+"/files", data, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// RetrieveFile returns a file of a vector store.
+func (c *Client) RetrieveFile(storeID, fileID string) (*vectorstores.File, error) {
+	return c.retrieveFile(context.Background(), storeID, fileID)
+}
+
+// retrieveFile returns a file of a vector store with given context.
+func (c *Client) retrieveFile(ctx context.Context, storeID, fileID string) (*vectorstores.File, error) {
+	if storeID == "" || fileID == "" {
+		return nil, errors.New("vector store ID and file ID are required")
+	}
+
+	var file vectorstores.File
+	if err := c.do(ctx, http.MethodGet, filePath(storeID, fileID), nil, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// UpdateFileAttributes replaces attributes of a file used for filtering in search.
+func (c *Client) UpdateFileAttributes(storeID, fileID string, attributes map[string]any) (*vectorstores.File, error) {
+	if storeID == "" || fileID == "" {
+		return nil, errors.New("vector store ID and file ID are required")
+	}
+	if err := vectorstores.ValidateAttributes(attributes); err != nil {
+		return nil, err
+	}
+	if attributes == nil {
+		// an empty object removes all attributes
+		attributes = map[string]any{}
+	}
+
+	var file vectorstores.File
+	payload := map[string]any{"attributes": attributes}
+	if err := c.do(context.Background(), http.MethodPost, filePath(storeID, fileID), payload, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// ListFiles returns a page of files of a vector store.
+func (c *Client) ListFiles(storeID string, opts *vectorstores.FileListOptions) (*vectorstores.FileList, error) {
+	if storeID == "" {
+		return nil, errors.New("vector store ID is empty")
+	}
+
+	endpoint := storePath(storeID) + "/files"
+	if opts != nil {
+		var extra url.Values
+		if opts.Filter != "" {
+			extra = url.Values{"filter": {opts.Filter}}
+		}
+		endpoint += listQuery(&opts.ListOptions, extra)
+	}
+
+	var list vectorstores.FileList
+	if err := c.do(context.Background(), http.MethodGet, endpoint, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// RemoveFile detaches a file from a vector store, the file itself is not deleted.
+func (c *Client) RemoveFile(storeID, fileID string) error {
+	if storeID == "" || fileID == "" {
+		return errors.New("vector store ID and file ID are required")
+	}
+	return c.delete(filePath(storeID, fileID))
+}
+
+// PollFile fetches the file until its ingestion is finished: completed, failed or cancelled.
+func (c *Client) PollFile(ctx context.Context, storeID, fileID string, interval time.Duration) (*vectorstores.File, error) {
+	for {
+		file, err := c.retrieveFile(ctx, storeID, fileID)
+		if err != nil {
+			return nil, err
+		}
+		if file.Status != vectorstores.FileStatusInProgress {
+			return file, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return file, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// CreateFileBatch attaches multiple uploaded files to a vector store at once.
+func (c *Client) CreateFileBatch(storeID string, data *vectorstores.FileBatchRequest) (*vectorstores.FileBatch, error) {
+	switch {
+	case storeID == "":
+		return nil, errors.New("vector store ID is empty")
+	case data == nil:
+		return nil, errors.New("file batch request is nil")
+	case len(data.FileIDs) == 0 && len(data.Files) == 0:
+		return nil, errors.New("file batch has no files")
+	case len(data.FileIDs) > 0 && len(data.Files) > 0:
+		return nil, errors.New("only one of FileIDs and Files can be set")
+	}
+	if err := vectorstores.ValidateAttributes(data.Attributes); err != nil {
+		return nil, err
+	}
+	if err := data.ChunkingStrategy.Validate(); err != nil {
+		return nil, err
+	}
+	for i := range data.Files {
+		if err := validateFile(&data.Files[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	var batch vectorstores.FileBatch
+	if err := c.do(context.Background(), http.MethodPost, storePath(storeID)+"/file_batches", data, &batch); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// RetrieveFileBatch returns a file batch of a vector store.
+func (c *Client) RetrieveFileBatch(storeID, batchID string) (*vectorstores.FileBatch, error) {
+	return c.fileBatch(context.Background(), http.MethodGet, storeID, batchID, "")
+}
+
+// CancelFileBatch cancels ingestion of files of a batch.
+func (c *Client) CancelFileBatch(storeID, batchID string) (*vectorstores.FileBatch, error) {
+	return c.fileBatch(context.Background(), http.MethodPost, storeID, batchID, "/cancel")
+}
+
+// PollFileBatch fetches the file batch until its ingestion is finished: completed, failed or cancelled.
+func (c *Client) PollFileBatch(ctx context.Context, storeID, batchID string, interval time.Duration) (*vectorstores.FileBatch, error) {
+	for {
+		batch, err := c.fileBatch(ctx, http.MethodGet, storeID, batchID, "")
+		if err != nil {
+			return nil, err
+		}
+		if batch.Status != vectorstores.FileStatusInProgress {
+			return batch, nil
+		}
+
+		c.Log.Debug(fmt.Sprintf(
+			"File batch %s of vector store %s is in progress, %d/%d files completed",
+			batchID, storeID, batch.FileCounts.Completed, batch.FileCounts.Total,
+		))
+
+		select {
+		case <-ctx.Done():
+			return batch, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// fileBatch sends a request without body about a file batch.
+func (c *Client) fileBatch(ctx context.Context, method, storeID, batchID, suffix string) (*vectorstores.FileBatch, error) {
+	if storeID == "" || batchID == "" {
+		return nil, errors.New("vector store ID and batch ID are required")
+	}
+
+	var batch vectorstores.FileBatch
+	endpoint := storePath(storeID) + "/file_batches/" + url.PathEscape(batchID) + suffix
+	if err := c.do(ctx, method, endpoint, nil, &batch); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// Search searches a vector store for chunks relevant to the query.
+func (c *Client) Search(storeID string, data *vectorstores.SearchRequest) (*vectorstores.SearchResults, error) {
+	switch {
+	case storeID == "":
+		return nil, errors.New("vector store ID is empty")
+	case data == nil:
+		return nil, errors.New("search request is nil")
+	case data.MaxNumResults < 0 || data.MaxNumResults > 50:
+		return nil, fmt.Errorf("MaxNumResults must be from 1 to 50, got %d", data.MaxNumResults)
+	}
+	switch q := data.Query.(type) {
+	case string:
+		if q == "" {
+			return nil, errors.New("query is empty")
+		}
+	case []string:
+		if len(q) == 0 {
+			return nil, errors.New("query is empty")
+		}
+	default:
+		return nil, fmt.Errorf("query must be a string or []string, got %T", data.Query)
+	}
+
+	if err := filters.Validate(data.Filters); err != nil {
+		return nil, fmt.Errorf("invalid filters: %w", err)
+	}
+
+	var results vectorstores.SearchResults
+	if err := c.do(context.Background(), http.MethodPost, storePath(storeID)+"/search", data, &results); err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
+
+// validateFile checks a file request.
+func validateFile(data *vectorstores.FileRequest) error {
+	if data.FileID == "" {
+		return errors.New("file ID is required")
+	}
+	if err := vectorstores.ValidateAttributes(data.Attributes); err != nil {
+		return fmt.Errorf("invalid attributes of file '%s': %w", data.FileID, err)
+	}
+	return data.ChunkingStrategy.Validate()
+}
+
+// storePath returns the endpoint of a vector store.
+func storePath(id string) string {
+	return "v1/vector_stores/" + url.PathEscape(id)
+}
+
+// filePath returns the endpoint of a file of a vector store.
+func filePath(storeID, fileID string) string {
+	return storePath(storeID) + "/files/" + url.PathEscape(fileID)
+}
+
+// listQuery returns the query string of pagination options with extra values.
+func listQuery(opts *vectorstores.ListOptions, values url.Values) string {
+	if values == nil {
+		values = url.Values{}
+	}
+	if opts.Limit > 0 {
+		values.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.After != "" {
+		values.Set("after", opts.After)
+	}
+	if opts.Before != "" {
+		values.Set("before", opts.Before)
+	}
+	if opts.Order != "" {
+		values.Set("order", opts.Order)
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
+
+// delete deletes an object and checks the result.
+func (c *Client) delete(endpoint string) error {
+	var result struct {
+		ID      string `json:"id"`
+		Deleted bool   `json:"deleted"`
+	}
+	if err := c.do(context.Background(), http.MethodDelete, endpoint, nil, &result); err != nil {
+		return err
+	}
+	if !result.Deleted {
+		return fmt.Errorf("'%s' was not deleted", result.ID)
+	}
+	return nil
+}
+
+// do sends a request with optional JSON payload to the endpoint and decodes the response into v.
+func (c *Client) do(ctx context.Context, method, endpoint string, payload, v any) error {
+	var reqBody io.Reader
+	if payload != nil {
+		b, err := openai.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseAPI+endpoint, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	c.AddHeaders(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return openai.NewAPIError(resp)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
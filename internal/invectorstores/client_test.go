@@ -0,0 +1,157 @@
+package invectorstores
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/content/output"
+	"github.com/unkn0wncode/openai/filters"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/vectorstores"
+)
+
+func TestVectorStores(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		polls int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var body map[string]any
+		if r.Method == http.MethodPost {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1/vector_stores":
+			assert.Equal(t, "docs", body["name"])
+			assert.Equal(t, map[string]any{
+				"type":   "static",
+				"static": map[string]any{"max_chunk_size_tokens": float64(400), "chunk_overlap_tokens": float64(100)},
+			}, body["chunking_strategy"])
+			fmt.Fprint(w, `{"id":"vs_1","object":"vector_store","name":"docs","status":"completed"}`)
+
+		case "POST /v1/vector_stores/vs_1/files":
+			assert.Equal(t, "file-1", body["file_id"])
+			assert.Equal(t, map[string]any{"region": "us"}, body["attributes"])
+			fmt.Fprint(w, `{"id":"file-1","object":"vector_store.file","vector_store_id":"vs_1","status":"in_progress"}`)
+
+		case "GET /v1/vector_stores/vs_1/files/file-1":
+			polls++
+			status := vectorstores.FileStatusInProgress
+			if polls > 1 {
+				status = vectorstores.FileStatusCompleted
+			}
+			fmt.Fprintf(w, `{"id":"file-1","object":"vector_store.file","vector_store_id":"vs_1","status":%q}`, status)
+
+		case "POST /v1/vector_stores/vs_1/files/file-1":
+			assert.Equal(t, map[string]any{"attributes": map[string]any{"region": "eu", "year": float64(2024)}}, body)
+			fmt.Fprint(w, `{"id":"file-1","object":"vector_store.file","status":"completed","attributes":{"region":"eu","year":2024}}`)
+
+		case "GET /v1/vector_stores/vs_1/files":
+			assert.Equal(t, "completed", r.URL.Query().Get("filter"))
+			assert.Equal(t, "5", r.URL.Query().Get("limit"))
+			fmt.Fprint(w, `{"object":"list","data":[{"id":"file-1","status":"completed"}],"first_id":"file-1","last_id":"file-1","has_more":false}`)
+
+		case "POST /v1/vector_stores/vs_1/search":
+			assert.Equal(t, "return policy", body["query"])
+			assert.Equal(t, float64(3), body["max_num_results"])
+			fmt.Fprint(w, `{"object":"vector_store.search_results.page","search_query":["return policy"],"data":[`+
+				`{"file_id":"file-1","filename":"policy.md","score":0.9,"attributes":{"region":"eu","year":2024},`+
+				`"content":[{"type":"text","text":"Returns are accepted"},{"type":"text","text":"within 30 days."}]}],"has_more":false,"next_page":null}`)
+
+		case "DELETE /v1/vector_stores/vs_1/files/file-1":
+			fmt.Fprint(w, `{"id":"file-1","object":"vector_store.file.deleted","deleted":true}`)
+
+		case "DELETE /v1/vector_stores/vs_1":
+			fmt.Fprint(w, `{"id":"vs_1","object":"vector_store.deleted","deleted":false}`)
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := openai.NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	client := NewClient(config)
+
+	store, err := client.Create(&vectorstores.CreateRequest{
+		Name:             "docs",
+		ChunkingStrategy: vectorstores.StaticChunking(400, 100),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "vs_1", store.ID)
+
+	_, err = client.AddFile(store.ID, &vectorstores.FileRequest{
+		FileID:     "file-1",
+		Attributes: map[string]any{"region": "us"},
+	})
+	require.NoError(t, err)
+
+	file, err := client.PollFile(context.Background(), store.ID, "file-1", time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, vectorstores.FileStatusCompleted, file.Status)
+	require.Equal(t, 2, polls)
+
+	file, err = client.UpdateFileAttributes(store.ID, "file-1", map[string]any{"region": "eu", "year": 2024})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"region": "eu", "year": float64(2024)}, file.Attributes)
+
+	list, err := client.ListFiles(store.ID, &vectorstores.FileListOptions{
+		ListOptions: vectorstores.ListOptions{Limit: 5},
+		Filter:      vectorstores.FileStatusCompleted,
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Data, 1)
+
+	results, err := client.Search(store.ID, &vectorstores.SearchRequest{Query: "return policy", MaxNumResults: 3})
+	require.NoError(t, err)
+	require.Equal(t, []output.FileSearchResult{{
+		FileID:     "file-1",
+		FileName:   "policy.md",
+		Score:      0.9,
+		Text:       "Returns are accepted\nwithin 30 days.",
+		Attributes: map[string]string{"region": "eu", "year": "2024"},
+	}}, results.FileSearchResults())
+
+	require.NoError(t, client.RemoveFile(store.ID, "file-1"))
+	require.ErrorContains(t, client.Delete(store.ID), "was not deleted")
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(openai.NewConfig("test"))
+
+	_, err := client.Create(&vectorstores.CreateRequest{ChunkingStrategy: vectorstores.StaticChunking(400, 300)})
+	require.ErrorContains(t, err, "chunk overlap")
+
+	_, err = client.AddFile("vs_1", &vectorstores.FileRequest{FileID: "file-1", Attributes: map[string]any{"tags": []string{"a"}}})
+	require.ErrorContains(t, err, "unsupported type")
+
+	_, err = client.UpdateFileAttributes("vs_1", "file-1", map[string]any{"long": strings.Repeat("a", 513)})
+	require.ErrorContains(t, err, "exceeds")
+
+	_, err = client.CreateFileBatch("vs_1", &vectorstores.FileBatchRequest{})
+	require.ErrorContains(t, err, "no files")
+
+	_, err = client.Search("vs_1", &vectorstores.SearchRequest{Query: 1})
+	require.ErrorContains(t, err, "string")
+
+	_, err = client.Search("vs_1", &vectorstores.SearchRequest{Query: "q", Filters: filters.Gt("year", "2024")})
+	require.ErrorContains(t, err, "needs a number")
+}
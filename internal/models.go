@@ -0,0 +1,116 @@
+// Package openai / internal / models.go resolves models of requests with the model registry.
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/unkn0wncode/openai/models"
+)
+
+// ResolveModel returns the model for given name from the model registry,
+// warns once per model if it's deprecated.
+func (c *Config) ResolveModel(name string) string {
+	model := c.Models.Resolve(name)
+	c.warnDeprecated(model)
+	return model
+}
+
+// warnDeprecated logs a warning on the first use of a deprecated model.
+func (c *Config) warnDeprecated(model string) {
+	if !c.Models.IsDeprecated(model) {
+		return
+	}
+	if _, warned := c.deprecationWarnings.LoadOrStore(model, true); !warned {
+		c.Log.Warn(fmt.Sprintf("Model '%s' is deprecated", model))
+	}
+}
+
+// WithFallbacks calls send with each model of the fallback chain of given model
+// until it succeeds or fails with an error that isn't a reason to fall back, see shouldFallback.
+// Returns the error of the last attempt.
+func (c *Config) WithFallbacks(model string, send func(model string) error) error {
+	chain := c.Models.Chain(model)
+
+	var err error
+	for i, m := range chain {
+		c.warnDeprecated(m)
+		if err = send(m); err == nil || !shouldFallback(err) {
+			return err
+		}
+		if i < len(chain)-1 {
+			c.Log.Warn(fmt.Sprintf("Model '%s' failed, falling back to '%s': %s", m, chain[i+1], err))
+		}
+	}
+	return err
+}
+
+// shouldFallback reports whether the error is a reason to try a fallback model:
+// the model is not found, overloaded or the server failed.
+func shouldFallback(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch {
+	case apiErr.StatusCode == http.StatusNotFound, apiErr.StatusCode >= 500:
+		return true
+	case apiErr.StatusCode == http.StatusTooManyRequests:
+		// rate limits of the token apply to other models too, only overloading is a reason to switch
+		return apiErr.Type == "server_error" || strings.Contains(strings.ToLower(apiErr.Message), "overloaded")
+	}
+	return false
+}
+
+// ListModels fetches models available to the token from the API.
+func (c *Config) ListModels(ctx context.Context) ([]models.Model, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseAPI+"v1/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.AddHeaders(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, NewAPIError(resp)
+	}
+	defer resp.Body.Close()
+
+	var list struct {
+		Data []models.Model `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return list.Data, nil
+}
+
+// RefreshModels fetches models available to the token from the API
+// and sets them as available in the model registry.
+func (c *Config) RefreshModels(ctx context.Context) error {
+	if c.Models == nil {
+		return fmt.Errorf("model registry is nil")
+	}
+
+	list, err := c.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(list))
+	for i, model := range list {
+		ids[i] = model.ID
+	}
+	c.Models.SetAvailable(ids)
+
+	c.Log.Debug(fmt.Sprintf("Refreshed models, %d available", len(ids)))
+	return nil
+}
@@ -0,0 +1,123 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/models"
+)
+
+func TestModelRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := models.NewRegistry()
+	require.Equal(t, models.Default, r.Resolve(""))
+	require.Equal(t, "gpt-x", r.Resolve("gpt-x"))
+
+	r.SetAlias("fast", "small")
+	r.SetAlias("small", models.DefaultNano)
+	require.Equal(t, models.DefaultNano, r.Resolve("fast"))
+
+	r.SetDefault("fast")
+	require.Equal(t, models.DefaultNano, r.Resolve(""))
+
+	// cycles don't hang
+	r.SetAlias("a", "b")
+	r.SetAlias("b", "a")
+	require.Contains(t, []string{"a", "b"}, r.Resolve("a"))
+
+	r.SetFallbacks(models.DefaultNano, "fast", models.DefaultMini, "other")
+	require.Equal(t, []string{models.DefaultNano, models.DefaultMini, "other"}, r.Chain(""))
+
+	r.SetAvailable([]string{models.DefaultNano})
+	require.Equal(t, []string{models.DefaultNano}, r.Chain("fast"))
+
+	r.SetFallbacks(models.DefaultNano)
+	require.Equal(t, []string{models.DefaultNano}, r.Chain(models.DefaultNano))
+
+	// fallbacks set on an alias apply to its target model
+	r.SetAvailable([]string{models.DefaultNano, models.DefaultMini})
+	r.SetFallbacks("fast", models.DefaultMini)
+	require.Equal(t, []string{models.DefaultNano, models.DefaultMini}, r.Chain(models.DefaultNano))
+	require.Equal(t, []string{models.DefaultNano, models.DefaultMini}, r.Chain("fast"))
+	r.SetFallbacks("small")
+	require.Equal(t, []string{models.DefaultNano}, r.Chain("fast"))
+
+	require.True(t, r.IsDeprecated("gpt-4-1106-preview"))
+	require.False(t, r.IsDeprecated(models.Default))
+	r.SetDeprecated(models.Default, true)
+	require.True(t, r.IsDeprecated(models.Default))
+	r.SetDeprecated(models.Default, false)
+	require.False(t, r.IsDeprecated(models.Default))
+
+	var nilRegistry *models.Registry
+	require.Equal(t, models.Default, nilRegistry.Resolve(""))
+	require.Equal(t, []string{"gpt-x"}, nilRegistry.Chain("gpt-x"))
+}
+
+func TestWithFallbacks(t *testing.T) {
+	t.Parallel()
+
+	config := NewConfig("test")
+	config.Models.SetFallbacks("a", "b", "c")
+
+	failures := map[string]error{
+		"a": &APIError{StatusCode: http.StatusServiceUnavailable},
+		"b": fmt.Errorf("wrapped: %w", &APIError{StatusCode: http.StatusTooManyRequests, Message: "The engine is currently overloaded"}),
+	}
+	var tried []string
+	send := func(model string) error {
+		tried = append(tried, model)
+		return failures[model]
+	}
+
+	require.NoError(t, config.WithFallbacks("a", send))
+	require.Equal(t, []string{"a", "b", "c"}, tried)
+
+	// rate limits and other errors are returned as is
+	tried = nil
+	failures["a"] = &APIError{StatusCode: http.StatusTooManyRequests, Message: "Rate limit reached"}
+	require.ErrorIs(t, config.WithFallbacks("a", send), failures["a"])
+	require.Equal(t, []string{"a"}, tried)
+
+	tried = nil
+	failures["a"] = errors.New("connection refused")
+	require.ErrorIs(t, config.WithFallbacks("a", send), failures["a"])
+	require.Equal(t, []string{"a"}, tried)
+
+	// the last error is returned when all models fail
+	tried = nil
+	failures["a"] = &APIError{StatusCode: http.StatusNotFound}
+	failures["c"] = &APIError{StatusCode: http.StatusInternalServerError}
+	require.ErrorIs(t, config.WithFallbacks("a", send), failures["c"])
+	require.Equal(t, []string{"a", "b", "c"}, tried)
+}
+
+func TestRefreshModels(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/models", r.URL.Path)
+		assert.Equal(t, "Bearer test", r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"object":"list","data":[`+
+			`{"id":"gpt-a","object":"model","created":1,"owned_by":"system"},`+
+			`{"id":"gpt-b","object":"model","created":1,"owned_by":"system"}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	config := NewConfig("test")
+	config.BaseAPI = server.URL + "/"
+	config.Models.SetFallbacks("gpt-a", "gpt-b", "gpt-c")
+	require.Equal(t, []string{"gpt-a", "gpt-b", "gpt-c"}, config.Models.Chain("gpt-a"))
+
+	require.NoError(t, config.RefreshModels(context.Background()))
+	require.True(t, config.Models.IsAvailable("gpt-b"))
+	require.False(t, config.Models.IsAvailable("gpt-c"))
+	require.Equal(t, []string{"gpt-a", "gpt-b"}, config.Models.Chain("gpt-a"))
+}
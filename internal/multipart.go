@@ -0,0 +1,96 @@
+// Package openai / internal / multipart.go sends multipart requests with streamed bodies.
+package openai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+// MultipartFile is a file part of a multipart request.
+type MultipartFile struct {
+	Field    string
+	Filename string
+	Content  io.Reader
+}
+
+// LimitedReader reads from R and fails with Err when the content exceeds N bytes.
+// Unlike io.LimitedReader it doesn't cut the content off silently.
+type LimitedReader struct {
+	R   io.Reader
+	N   int64
+	Err error
+}
+
+func (l *LimitedReader) Read(p []byte) (int, error) {
+	if l.N < 0 {
+		return 0, l.Err
+	}
+	if int64(len(p)) > l.N+1 {
+		p = p[:l.N+1]
+	}
+	n, err := l.R.Read(p)
+	l.N -= int64(n)
+	if l.N < 0 {
+		return n, l.Err
+	}
+	return n, err
+}
+
+// PostMultipart sends a multipart POST request with fields and files to the endpoint.
+// The body is streamed while it's written, so file contents are not held in memory,
+// and for the same reason the request is not retried.
+// Fields are written in order of their names, files after them.
+// The overall Timeout of HTTPClient is not applied, as sending large files can take longer,
+// use ctx to limit the request instead.
+func (c *Config) PostMultipart(ctx context.Context, endpoint string, fields url.Values, files ...MultipartFile) (*http.Response, error) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeMultipart(mw, fields, files))
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseAPI+endpoint, pr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.AddHeaders(req)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	// HTTPClient.Do would read the whole body to be able to retry,
+	// and the timeout of the client covers reading the body too
+	resp, err := c.HTTPClient.WithoutTimeout().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	return resp, nil
+}
+
+// writeMultipart writes fields and files to the multipart writer and closes it.
+func writeMultipart(mw *multipart.Writer, fields url.Values, files []MultipartFile) error {
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		for _, value := range fields[name] {
+			if err := mw.WriteField(name, value); err != nil {
+				return fmt.Errorf("failed to write field '%s': %w", name, err)
+			}
+		}
+	}
+
+	for _, file := range files {
+		part, err := mw.CreateFormFile(file.Field, file.Filename)
+		if err != nil {
+			return fmt.Errorf("failed to create part '%s': %w", file.Field, err)
+		}
+		if _, err := io.Copy(part, file.Content); err != nil {
+			return fmt.Errorf("failed to write content of '%s': %w", file.Filename, err)
+		}
+	}
+
+	return mw.Close()
+}
@@ -8,13 +8,9 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
-	"os"
-	"sync"
 	"time"
 
 	"github.com/unkn0wncode/openai/util"
-
-	"github.com/pkoukk/tiktoken-go"
 )
 
 // DefaultBaseAPI is the default base URL for OpenAI API endpoints.
@@ -55,6 +51,15 @@ func NewHTTPClient() *HTTPClient {
 	}
 }
 
+// WithoutTimeout returns a copy of the underlying http.Client without the overall Timeout.
+// Use it for requests with long-running bodies, such as streams and large downloads,
+// and limit them with the request context instead.
+func (c *HTTPClient) WithoutTimeout() *http.Client {
+	client := *c.Client
+	client.Timeout = 0
+	return &client
+}
+
 // RoundTrip logs the request and response while performing round trip, if logger is set.
 func (lt *LoggingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	log := lt.Log
@@ -155,41 +160,6 @@ func (c *HTTPClient) WithRetry(req *http.Request) (*http.Response, error) {
 	return resp, err
 }
 
-// Encoders for counting tokens.
-var (
-	encodersMux            sync.Mutex
-	TokenEncoderChat       *tiktoken.Tiktoken
-	TokenEncoderCompletion *tiktoken.Tiktoken
-)
-
-// LoadTokenEncoders loads token encoders for chat and completion models.
-// Uses cache directory.
-// Can be called repeatedly but will not reload encoders if they are already successfully loaded.
-func LoadTokenEncoders() error {
-	encodersMux.Lock()
-	defer encodersMux.Unlock()
-
-	if TokenEncoderChat != nil && TokenEncoderCompletion != nil {
-		return nil
-	}
-
-	err := os.Setenv("TIKTOKEN_CACHE_DIR", "./tiktoken")
-	if err != nil {
-		return fmt.Errorf("failed to set TIKTOK_CACHE_DIR env variable: %w", err)
-	}
-
-	TokenEncoderChat, err = tiktoken.GetEncoding(tiktoken.MODEL_CL100K_BASE)
-	if err != nil {
-		return fmt.Errorf("failed to get chat token encoder: %w", err)
-	}
-	TokenEncoderCompletion, err = tiktoken.GetEncoding(tiktoken.MODEL_P50K_BASE)
-	if err != nil {
-		return fmt.Errorf("failed to get completion token encoder: %w", err)
-	}
-
-	return nil
-}
-
 // Marshal marshals the given value to JSON.
 // HTML escaping is disabled.
 func Marshal(v any) ([]byte, error) {
@@ -0,0 +1,435 @@
+// Package openai / internal / sse.go implements a reader for server-sent events (SSE)
+// following the EventSource specification, shared by streaming API wrappers.
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SSEDone is the data payload that some APIs (e.g. Chat Completions) send as the last event
+// to indicate the end of the stream.
+const SSEDone = "[DONE]"
+
+// defaultSSEMaxLineSize is the default limit for a single line in the event stream.
+const defaultSSEMaxLineSize = 32 * 1024 * 1024
+
+// SSEEvent is a single dispatched server-sent event.
+type SSEEvent struct {
+	// Event is the event type from the "event" field, "message" if the field was not present.
+	Event string
+	// Data is the joined payload of all "data" fields of the event, without the trailing newline.
+	Data []byte
+	// ID is the last event ID seen in the stream so far, it persists between events.
+	ID string
+	// Retry is the reconnection time from the latest valid "retry" field, zero if not received.
+	Retry time.Duration
+}
+
+// IsDone reports whether the event carries the "[DONE]" terminator payload.
+func (e *SSEEvent) IsDone() bool {
+	return string(e.Data) == SSEDone
+}
+
+// SSEReader reads server-sent events from a stream.
+// Supported line endings are LF, CRLF and CR. Comment lines (starting with ":"),
+// commonly used for keep-alive, are skipped. Unknown fields are ignored.
+type SSEReader struct {
+	br *bufio.Reader
+
+	// MaxLineSize is the maximum size of a single line in bytes, zero means no limit.
+	MaxLineSize int
+
+	// OnComment is called for every comment line with the text after the colon, if set.
+	OnComment func(comment string)
+
+	started bool
+	skipLF  bool // previous line was terminated by CR, so a following LF must be skipped
+	lastID  string
+	retry   time.Duration
+	line    []byte
+}
+
+// NewSSEReader creates an SSEReader on top of given reader.
+func NewSSEReader(r io.Reader) *SSEReader {
+	return &SSEReader{
+		br:          bufio.NewReaderSize(r, 64*1024),
+		MaxLineSize: defaultSSEMaxLineSize,
+	}
+}
+
+// Next reads the stream until a complete event is dispatched and returns it.
+// Returns io.EOF when the stream ends, any incomplete event at the end of stream is discarded.
+func (r *SSEReader) Next() (*SSEEvent, error) {
+	var (
+		eventType string
+		data      bytes.Buffer
+		hasData   bool
+	)
+
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		// empty line dispatches the event
+		if len(line) == 0 {
+			if !hasData {
+				eventType = ""
+				continue
+			}
+
+			event := &SSEEvent{
+				Event: eventType,
+				Data:  bytes.TrimSuffix(data.Bytes(), []byte("\n")),
+				ID:    r.lastID,
+				Retry: r.retry,
+			}
+			if event.Event == "" {
+				event.Event = "message"
+			}
+			return event, nil
+		}
+
+		if line[0] == ':' {
+			if r.OnComment != nil {
+				r.OnComment(string(bytes.TrimPrefix(line[1:], []byte(" "))))
+			}
+			continue
+		}
+
+		field, value := line, []byte(nil)
+		if i := bytes.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], line[i+1:]
+			value = bytes.TrimPrefix(value, []byte(" "))
+		}
+
+		switch string(field) {
+		case "event":
+			eventType = string(value)
+		case "data":
+			data.Write(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if bytes.IndexByte(value, 0) < 0 {
+				r.lastID = string(value)
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(string(value), 10, 63); err == nil {
+				r.retry = time.Duration(ms) * time.Millisecond
+			}
+		default:
+			// unknown fields are ignored according to the spec
+		}
+	}
+}
+
+// LastEventID returns the last event ID received in the stream.
+func (r *SSEReader) LastEventID() string {
+	return r.lastID
+}
+
+// readLine reads a single line terminated by LF, CRLF or CR, without the terminator.
+// The returned slice is only valid until the next call.
+func (r *SSEReader) readLine() ([]byte, error) {
+	if !r.started {
+		r.started = true
+		// the stream may start with a UTF-8 BOM that must be ignored
+		if bom, err := r.br.Peek(3); err == nil && bytes.Equal(bom, []byte("\xEF\xBB\xBF")) {
+			//nolint:errcheck // the bytes are already buffered, discarding can't fail
+			r.br.Discard(3)
+		}
+	}
+
+	if r.skipLF {
+		r.skipLF = false
+		b, err := r.br.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if b[0] == '\n' {
+			//nolint:errcheck // the byte is already buffered, discarding can't fail
+			r.br.Discard(1)
+		}
+	}
+
+	r.line = r.line[:0]
+	for {
+		if r.br.Buffered() == 0 {
+			if _, err := r.br.Peek(1); err != nil {
+				if errors.Is(err, io.EOF) && len(r.line) > 0 {
+					// unterminated line at the end of stream can't complete an event
+					return nil, io.EOF
+				}
+				return nil, err
+			}
+		}
+
+		buf, _ := r.br.Peek(r.br.Buffered())
+		i := bytes.IndexAny(buf, "\r\n")
+		if i < 0 {
+			i = len(buf)
+		}
+		r.line = append(r.line, buf[:i]...)
+		if r.MaxLineSize > 0 && len(r.line) > r.MaxLineSize {
+			return nil, fmt.Errorf("event stream line exceeds %d bytes", r.MaxLineSize)
+		}
+
+		if i < len(buf) {
+			r.skipLF = buf[i] == '\r'
+			//nolint:errcheck // the bytes are already buffered, discarding can't fail
+			r.br.Discard(i + 1)
+			return r.line, nil
+		}
+
+		//nolint:errcheck // the bytes are already buffered, discarding can't fail
+		r.br.Discard(len(buf))
+	}
+}
+
+// APIError is an error returned when the API responds with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+
+	// fields parsed from the standard error object in body, if present
+	Message string
+	Type    string
+	Code    string
+	Param   string
+
+	// RetryAfter is the delay requested by the server with Retry-After headers, if any.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("request failed with status: %s, error: %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("request failed with status: %s, body: %s", e.Status, string(e.Body))
+}
+
+// NewAPIError reads the body of a failed response and returns an APIError for it.
+// The body is closed afterwards.
+func NewAPIError(resp *http.Response) *APIError {
+	if resp.Body == nil {
+		return NewAPIErrorFromBody(resp, nil)
+	}
+	defer resp.Body.Close()
+
+	// error bodies are small, limit reading in case of a misbehaving server
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	return NewAPIErrorFromBody(resp, body)
+}
+
+// NewAPIErrorFromBody returns an APIError for a failed response with already read body.
+func NewAPIErrorFromBody(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       body,
+		RetryAfter: retryAfter(resp.Header),
+	}
+
+	var payload struct {
+		Error *struct {
+			Message string          `json:"message"`
+			Type    string          `json:"type"`
+			Code    json.RawMessage `json:"code"`
+			Param   string          `json:"param"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(apiErr.Body, &payload); err == nil && payload.Error != nil {
+		apiErr.Message = payload.Error.Message
+		apiErr.Type = payload.Error.Type
+		apiErr.Param = payload.Error.Param
+		// code may be a string or a number
+		var code string
+		if err := json.Unmarshal(payload.Error.Code, &code); err == nil {
+			apiErr.Code = code
+		} else if len(payload.Error.Code) > 0 && string(payload.Error.Code) != "null" {
+			apiErr.Code = string(payload.Error.Code)
+		}
+	}
+
+	return apiErr
+}
+
+// retryAfter returns the delay requested by the "retry-after-ms" or "Retry-After" header,
+// the latter in seconds or as an HTTP date. Returns 0 if there's none.
+func retryAfter(header http.Header) time.Duration {
+	if ms, err := strconv.ParseFloat(header.Get("retry-after-ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+
+	value := header.Get("Retry-After")
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
+
+// IsRetryable reports whether a failed request may succeed when sent again:
+// it was rate limited, the server failed or the network failed.
+// Validation errors, exceeded quota and cancelled contexts are not retryable.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode == http.StatusTooManyRequests {
+			return apiErr.Code != "insufficient_quota"
+		}
+		return apiErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// Retry calls f up to attempts times while it fails with retryable errors, see IsRetryable.
+// It waits the interval doubled after each failed attempt, or as long as the server asks
+// on rate limits. Returns the last error, or the context error if it ends while waiting.
+func Retry(ctx context.Context, attempts int, interval time.Duration, f func() error) error {
+	for i := range attempts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := f()
+		if err == nil || i == attempts-1 || !IsRetryable(err) {
+			return err
+		}
+
+		wait := interval << i
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			wait = apiErr.RetryAfter
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// CheckStreamResponse verifies that a response to a streaming request can be read as an
+// event stream. Returns an *APIError for non-2xx statuses, closing the body in that case.
+func CheckStreamResponse(resp *http.Response) error {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return NewAPIError(resp)
+	}
+	return nil
+}
+
+// SendItem delivers an item of a stream to the consumer unless the context is done.
+// Returns false if the item was not delivered.
+func SendItem(ctx context.Context, items chan<- any, item any) bool {
+	select {
+	case items <- item:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// ReceiveItem waits for the next item of a stream fed by SendItem or StreamSSE.
+// ok is false when the stream is over, err is then the error delivered by the producer
+// or the context error, nil if the stream is complete.
+// A producer stopped by the context closes the channel without delivering the error,
+// so the closed channel is reported with the context error rather than as a complete stream.
+func ReceiveItem(ctx context.Context, items <-chan any) (item any, ok bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	select {
+	case item, open := <-items:
+		if !open {
+			return nil, false, ctx.Err()
+		}
+		if err, isErr := item.(error); isErr {
+			return nil, false, err
+		}
+		return item, true, nil
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+}
+
+// StreamSSE reads events from the body in a goroutine and delivers items decoded from them
+// to the returned channel. decode returns nil to skip an event, or an error to end the stream
+// with it. The stream ends at EOF or at the "[DONE]" event, read errors end it with an error
+// replaced by the context error if the context is done. When the goroutine finishes, onDone is
+// called if set, then the channel and the body are closed.
+// Read the channel with ReceiveItem.
+func StreamSSE(ctx context.Context, body io.ReadCloser, decode func(event *SSEEvent) (any, error), onDone func()) <-chan any {
+	items := make(chan any)
+	go func() {
+		defer body.Close()
+		defer close(items)
+		if onDone != nil {
+			defer onDone()
+		}
+
+		reader := NewSSEReader(body)
+		for {
+			if err := ctx.Err(); err != nil {
+				SendItem(ctx, items, err)
+				return
+			}
+
+			event, err := reader.Next()
+			switch {
+			case err == nil:
+			case errors.Is(err, io.EOF):
+				return
+			default:
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					err = ctxErr
+				}
+				SendItem(ctx, items, err)
+				return
+			}
+
+			if event.IsDone() {
+				return
+			}
+
+			item, err := decode(event)
+			if err != nil {
+				SendItem(ctx, items, err)
+				return
+			}
+			if item == nil {
+				continue
+			}
+			if !SendItem(ctx, items, item) {
+				return
+			}
+		}
+	}()
+	return items
+}
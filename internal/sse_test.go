@@ -0,0 +1,209 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// readAllEvents reads events from a stream until EOF.
+func readAllEvents(t *testing.T, r *SSEReader) []*SSEEvent {
+	t.Helper()
+	var events []*SSEEvent
+	for {
+		event, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return events
+		}
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+}
+
+func TestSSEReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Basic", func(t *testing.T) {
+		t.Parallel()
+		r := NewSSEReader(strings.NewReader(
+			"event: response.created\ndata: {\"a\":1}\n\ndata: second\n\n",
+		))
+		events := readAllEvents(t, r)
+		require.Len(t, events, 2)
+		require.Equal(t, "response.created", events[0].Event)
+		require.Equal(t, `{"a":1}`, string(events[0].Data))
+		require.Equal(t, "message", events[1].Event)
+		require.Equal(t, "second", string(events[1].Data))
+	})
+
+	t.Run("MultilineDataAndLineEndings", func(t *testing.T) {
+		t.Parallel()
+		r := NewSSEReader(strings.NewReader(
+			"\xEF\xBB\xBFdata: one\r\ndata:two\rdata\n\r\n" + "data: x\r\r",
+		))
+		events := readAllEvents(t, r)
+		require.Len(t, events, 2)
+		require.Equal(t, "one\ntwo\n", string(events[0].Data))
+		require.Equal(t, "x", string(events[1].Data))
+	})
+
+	t.Run("CommentsIDRetry", func(t *testing.T) {
+		t.Parallel()
+		var comments []string
+		r := NewSSEReader(strings.NewReader(
+			": keep-alive\n\nid: 42\nretry: 1500\nretry: bad\nfoo: bar\ndata: d\n\ndata: e\n\n",
+		))
+		r.OnComment = func(c string) { comments = append(comments, c) }
+		events := readAllEvents(t, r)
+		require.Equal(t, []string{"keep-alive"}, comments)
+		require.Len(t, events, 2)
+		require.Equal(t, "42", events[0].ID)
+		require.Equal(t, 1500*time.Millisecond, events[0].Retry)
+		require.Equal(t, "42", events[1].ID)
+		require.Equal(t, "42", r.LastEventID())
+	})
+
+	t.Run("EventWithoutDataIsNotDispatched", func(t *testing.T) {
+		t.Parallel()
+		r := NewSSEReader(strings.NewReader("event: ping\n\ndata: x\n\n"))
+		events := readAllEvents(t, r)
+		require.Len(t, events, 1)
+		require.Equal(t, "message", events[0].Event)
+	})
+
+	t.Run("IncompleteEventDiscarded", func(t *testing.T) {
+		t.Parallel()
+		r := NewSSEReader(strings.NewReader("data: [DONE]\n\ndata: partial\n"))
+		events := readAllEvents(t, r)
+		require.Len(t, events, 1)
+		require.True(t, events[0].IsDone())
+	})
+
+	t.Run("LineLimit", func(t *testing.T) {
+		t.Parallel()
+		r := NewSSEReader(strings.NewReader("data: " + strings.Repeat("x", 200) + "\n\n"))
+		r.MaxLineSize = 100
+		_, err := r.Next()
+		require.Error(t, err)
+	})
+}
+
+func TestCheckStreamResponse(t *testing.T) {
+	t.Parallel()
+
+	ok := &http.Response{StatusCode: http.StatusOK, Status: "200 OK"}
+	require.NoError(t, CheckStreamResponse(ok))
+
+	failed := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Status:     "429 Too Many Requests",
+		Body: io.NopCloser(strings.NewReader(
+			`{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`,
+		)),
+	}
+	err := CheckStreamResponse(failed)
+	require.Error(t, err)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	require.Equal(t, "Rate limit reached", apiErr.Message)
+	require.Equal(t, "rate_limit_exceeded", apiErr.Code)
+	require.Contains(t, err.Error(), "429")
+}
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{"Retry-After": {"2"}}
+	apiErr := NewAPIErrorFromBody(&http.Response{StatusCode: http.StatusTooManyRequests, Header: header}, nil)
+	require.Equal(t, 2*time.Second, apiErr.RetryAfter)
+	header.Set("retry-after-ms", "250")
+	apiErr = NewAPIErrorFromBody(&http.Response{StatusCode: http.StatusTooManyRequests, Header: header}, nil)
+	require.Equal(t, 250*time.Millisecond, apiErr.RetryAfter)
+	require.True(t, IsRetryable(fmt.Errorf("wrapped: %w", apiErr)))
+
+	require.True(t, IsRetryable(&APIError{StatusCode: http.StatusBadGateway}))
+	require.True(t, IsRetryable(&url.Error{Op: "Post", URL: "http://example", Err: io.ErrUnexpectedEOF}))
+	require.False(t, IsRetryable(&APIError{StatusCode: http.StatusBadRequest}))
+	require.False(t, IsRetryable(&APIError{StatusCode: http.StatusTooManyRequests, Code: "insufficient_quota"}))
+	require.False(t, IsRetryable(&url.Error{Op: "Post", URL: "http://example", Err: context.Canceled}))
+	require.False(t, IsRetryable(errors.New("failed to decode response")))
+}
+
+func TestStreamSSE(t *testing.T) {
+	t.Parallel()
+
+	decode := func(event *SSEEvent) (any, error) {
+		switch string(event.Data) {
+		case "skip":
+			return nil, nil
+		case "bad":
+			return nil, errors.New("bad event")
+		}
+		return string(event.Data), nil
+	}
+
+	// receiveAll reads items until the stream is over
+	receiveAll := func(ctx context.Context, items <-chan any) ([]any, error) {
+		var all []any
+		for {
+			item, ok, err := ReceiveItem(ctx, items)
+			if !ok {
+				return all, err
+			}
+			all = append(all, item)
+		}
+	}
+
+	t.Run("Items", func(t *testing.T) {
+		t.Parallel()
+		var done bool
+		items := StreamSSE(context.Background(), io.NopCloser(strings.NewReader(
+			"data: a\n\ndata: skip\n\ndata: b\n\ndata: [DONE]\n\ndata: c\n\n",
+		)), decode, func() { done = true })
+
+		all, err := receiveAll(context.Background(), items)
+		require.NoError(t, err)
+		require.Equal(t, []any{"a", "b"}, all)
+		require.True(t, done)
+	})
+
+	t.Run("DecodeError", func(t *testing.T) {
+		t.Parallel()
+		items := StreamSSE(context.Background(), io.NopCloser(strings.NewReader(
+			"data: a\n\ndata: bad\n\ndata: b\n\n",
+		)), decode, nil)
+
+		all, err := receiveAll(context.Background(), items)
+		require.EqualError(t, err, "bad event")
+		require.Equal(t, []any{"a"}, all)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		t.Parallel()
+		// the producer is stopped by the context without delivering the error,
+		// a truncated stream must never look complete
+		for range 100 {
+			ctx, cancel := context.WithCancel(context.Background())
+			pr, pw := io.Pipe()
+			items := StreamSSE(ctx, pr, decode, nil)
+			go func() {
+				_, _ = io.WriteString(pw, "data: a\n\n")
+				cancel()
+				pw.CloseWithError(errors.New("connection reset"))
+			}()
+
+			_, err := receiveAll(ctx, items)
+			require.ErrorIs(t, err, context.Canceled)
+		}
+	})
+}
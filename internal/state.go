@@ -0,0 +1,61 @@
+// Package openai / internal / state.go contains a helper for observable connection states.
+package openai
+
+import "sync"
+
+// StateNotifier keeps the current state and delivers its changes to a channel.
+// If the channel is not read in time, the undelivered state is replaced with the newer one,
+// so readers never block the owner and always see the latest state.
+type StateNotifier[T comparable] struct {
+	mu     sync.Mutex
+	state  T
+	states chan T
+	final  bool
+}
+
+// NewStateNotifier creates a StateNotifier with given initial state.
+// The initial state is delivered to the channel as well.
+func NewStateNotifier[T comparable](initial T) *StateNotifier[T] {
+	n := &StateNotifier[T]{
+		state:  initial,
+		states: make(chan T, 1),
+	}
+	n.states <- initial
+	return n
+}
+
+// Set changes the state. A final state closes the channel after being delivered,
+// subsequent calls are ignored. Setting the current state again does nothing.
+func (n *StateNotifier[T]) Set(state T, final bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.final || state == n.state {
+		return
+	}
+	n.state = state
+	n.final = final
+
+	// only the notifier sends to the channel, so after draining there is room for the new state
+	select {
+	case <-n.states:
+	default:
+	}
+	n.states <- state
+
+	if final {
+		close(n.states)
+	}
+}
+
+// Get returns the current state.
+func (n *StateNotifier[T]) Get() T {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.state
+}
+
+// Chan returns the channel receiving state changes.
+func (n *StateNotifier[T]) Chan() <-chan T {
+	return n.states
+}
@@ -0,0 +1,105 @@
+// Package openai / internal / websocket.go provides helpers for WebSocket connections to the API.
+package openai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/websocket"
+)
+
+// DialWebSocket opens a WebSocket connection to given API path with optional query parameters.
+// The connection is authorized with the config token and uses WebSocketDialer if it's set.
+func (c *Config) DialWebSocket(
+	ctx context.Context, path string, query url.Values, headers http.Header,
+) (*websocket.Conn, error) {
+	targetURL, err := WebSocketURL(c.BaseAPI, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(query) > 0 {
+		targetURL += "?" + query.Encode()
+	}
+
+	if headers == nil {
+		headers = http.Header{}
+	}
+	headers.Set("Authorization", "Bearer "+c.Token)
+
+	conn, _, err := c.newWebSocketDialer().DialContext(ctx, targetURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect websocket: %w", err)
+	}
+
+	return conn, nil
+}
+
+// newWebSocketDialer returns a copy of WebSocketDialer or a dialer derived from HTTPClient settings.
+func (c *Config) newWebSocketDialer() *websocket.Dialer {
+	if c.WebSocketDialer != nil {
+		dialerCopy := *c.WebSocketDialer
+		return &dialerCopy
+	}
+
+	dialer := websocket.Dialer{
+		Proxy: http.ProxyFromEnvironment,
+	}
+
+	if c.HTTPClient == nil || c.HTTPClient.Client == nil {
+		return &dialer
+	}
+
+	httpClient := c.HTTPClient.Client
+	if httpClient.Timeout > 0 {
+		dialer.HandshakeTimeout = httpClient.Timeout
+	}
+
+	if transport, ok := httpClient.Transport.(*http.Transport); ok && transport != nil {
+		if transport.Proxy != nil {
+			dialer.Proxy = transport.Proxy
+		}
+		dialer.NetDialContext = transport.DialContext
+		dialer.TLSClientConfig = transport.TLSClientConfig
+	}
+
+	return &dialer
+}
+
+// WebSocketURL joins base API URL with given path and switches the scheme to ws or wss.
+func WebSocketURL(base, path string) (string, error) {
+	rawURL, err := url.JoinPath(base, path)
+	if err != nil {
+		return "", fmt.Errorf("failed to build websocket URL: %w", err)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse websocket URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	case "wss", "ws":
+	default:
+		return "", fmt.Errorf("unsupported base API scheme: %s", u.Scheme)
+	}
+
+	return u.String(), nil
+}
+
+// LogWebSocketPayload logs a WebSocket message if request logging is enabled.
+func (c *Config) LogWebSocketPayload(direction string, data []byte) {
+	if c.HTTPClient == nil {
+		return
+	}
+	lt, ok := c.HTTPClient.Transport.(*LoggingTransport)
+	if !ok || !lt.EnableLog {
+		return
+	}
+	c.Log.Debug(fmt.Sprintf("websocket %s:\n%s", direction, string(data)))
+}
@@ -0,0 +1,201 @@
+// Package models / capabilities.go describes features supported by OpenAI models.
+package models
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ErrUnsupported is wrapped by errors of requests that use features unsupported by the model.
+var ErrUnsupported = errors.New("unsupported by model")
+
+// Modality is a type of content that a model can take or produce.
+type Modality string
+
+// Modalities of models.
+const (
+	ModalityText  Modality = "text"
+	ModalityImage Modality = "image"
+	ModalityAudio Modality = "audio"
+)
+
+// Tool types of the Responses API.
+const (
+	ToolFunction        = "function"
+	ToolCustom          = "custom"
+	ToolWebSearch       = "web_search"
+	ToolFileSearch      = "file_search"
+	ToolCodeInterpreter = "code_interpreter"
+	ToolComputerUse     = "computer_use_preview"
+	ToolImageGeneration = "image_generation"
+	ToolMCP             = "mcp"
+	ToolLocalShell      = "local_shell"
+	ToolShell           = "shell"
+	ToolApplyPatch      = "apply_patch"
+)
+
+// Capabilities describes features supported by a model.
+type Capabilities struct {
+	Input  []Modality // modalities accepted as input
+	Output []Modality // modalities produced as output
+
+	// Reasoning models accept reasoning configuration.
+	Reasoning bool
+	// Sampling models accept temperature, top_p and penalties.
+	// Reasoning models with sampling accept them only with reasoning effort "none".
+	Sampling bool
+	// FunctionCalling models accept function and custom tools.
+	FunctionCalling bool
+	// StructuredOutputs models accept JSON schemas as the output format.
+	StructuredOutputs bool
+	// Tools lists built-in tool types of the Responses API supported by the model.
+	Tools []string
+}
+
+// SupportsInput reports whether the model accepts the modality as input.
+func (c Capabilities) SupportsInput(m Modality) bool {
+	return slices.Contains(c.Input, m)
+}
+
+// SupportsOutput reports whether the model can produce the modality.
+func (c Capabilities) SupportsOutput(m Modality) bool {
+	return slices.Contains(c.Output, m)
+}
+
+// SupportsTool reports whether the model supports the tool type.
+// Function and custom tools are supported by models with function calling.
+func (c Capabilities) SupportsTool(toolType string) bool {
+	switch toolType {
+	case ToolFunction, ToolCustom:
+		return c.FunctionCalling
+	case "web_search_preview":
+		toolType = ToolWebSearch
+	}
+	return slices.Contains(c.Tools, toolType)
+}
+
+// CapabilityOverrides sets capabilities of models by exact name. Overrides take precedence
+// over capabilities of model families and can describe new or fine-tuned models.
+var CapabilityOverrides = map[string]Capabilities{
+	GPT4o20240513: {
+		Input:           []Modality{ModalityText, ModalityImage},
+		Output:          []Modality{ModalityText},
+		Sampling:        true,
+		FunctionCalling: true,
+		Tools:           generalTools,
+	},
+}
+
+// GetCapabilities returns capabilities of the model.
+// Fine-tuned models ("ft:<base>:...") have capabilities of their base model.
+// Returns false if the model is unknown.
+func GetCapabilities(model string) (Capabilities, bool) {
+	if c, ok := CapabilityOverrides[model]; ok {
+		return c, true
+	}
+
+	if base, ok := strings.CutPrefix(model, "ft:"); ok {
+		base, _, _ = strings.Cut(base, ":")
+		return GetCapabilities(base)
+	}
+
+	for _, f := range families {
+		if f.match(model) {
+			return f.caps, true
+		}
+	}
+	return Capabilities{}, false
+}
+
+var (
+	textIO      = []Modality{ModalityText}
+	textImageIn = []Modality{ModalityText, ModalityImage}
+	audioIO     = []Modality{ModalityText, ModalityAudio}
+
+	generalTools = []string{ToolWebSearch, ToolFileSearch, ToolCodeInterpreter, ToolImageGeneration, ToolMCP}
+	agenticTools = append(slices.Clone(generalTools), ToolShell, ToolApplyPatch, ToolLocalShell)
+)
+
+// prefix matches models starting with any of the prefixes.
+func prefix(prefixes ...string) func(string) bool {
+	return func(model string) bool {
+		for _, p := range prefixes {
+			if strings.HasPrefix(model, p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// contains matches models containing any of the substrings.
+func contains(parts ...string) func(string) bool {
+	return func(model string) bool {
+		for _, p := range parts {
+			if strings.Contains(model, p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// families lists capabilities of model families, the first match is used.
+var families = []struct {
+	match func(model string) bool
+	caps  Capabilities
+}{
+	{contains("embedding"), Capabilities{Input: textIO}},
+	{contains("moderation"), Capabilities{Input: textImageIn}},
+	{contains("-transcribe"), Capabilities{Input: []Modality{ModalityAudio}, Output: textIO, Sampling: true}},
+	{contains("-tts"), Capabilities{Input: textIO, Output: []Modality{ModalityAudio}}},
+	{contains("realtime"), Capabilities{Input: []Modality{ModalityText, ModalityAudio, ModalityImage}, Output: audioIO, Sampling: true, FunctionCalling: true}},
+	{contains("-audio", "gpt-audio"), Capabilities{Input: audioIO, Output: audioIO, Sampling: true, FunctionCalling: true}},
+	{contains("search-preview", "search-api"), Capabilities{Input: textImageIn, Output: textIO, StructuredOutputs: true}},
+	{contains("deep-research"), Capabilities{
+		Input: textImageIn, Output: textIO, Reasoning: true,
+		Tools: []string{ToolWebSearch, ToolFileSearch, ToolCodeInterpreter, ToolMCP},
+	}},
+	{func(m string) bool { return strings.HasPrefix(m, "gpt-5") && strings.HasSuffix(m, "-chat-latest") }, Capabilities{
+		Input: textImageIn, Output: textIO, Sampling: true, FunctionCalling: true, StructuredOutputs: true, Tools: generalTools,
+	}},
+	{func(m string) bool { return strings.HasPrefix(m, "gpt-5") && strings.Contains(m, "codex") }, Capabilities{
+		Input: textImageIn, Output: textIO, Reasoning: true, FunctionCalling: true, StructuredOutputs: true, Tools: agenticTools,
+	}},
+	{func(m string) bool { return strings.HasPrefix(m, "gpt-5") && strings.Contains(m, "-pro") }, Capabilities{
+		Input: textImageIn, Output: textIO, Reasoning: true, FunctionCalling: true, StructuredOutputs: true,
+		Tools: []string{ToolWebSearch, ToolFileSearch, ToolImageGeneration, ToolMCP},
+	}},
+	// GPT-5.1 and newer accept sampling parameters with reasoning effort "none"
+	{prefix("gpt-5."), Capabilities{
+		Input: textImageIn, Output: textIO, Reasoning: true, Sampling: true, FunctionCalling: true, StructuredOutputs: true, Tools: agenticTools,
+	}},
+	{prefix("gpt-5"), Capabilities{
+		Input: textImageIn, Output: textIO, Reasoning: true, FunctionCalling: true, StructuredOutputs: true, Tools: generalTools,
+	}},
+	{prefix("o1-mini"), Capabilities{Input: textIO, Output: textIO, Reasoning: true}},
+	{prefix("o1"), Capabilities{
+		Input: textImageIn, Output: textIO, Reasoning: true, FunctionCalling: true, StructuredOutputs: true, Tools: []string{ToolFileSearch, ToolMCP},
+	}},
+	{prefix("o3-mini"), Capabilities{
+		Input: textIO, Output: textIO, Reasoning: true, FunctionCalling: true, StructuredOutputs: true,
+		Tools: []string{ToolFileSearch, ToolCodeInterpreter, ToolMCP},
+	}},
+	{prefix("o3", "o4-mini", "codex-mini"), Capabilities{
+		Input: textImageIn, Output: textIO, Reasoning: true, FunctionCalling: true, StructuredOutputs: true, Tools: generalTools,
+	}},
+	{prefix("computer-use"), Capabilities{
+		Input: textImageIn, Output: textIO, Reasoning: true, FunctionCalling: true, Tools: []string{ToolComputerUse},
+	}},
+	{prefix("gpt-4.1", "gpt-4o", "chatgpt-4o"), Capabilities{
+		Input: textImageIn, Output: textIO, Sampling: true, FunctionCalling: true, StructuredOutputs: true, Tools: generalTools,
+	}},
+	{prefix("gpt-oss"), Capabilities{
+		Input: textIO, Output: textIO, Reasoning: true, Sampling: true, FunctionCalling: true, StructuredOutputs: true,
+	}},
+	{prefix("gpt-4-turbo"), Capabilities{Input: textImageIn, Output: textIO, Sampling: true, FunctionCalling: true}},
+	{prefix("gpt-3.5-turbo-instruct"), Capabilities{Input: textIO, Output: textIO, Sampling: true}},
+	{prefix("gpt-4", "gpt-3.5"), Capabilities{Input: textIO, Output: textIO, Sampling: true, FunctionCalling: true}},
+	{prefix("davinci", "babbage", "text-"), Capabilities{Input: textIO, Output: textIO, Sampling: true}},
+}
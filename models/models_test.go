@@ -9,9 +9,9 @@ import (
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
-	. "github.com/unkn0wncode/openai/internal"
 )
 
 var testToken string
@@ -45,7 +45,8 @@ type modelData struct {
 func TestModelsList(t *testing.T) {
 	// fetch list of models from API
 
-	client := NewHTTPClient()
+	// plain client, as the internal package depends on this one
+	client := &http.Client{Timeout: 30 * time.Second}
 	req, err := http.NewRequest(http.MethodGet, "https://api.openai.com/v1/models", nil)
 	require.NoError(t, err)
 	req.Header.Add("Authorization", "Bearer "+testToken)
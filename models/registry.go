@@ -0,0 +1,186 @@
+// Package models / registry.go resolves model names of requests at runtime.
+package models
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Registry resolves model names used in requests at runtime.
+// It maps aliases, like "fast" or "smart", to models, keeps fallback chains for models
+// that fail to respond, and knows which models are deprecated or available.
+// It's safe for concurrent use.
+type Registry struct {
+	mu sync.RWMutex
+
+	// model used by requests without a model
+	defaultModel string
+	// custom names mapped to models or other aliases
+	aliases map[string]string
+	// models tried in order when the model is not found, overloaded or fails with a server error
+	fallbacks map[string][]string
+	// models that still work but are retired or scheduled for retirement
+	deprecated map[string]bool
+	// models listed by the API, nil until refresh
+	available map[string]bool
+}
+
+// NewRegistry creates a registry with the package Default model and Deprecated models.
+func NewRegistry() *Registry {
+	return &Registry{
+		defaultModel: Default,
+		aliases:      make(map[string]string),
+		fallbacks:    make(map[string][]string),
+		deprecated:   maps.Clone(Deprecated),
+	}
+}
+
+// SetDefault sets the model used by requests without a model, which can be an alias.
+func (r *Registry) SetDefault(model string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultModel = model
+}
+
+// SetAlias makes the alias resolve to the model, which can be another alias.
+func (r *Registry) SetAlias(alias, model string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.aliases == nil {
+		r.aliases = make(map[string]string)
+	}
+	r.aliases[alias] = model
+}
+
+// SetFallbacks sets models to try in order when the model fails to respond.
+// Fallbacks can be aliases. No fallbacks removes the chain.
+// The model can be an alias too, it's resolved when the chain is set,
+// so the chain stays with the current target model if the alias is changed later.
+func (r *Registry) SetFallbacks(model string, fallbacks ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	model = r.resolve(model)
+	if len(fallbacks) == 0 {
+		delete(r.fallbacks, model)
+		return
+	}
+	if r.fallbacks == nil {
+		r.fallbacks = make(map[string][]string)
+	}
+	r.fallbacks[model] = fallbacks
+}
+
+// SetAvailable replaces the set of available models, e.g. with models listed by the API.
+func (r *Registry) SetAvailable(ids []string) {
+	available := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		available[id] = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.available = available
+}
+
+// IsAvailable reports whether the model was listed by the API.
+// All models are considered available until SetAvailable is called.
+func (r *Registry) IsAvailable(model string) bool {
+	if r == nil {
+		return true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.available == nil || r.available[model]
+}
+
+// Resolve returns the model for the name: the default model for empty name,
+// the target model for aliases, or the name itself.
+// A nil registry resolves only the empty name, to the package Default.
+func (r *Registry) Resolve(name string) string {
+	if r == nil {
+		if name == "" {
+			return Default
+		}
+		return name
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.resolve(name)
+}
+
+// resolve is Resolve without locking.
+func (r *Registry) resolve(name string) string {
+	if name == "" {
+		name = r.defaultModel
+		if name == "" {
+			name = Default
+		}
+	}
+
+	// the number of steps is limited to stop on alias cycles
+	for range len(r.aliases) {
+		model, ok := r.aliases[name]
+		if !ok {
+			break
+		}
+		name = model
+	}
+	return name
+}
+
+// Chain returns the resolved model for the name followed by its resolved fallbacks,
+// without duplicates and fallbacks that aren't available.
+func (r *Registry) Chain(name string) []string {
+	model := r.Resolve(name)
+	if r == nil {
+		return []string{model}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chain := []string{model}
+	for _, fallback := range r.fallbacks[model] {
+		fallback = r.resolve(fallback)
+		if slices.Contains(chain, fallback) {
+			continue
+		}
+		if r.available != nil && !r.available[fallback] {
+			continue
+		}
+		chain = append(chain, fallback)
+	}
+	return chain
+}
+
+// IsDeprecated reports whether the model is deprecated.
+func (r *Registry) IsDeprecated(model string) bool {
+	if r == nil {
+		return Deprecated[model]
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.deprecated[model]
+}
+
+// SetDeprecated marks the model as deprecated or not. Requests to deprecated models
+// are logged with a warning.
+func (r *Registry) SetDeprecated(model string, deprecated bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !deprecated {
+		delete(r.deprecated, model)
+		return
+	}
+	if r.deprecated == nil {
+		r.deprecated = make(map[string]bool)
+	}
+	r.deprecated[model] = true
+}
@@ -0,0 +1,103 @@
+// Package models / service.go contains the service layer for OpenAI Models API.
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Service is the service layer for OpenAI Models API.
+type Service interface {
+	// List returns models available to the token, including fine-tuned models of the organization.
+	List() ([]Model, error)
+
+	// Retrieve returns a model by ID.
+	Retrieve(id string) (*Model, error)
+
+	// Delete deletes a fine-tuned model. Requires the Owner role in the organization.
+	Delete(id string) error
+
+	// DiffData lists available models and compares them with data of this package, see Diff.
+	// Differences are logged as warnings.
+	DiffData() (*DataDiff, error)
+}
+
+// Model is a model object returned by the API.
+type Model struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`   // always "model"
+	Created int64  `json:"created"`  // Unix timestamp
+	OwnedBy string `json:"owned_by"` // "system", "openai" or "openai-internal" for OpenAI-owned models
+}
+
+// CreatedAt returns the creation time of the model.
+func (m Model) CreatedAt() time.Time {
+	return time.Unix(m.Created, 0)
+}
+
+// IsFineTuned reports whether the model is a fine-tuned model, identified by the "ft:" prefix of its ID.
+func (m Model) IsFineTuned() bool {
+	return strings.HasPrefix(m.ID, "ft:")
+}
+
+// DataDiff lists differences between models available in the API and data of this package.
+type DataDiff struct {
+	// Unknown are models listed by the API but missing from the package data.
+	// They can be used but have no pricing, limits or constants.
+	Unknown []string
+	// Retired are models in the package data but not listed by the API.
+	// Requests to them are likely to fail.
+	Retired []string
+}
+
+// Empty reports whether there are no differences.
+func (d *DataDiff) Empty() bool {
+	return len(d.Unknown) == 0 && len(d.Retired) == 0
+}
+
+// Diff compares models listed by the API with models known to this package:
+// Data, DataEmbedding, DataTTS, PricePerImageData and VideoData.
+// Fine-tuned models are skipped. Results are sorted.
+func Diff(live []Model) *DataDiff {
+	listed := make(map[string]bool, len(live))
+	for _, m := range live {
+		if !m.IsFineTuned() {
+			listed[m.ID] = true
+		}
+	}
+
+	known := make(map[string]bool, len(Data))
+	for model := range Data {
+		known[model] = true
+	}
+	for model := range DataEmbedding {
+		known[model] = true
+	}
+	for model := range DataTTS {
+		known[model] = true
+	}
+	for model := range PricePerImageData {
+		known[model] = true
+	}
+	for model := range VideoData {
+		known[model] = true
+	}
+	// default values placeholder
+	delete(known, "")
+
+	diff := &DataDiff{}
+	for model := range listed {
+		if !known[model] {
+			diff.Unknown = append(diff.Unknown, model)
+		}
+	}
+	for model := range known {
+		if !listed[model] {
+			diff.Retired = append(diff.Retired, model)
+		}
+	}
+	slices.Sort(diff.Unknown)
+	slices.Sort(diff.Retired)
+	return diff
+}
@@ -177,7 +177,6 @@ var Data = map[string]struct {
 	GPT4Turbo:              {0.00001000, 0.00000000, 0.00003000, 128000, 4096},
 	GPT4Turbo20240409:      {0.00001000, 0.00001000, 0.00003000, 128000, 4096},
 	"gpt-4-0613":           {0.00003000, 0.00003000, 0.00006000, 8192, 8192},
-	"gpt-4-1106-preview":   {0.00001000, 0.00000000, 0.00003000, 128000, 4096},
 	GPT4Quasar:             {0.00000200, 0.00000050, 0.00000800, 1047576, 32768},
 	GPT4Quasar20250414:     {0.00000200, 0.00000050, 0.00000800, 1000000, 32768},
 	GPT4QuasarMini:         {0.00000040, 0.00000010, 0.00000160, 1047576, 32768},
@@ -294,4 +293,12 @@ var Data = map[string]struct {
 	// Embedding models
 	ThreeLarge: {0.00000013, 0.00000000, 0.00000000, 8191, 3072},
 	ThreeSmall: {0.00000002, 0.00000000, 0.00000000, 8191, 1536},
+
+	// Deprecated or unused models
+	"gpt-4-1106-preview": {0.00001000, 0.00000000, 0.00003000, 128000, 4096},
+}
+
+// Deprecated lists models from the "Deprecated or unused models" section of Data.
+var Deprecated = map[string]bool{
+	"gpt-4-1106-preview": true,
 }
@@ -0,0 +1,9 @@
+// Package models / transcription.go contains list of OpenAI speech-to-text models.
+// Newer transcription models are listed in text.go along with their token pricing.
+package models
+
+const (
+	DefaultTranscription = GPT4oMiniTranscribe
+	DefaultTranslation   = Whisper1
+	Whisper1             = "whisper-1"
+)
@@ -1,7 +1,14 @@
 // Package models / tts.go contains list and properties of OpenAI text-to-speech models.
 package models
 
+import (
+	"slices"
+	"strings"
+)
+
 const (
+	DefaultTTS           = GPT4oMiniTTS
+	TTS1                 = "tts-1"
 	TTS11106             = "tts-1-1106"
 	TTS1HD               = "tts-1-hd"
 	TTS1HD1106           = "tts-1-hd-1106"
@@ -18,6 +25,11 @@ type TTSPricing struct {
 
 // DataTTS lists pricing information for text-to-speech models.
 var DataTTS = map[string]TTSPricing{
+	TTS1: {
+		PricePerCharacter:  0.00001500,
+		ApproxUSDPerMinute: 0.01500,
+		LimitCharacters:    16384,
+	},
 	TTS11106: {
 		PricePerCharacter:  0.00001500,
 		ApproxUSDPerMinute: 0.01500,
@@ -44,3 +56,22 @@ var DataTTS = map[string]TTSPricing{
 		LimitCharacters:    16384,
 	},
 }
+
+// GetTTSPricing returns pricing of a text-to-speech model. Models without dated suffix,
+// like gpt-4o-mini-tts, get pricing of their latest dated snapshot.
+func GetTTSPricing(model string) (TTSPricing, bool) {
+	if pricing, ok := DataTTS[model]; ok {
+		return pricing, true
+	}
+
+	var snapshots []string
+	for name := range DataTTS {
+		if strings.HasPrefix(name, model+"-") {
+			snapshots = append(snapshots, name)
+		}
+	}
+	if len(snapshots) == 0 {
+		return TTSPricing{}, false
+	}
+	return DataTTS[slices.Max(snapshots)], true
+}
@@ -0,0 +1,156 @@
+package realtime
+
+import (
+	"encoding/binary"
+)
+
+// EncodePCM16 encodes samples as 16-bit little-endian PCM, the format of FormatPCM16.
+func EncodePCM16(samples []int16) []byte {
+	data := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(data[i*2:], uint16(s))
+	}
+	return data
+}
+
+// DecodePCM16 decodes 16-bit little-endian PCM into samples. A trailing odd byte is ignored.
+func DecodePCM16(data []byte) []int16 {
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[i*2:]))
+	}
+	return samples
+}
+
+// Resample converts samples from one sample rate to another with linear interpolation,
+// e.g. between 8kHz of G.711 and 24kHz of PCM16 sessions.
+func Resample(samples []int16, fromRate, toRate int) []int16 {
+	if fromRate == toRate || len(samples) == 0 || fromRate <= 0 || toRate <= 0 {
+		return samples
+	}
+
+	n := int(int64(len(samples)) * int64(toRate) / int64(fromRate))
+	out := make([]int16, n)
+	for i := range out {
+		// position i*fromRate/toRate in source samples, as integer part and remainder
+		pos := int64(i) * int64(fromRate)
+		j, rem := int(pos/int64(toRate)), pos%int64(toRate)
+		if j >= len(samples)-1 {
+			out[i] = samples[len(samples)-1]
+			continue
+		}
+		diff := int64(samples[j+1]) - int64(samples[j])
+		out[i] = int16(int64(samples[j]) + diff*rem/int64(toRate))
+	}
+	return out
+}
+
+const (
+	muLawBias = 0x84
+	muLawClip = 32635
+)
+
+// EncodeMuLaw encodes samples with G.711 mu-law, the format of FormatG711ULaw.
+// Samples must be at 8kHz, see Resample.
+func EncodeMuLaw(samples []int16) []byte {
+	data := make([]byte, len(samples))
+	for i, s := range samples {
+		data[i] = muLawEncodeSample(s)
+	}
+	return data
+}
+
+// DecodeMuLaw decodes G.711 mu-law data into samples.
+func DecodeMuLaw(data []byte) []int16 {
+	samples := make([]int16, len(data))
+	for i, b := range data {
+		samples[i] = muLawDecodeSample(b)
+	}
+	return samples
+}
+
+func muLawEncodeSample(s int16) byte {
+	sample := int(s)
+	sign := 0
+	if sample < 0 {
+		sign = 0x80
+		sample = -sample
+	}
+	sample = min(sample, muLawClip) + muLawBias
+
+	exponent := 7
+	for mask := 0x4000; sample&mask == 0 && exponent > 0; mask >>= 1 {
+		exponent--
+	}
+	mantissa := (sample >> (exponent + 3)) & 0x0F
+	return ^byte(sign | exponent<<4 | mantissa)
+}
+
+func muLawDecodeSample(b byte) int16 {
+	b = ^b
+	exponent := int(b>>4) & 0x07
+	mantissa := int(b & 0x0F)
+	sample := ((mantissa << 3) + muLawBias) << exponent
+	sample -= muLawBias
+	if b&0x80 != 0 {
+		return int16(-sample)
+	}
+	return int16(sample)
+}
+
+// EncodeALaw encodes samples with G.711 A-law, the format of FormatG711ALaw.
+// Samples must be at 8kHz, see Resample.
+func EncodeALaw(samples []int16) []byte {
+	data := make([]byte, len(samples))
+	for i, s := range samples {
+		data[i] = aLawEncodeSample(s)
+	}
+	return data
+}
+
+// DecodeALaw decodes G.711 A-law data into samples.
+func DecodeALaw(data []byte) []int16 {
+	samples := make([]int16, len(data))
+	for i, b := range data {
+		samples[i] = aLawDecodeSample(b)
+	}
+	return samples
+}
+
+func aLawEncodeSample(s int16) byte {
+	sample := int(s) >> 3 // A-law works with 13-bit samples
+	sign := 0x80
+	if sample < 0 {
+		sign = 0
+		sample = -sample - 1
+	}
+
+	var encoded int
+	if sample < 32 {
+		encoded = sample >> 1
+	} else {
+		exponent := 1
+		for sample >= 64<<(exponent-1) && exponent < 7 {
+			exponent++
+		}
+		encoded = exponent<<4 | (sample>>exponent)&0x0F
+	}
+	return byte(encoded|sign) ^ 0x55
+}
+
+func aLawDecodeSample(b byte) int16 {
+	b ^= 0x55
+	exponent := int(b>>4) & 0x07
+	mantissa := int(b & 0x0F)
+
+	var sample int
+	if exponent == 0 {
+		sample = mantissa<<4 + 8
+	} else {
+		sample = (mantissa<<4 + 0x108) << (exponent - 1)
+	}
+	if b&0x80 == 0 {
+		return int16(-sample)
+	}
+	return int16(sample)
+}
@@ -0,0 +1,60 @@
+package realtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudio(t *testing.T) {
+	t.Parallel()
+
+	samples := []int16{0, 1, -1, 100, -100, 1000, -1000, 12345, -12345, 32767, -32768}
+
+	t.Run("PCM16", func(t *testing.T) {
+		t.Parallel()
+		data := EncodePCM16(samples)
+		require.Len(t, data, len(samples)*2)
+		require.Equal(t, []byte{0xFF, 0x7F}, data[len(data)-4:len(data)-2])
+		require.Equal(t, samples, DecodePCM16(data))
+	})
+
+	t.Run("G711", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, byte(0xFF), EncodeMuLaw([]int16{0})[0])
+		require.Equal(t, byte(0xD5), EncodeALaw([]int16{0})[0])
+
+		for name, codec := range map[string]struct {
+			encode func([]int16) []byte
+			decode func([]byte) []int16
+		}{
+			"MuLaw": {EncodeMuLaw, DecodeMuLaw},
+			"ALaw":  {EncodeALaw, DecodeALaw},
+		} {
+			decoded := codec.decode(codec.encode(samples))
+			require.Len(t, decoded, len(samples), name)
+			for i, s := range samples {
+				// companding keeps about 4 bits of mantissa, so the error is relative
+				tolerance := max(abs(int(s))/16, 16)
+				require.InDelta(t, s, decoded[i], float64(tolerance), "%s: sample %d", name, s)
+			}
+
+			// decoded values are exact points of the scale, so they survive a second pass
+			require.Equal(t, decoded, codec.decode(codec.encode(decoded)), name)
+		}
+	})
+
+	t.Run("Resample", func(t *testing.T) {
+		t.Parallel()
+		up := Resample([]int16{0, 300, 600}, 8000, 24000)
+		require.Equal(t, []int16{0, 100, 200, 300, 400, 500, 600, 600, 600}, up)
+		require.Equal(t, []int16{0, 300, 600}, Resample(up, 24000, 8000)[:3])
+	})
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
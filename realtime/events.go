@@ -0,0 +1,348 @@
+package realtime
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Unmarshal unmarshals a server event into a type specified in its "type" field.
+// Unknown event types are returned as UnknownEvent.
+func Unmarshal(data []byte) (any, error) {
+	var base BaseEvent
+	if err := json.Unmarshal(data, &base); err != nil {
+		return nil, err
+	}
+
+	switch base.Type {
+	case "error":
+		return unmarshalToType[Error](data)
+	case "session.created":
+		return unmarshalToType[SessionCreated](data)
+	case "session.updated":
+		return unmarshalToType[SessionUpdated](data)
+	case "conversation.item.added", "conversation.item.created":
+		return unmarshalToType[ConversationItemAdded](data)
+	case "conversation.item.done":
+		return unmarshalToType[ConversationItemDone](data)
+	case "conversation.item.deleted":
+		return unmarshalToType[ConversationItemDeleted](data)
+	case "conversation.item.input_audio_transcription.delta":
+		return unmarshalToType[InputAudioTranscriptionDelta](data)
+	case "conversation.item.input_audio_transcription.completed":
+		return unmarshalToType[InputAudioTranscriptionCompleted](data)
+	case "conversation.item.input_audio_transcription.failed":
+		return unmarshalToType[InputAudioTranscriptionFailed](data)
+	case "input_audio_buffer.committed":
+		return unmarshalToType[InputAudioBufferCommitted](data)
+	case "input_audio_buffer.cleared":
+		return unmarshalToType[InputAudioBufferCleared](data)
+	case "input_audio_buffer.speech_started":
+		return unmarshalToType[InputAudioBufferSpeechStarted](data)
+	case "input_audio_buffer.speech_stopped":
+		return unmarshalToType[InputAudioBufferSpeechStopped](data)
+	case "response.created":
+		return unmarshalToType[ResponseCreated](data)
+	case "response.done":
+		return unmarshalToType[ResponseDone](data)
+	case "response.output_item.added":
+		return unmarshalToType[ResponseOutputItemAdded](data)
+	case "response.output_item.done":
+		return unmarshalToType[ResponseOutputItemDone](data)
+	case "response.output_text.delta", "response.text.delta":
+		return unmarshalToType[ResponseOutputTextDelta](data)
+	case "response.output_text.done", "response.text.done":
+		return unmarshalToType[ResponseOutputTextDone](data)
+	case "response.output_audio.delta", "response.audio.delta":
+		return unmarshalToType[ResponseOutputAudioDelta](data)
+	case "response.output_audio.done", "response.audio.done":
+		return unmarshalToType[ResponseOutputAudioDone](data)
+	case "response.output_audio_transcript.delta", "response.audio_transcript.delta":
+		return unmarshalToType[ResponseOutputAudioTranscriptDelta](data)
+	case "response.output_audio_transcript.done", "response.audio_transcript.done":
+		return unmarshalToType[ResponseOutputAudioTranscriptDone](data)
+	case "response.function_call_arguments.delta":
+		return unmarshalToType[ResponseFunctionCallArgumentsDelta](data)
+	case "response.function_call_arguments.done":
+		return unmarshalToType[ResponseFunctionCallArgumentsDone](data)
+	case "rate_limits.updated":
+		return unmarshalToType[RateLimitsUpdated](data)
+	default:
+		return UnknownEvent{BaseEvent: base, Raw: json.RawMessage(data)}, nil
+	}
+}
+
+// unmarshalToType is a generic function that unmarshals event data into a given type.
+func unmarshalToType[T any](data []byte) (T, error) {
+	var t T
+	if err := json.Unmarshal(data, &t); err != nil {
+		return t, fmt.Errorf("failed to unmarshal realtime event: %w", err)
+	}
+	return t, nil
+}
+
+// types containing repeating fields for embedding
+type (
+	// BaseEvent contains the common fields for all server events.
+	BaseEvent struct {
+		Type    string `json:"type"`
+		EventID string `json:"event_id"`
+	}
+
+	// ItemReference contains the common fields for events referencing a conversation item.
+	ItemReference struct {
+		BaseEvent
+		ItemID string `json:"item_id"`
+	}
+
+	// ContentReference contains the common fields for events referencing a part of response output.
+	ContentReference struct {
+		BaseEvent
+		ResponseID   string `json:"response_id"`
+		ItemID       string `json:"item_id"`
+		OutputIndex  int    `json:"output_index"`
+		ContentIndex int    `json:"content_index"`
+	}
+
+	// SessionEvent contains the common fields for events with session payload.
+	SessionEvent struct {
+		BaseEvent
+		Session SessionInfo `json:"session"`
+	}
+
+	// ResponseEvent contains the common fields for events with response payload.
+	ResponseEvent struct {
+		BaseEvent
+		Response Response `json:"response"`
+	}
+
+	// OutputItemEvent contains the common fields for events with response output item payload.
+	OutputItemEvent struct {
+		BaseEvent
+		ResponseID  string `json:"response_id"`
+		OutputIndex int    `json:"output_index"`
+		Item        Item   `json:"item"`
+	}
+)
+
+// SessionInfo is a session object returned by the server.
+type SessionInfo struct {
+	ID               string          `json:"id"`
+	Object           string          `json:"object"` // "realtime.session"
+	Type             string          `json:"type"`   // "realtime"
+	Model            string          `json:"model"`
+	Instructions     string          `json:"instructions"`
+	OutputModalities []string        `json:"output_modalities"`
+	Audio            *AudioConfig    `json:"audio"`
+	Tools            json.RawMessage `json:"tools"`
+	ToolChoice       json.RawMessage `json:"tool_choice"`
+	MaxOutputTokens  json.RawMessage `json:"max_output_tokens"`
+	ExpiresAt        int64           `json:"expires_at"` // Unix timestamp (seconds)
+}
+
+// Response is a response object returned by the server.
+type Response struct {
+	ID             string `json:"id"`
+	Object         string `json:"object"` // "realtime.response"
+	ConversationID string `json:"conversation_id"`
+	// "in_progress", "completed", "cancelled", "failed" or "incomplete"
+	Status        string `json:"status"`
+	StatusDetails *struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+		Error  *struct {
+			Type string `json:"type"`
+			Code string `json:"code"`
+		} `json:"error"`
+	} `json:"status_details"`
+	Output   []Item            `json:"output"`
+	Metadata map[string]string `json:"metadata"`
+	Usage    *Usage            `json:"usage"`
+}
+
+// Usage is the token usage of a response.
+type Usage struct {
+	TotalTokens        int           `json:"total_tokens"`
+	InputTokens        int           `json:"input_tokens"`
+	OutputTokens       int           `json:"output_tokens"`
+	InputTokenDetails  *TokenDetails `json:"input_token_details"`
+	OutputTokenDetails *TokenDetails `json:"output_token_details"`
+}
+
+// TokenDetails is a breakdown of tokens by modality.
+type TokenDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+	TextTokens   int `json:"text_tokens"`
+	AudioTokens  int `json:"audio_tokens"`
+}
+
+// server event types
+type (
+	// Error is sent when an error occurs, which can be a client or a server problem.
+	// Most errors are recoverable and the session stays open.
+	Error struct {
+		BaseEvent
+		Error struct {
+			Type    string `json:"type"`
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Param   string `json:"param"`
+			// ID of the client event that caused the error, if any
+			EventID string `json:"event_id"`
+		} `json:"error"`
+	}
+
+	// SessionCreated is the first event of a session, sent after connecting.
+	SessionCreated SessionEvent
+
+	// SessionUpdated is sent after a session.update event is applied.
+	SessionUpdated SessionEvent
+
+	// ConversationItemAdded is sent when an item is added to the conversation.
+	ConversationItemAdded struct {
+		BaseEvent
+		PreviousItemID string `json:"previous_item_id"`
+		Item           Item   `json:"item"`
+	}
+
+	// ConversationItemDone is sent when an item of the conversation is finalized.
+	ConversationItemDone ConversationItemAdded
+
+	// ConversationItemDeleted is sent when an item is deleted from the conversation.
+	ConversationItemDeleted ItemReference
+
+	// InputAudioTranscriptionDelta contains a part of input audio transcription.
+	InputAudioTranscriptionDelta struct {
+		ItemReference
+		ContentIndex int    `json:"content_index"`
+		Delta        string `json:"delta"`
+	}
+
+	// InputAudioTranscriptionCompleted contains the full transcription of input audio.
+	InputAudioTranscriptionCompleted struct {
+		ItemReference
+		ContentIndex int    `json:"content_index"`
+		Transcript   string `json:"transcript"`
+	}
+
+	// InputAudioTranscriptionFailed is sent when input audio can't be transcribed.
+	InputAudioTranscriptionFailed struct {
+		ItemReference
+		ContentIndex int `json:"content_index"`
+		Error        struct {
+			Type    string `json:"type"`
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	// InputAudioBufferCommitted is sent when the audio buffer is committed
+	// and a user message item is created from it.
+	InputAudioBufferCommitted struct {
+		ItemReference
+		PreviousItemID string `json:"previous_item_id"`
+	}
+
+	// InputAudioBufferCleared is sent when the audio buffer is cleared.
+	InputAudioBufferCleared BaseEvent
+
+	// InputAudioBufferSpeechStarted is sent when turn detection finds speech in the audio buffer.
+	InputAudioBufferSpeechStarted struct {
+		ItemReference
+		AudioStartMs int `json:"audio_start_ms"`
+	}
+
+	// InputAudioBufferSpeechStopped is sent when turn detection finds the end of speech.
+	InputAudioBufferSpeechStopped struct {
+		ItemReference
+		AudioEndMs int `json:"audio_end_ms"`
+	}
+
+	// ResponseCreated is sent when a response starts.
+	ResponseCreated ResponseEvent
+
+	// ResponseDone is sent when a response is finished, whatever its final status is.
+	ResponseDone ResponseEvent
+
+	// ResponseOutputItemAdded is sent when a response adds an output item.
+	ResponseOutputItemAdded OutputItemEvent
+
+	// ResponseOutputItemDone is sent when an output item of a response is finished.
+	ResponseOutputItemDone OutputItemEvent
+
+	// ResponseOutputTextDelta contains a part of output text.
+	ResponseOutputTextDelta struct {
+		ContentReference
+		Delta string `json:"delta"`
+	}
+
+	// ResponseOutputTextDone contains the full output text.
+	ResponseOutputTextDone struct {
+		ContentReference
+		Text string `json:"text"`
+	}
+
+	// ResponseOutputAudioDelta contains a part of output audio, use Audio to decode it.
+	ResponseOutputAudioDelta struct {
+		ContentReference
+		Delta string `json:"delta"` // base64-encoded
+	}
+
+	// ResponseOutputAudioDone is sent when output audio is finished.
+	ResponseOutputAudioDone ContentReference
+
+	// ResponseOutputAudioTranscriptDelta contains a part of output audio transcript.
+	ResponseOutputAudioTranscriptDelta struct {
+		ContentReference
+		Delta string `json:"delta"`
+	}
+
+	// ResponseOutputAudioTranscriptDone contains the full output audio transcript.
+	ResponseOutputAudioTranscriptDone struct {
+		ContentReference
+		Transcript string `json:"transcript"`
+	}
+
+	// ResponseFunctionCallArgumentsDelta contains a part of function call arguments.
+	ResponseFunctionCallArgumentsDelta struct {
+		BaseEvent
+		ResponseID  string `json:"response_id"`
+		ItemID      string `json:"item_id"`
+		OutputIndex int    `json:"output_index"`
+		CallID      string `json:"call_id"`
+		Delta       string `json:"delta"`
+	}
+
+	// ResponseFunctionCallArgumentsDone contains the full function call.
+	ResponseFunctionCallArgumentsDone struct {
+		BaseEvent
+		ResponseID  string `json:"response_id"`
+		ItemID      string `json:"item_id"`
+		OutputIndex int    `json:"output_index"`
+		CallID      string `json:"call_id"`
+		Name        string `json:"name"`
+		Arguments   string `json:"arguments"`
+	}
+
+	// RateLimitsUpdated is sent at the start of a response with current rate limits.
+	RateLimitsUpdated struct {
+		BaseEvent
+		RateLimits []struct {
+			Name         string  `json:"name"` // "requests" or "tokens"
+			Limit        int     `json:"limit"`
+			Remaining    int     `json:"remaining"`
+			ResetSeconds float64 `json:"reset_seconds"`
+		} `json:"rate_limits"`
+	}
+
+	// UnknownEvent is a server event of a type not known by this package.
+	UnknownEvent struct {
+		BaseEvent
+		Raw json.RawMessage `json:"-"`
+	}
+)
+
+// Audio decodes the audio delta.
+func (e ResponseOutputAudioDelta) Audio() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(e.Delta)
+}
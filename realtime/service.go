@@ -0,0 +1,231 @@
+// Package realtime provides a wrapper for the OpenAI Realtime API.
+// A session is a WebSocket connection exchanging client and server events
+// for low-latency text and speech-to-speech conversations.
+package realtime
+
+import (
+	"context"
+)
+
+// Service defines methods to operate on Realtime API.
+type Service interface {
+	// Connect opens a new realtime session. If config is not nil, it's sent as session.update
+	// right after connecting. The model is taken from config, models.GPTRealtime is used by default.
+	// Context is only used for the dialer and doesn't limit session lifetime.
+	Connect(ctx context.Context, config *SessionConfig) (Session, error)
+}
+
+// Session is a realtime session over a WebSocket connection.
+// Methods sending client events are safe for concurrent use.
+// Server events are read with Next and Event, like a streaming iterator.
+//
+// If SessionConfig.Tools names functions registered in the client's tools registry,
+// their calls are executed automatically: outputs are added to the conversation and
+// a new response is requested. The calls are still delivered as events.
+type Session interface {
+	// Update sends session.update with given config.
+	Update(config *SessionConfig) error
+	// AppendAudio sends input_audio_buffer.append with given audio in the session input format.
+	AppendAudio(audio []byte) error
+	// CommitAudio sends input_audio_buffer.commit, creating a user message from the buffered audio.
+	// Not needed with turn detection, which commits the buffer automatically.
+	CommitAudio() error
+	// ClearAudio sends input_audio_buffer.clear, discarding the buffered audio.
+	ClearAudio() error
+	// CreateItem sends conversation.item.create to add an item to the conversation.
+	CreateItem(item Item) error
+	// CreateResponse sends response.create. Config is optional and overrides session settings
+	// for this response only.
+	CreateResponse(config *ResponseConfig) error
+	// CancelResponse sends response.cancel for the response in progress.
+	CancelResponse() error
+	// SendEvent sends an arbitrary client event, it must be marshaled to an object with "type" field.
+	SendEvent(event any) error
+
+	// Next waits for the next server event and reports whether it's available.
+	// It returns false when the session is closed or fails, see Err.
+	Next() bool
+	// Event returns the current server event, one of the types from events.go
+	// or UnknownEvent for event types not known by this package.
+	Event() any
+	// Err returns the error that ended the session, if any.
+	Err() error
+
+	// Close closes the session.
+	Close() error
+}
+
+// SessionConfig contains session settings sent with session.update.
+// Fields left empty keep their current values.
+type SessionConfig struct {
+	// always "realtime", set automatically
+	Type string `json:"type"`
+
+	Model        string `json:"model,omitempty"`
+	Instructions string `json:"instructions,omitempty"`
+	// "text" or "audio" (audio output includes its transcript)
+	OutputModalities []string     `json:"output_modalities,omitempty"`
+	Audio            *AudioConfig `json:"audio,omitempty"`
+
+	// Tools is a list of names of functions or tools from the tools registry.
+	Tools []string `json:"-"`
+	// "auto", "none", "required" or an object forcing a function:
+	//  {"type": "function", "name": "my_function"}
+	ToolChoice any `json:"tool_choice,omitempty"`
+	// ReturnToolCalls disables automatic execution of function calls.
+	ReturnToolCalls bool `json:"-"`
+
+	// integer or "inf"
+	MaxOutputTokens any     `json:"max_output_tokens,omitempty"`
+	Temperature     float64 `json:"temperature,omitempty"`
+}
+
+// AudioConfig contains audio settings of a session.
+type AudioConfig struct {
+	Input  *AudioInput  `json:"input,omitempty"`
+	Output *AudioOutput `json:"output,omitempty"`
+}
+
+// AudioInput contains settings for input audio.
+type AudioInput struct {
+	Format *AudioFormat `json:"format,omitempty"`
+	// Transcription enables transcription of input audio, delivered with
+	// ConversationItemInputAudioTranscriptionCompleted events.
+	Transcription *Transcription `json:"transcription,omitempty"`
+	// TurnDetection enables voice activity detection. Set Type to "none"
+	// to disable it and commit the audio buffer manually.
+	TurnDetection  *TurnDetection  `json:"turn_detection,omitempty"`
+	NoiseReduction *NoiseReduction `json:"noise_reduction,omitempty"`
+}
+
+// AudioOutput contains settings for output audio.
+type AudioOutput struct {
+	Format *AudioFormat `json:"format,omitempty"`
+	// e.g. "alloy", "ash", "ballad", "coral", "echo", "sage", "shimmer", "verse", "marin", "cedar"
+	Voice string  `json:"voice,omitempty"`
+	Speed float64 `json:"speed,omitempty"`
+}
+
+// AudioFormat is a format of input or output audio.
+type AudioFormat struct {
+	// "audio/pcm", "audio/pcmu" or "audio/pcma"
+	Type string `json:"type"`
+	// only for "audio/pcm", always 24000
+	Rate int `json:"rate,omitempty"`
+}
+
+// Supported audio formats.
+var (
+	// FormatPCM16 is 16-bit little-endian mono PCM at 24kHz.
+	FormatPCM16 = AudioFormat{Type: "audio/pcm", Rate: 24000}
+	// FormatG711ULaw is G.711 mu-law at 8kHz.
+	FormatG711ULaw = AudioFormat{Type: "audio/pcmu"}
+	// FormatG711ALaw is G.711 A-law at 8kHz.
+	FormatG711ALaw = AudioFormat{Type: "audio/pcma"}
+)
+
+// Transcription contains settings for transcription of input audio.
+type Transcription struct {
+	// e.g. "whisper-1", "gpt-4o-transcribe"
+	Model    string `json:"model"`
+	Language string `json:"language,omitempty"` // ISO-639-1, e.g. "en"
+	Prompt   string `json:"prompt,omitempty"`
+}
+
+// TurnDetection contains settings for voice activity detection.
+type TurnDetection struct {
+	// "server_vad" or "semantic_vad"
+	Type string `json:"type"`
+
+	// for server_vad only
+
+	Threshold         float64 `json:"threshold,omitempty"`
+	PrefixPaddingMs   int     `json:"prefix_padding_ms,omitempty"`
+	SilenceDurationMs int     `json:"silence_duration_ms,omitempty"`
+	IdleTimeoutMs     int     `json:"idle_timeout_ms,omitempty"`
+
+	// for semantic_vad only
+
+	// "low", "medium", "high" or "auto"
+	Eagerness string `json:"eagerness,omitempty"`
+
+	// whether to create a response when a turn ends, default true
+	CreateResponse *bool `json:"create_response,omitempty"`
+	// whether to interrupt a response in progress when speech starts, default true
+	InterruptResponse *bool `json:"interrupt_response,omitempty"`
+}
+
+// NoiseReduction contains settings for input audio noise reduction.
+type NoiseReduction struct {
+	// "near_field" or "far_field"
+	Type string `json:"type"`
+}
+
+// ResponseConfig contains settings for response.create that override session settings.
+type ResponseConfig struct {
+	Instructions     string       `json:"instructions,omitempty"`
+	OutputModalities []string     `json:"output_modalities,omitempty"`
+	Audio            *AudioConfig `json:"audio,omitempty"`
+	// Tools is a list of names of functions or tools from the tools registry.
+	Tools      []string `json:"-"`
+	ToolChoice any      `json:"tool_choice,omitempty"`
+	// "auto" (default) or "none", which creates an out-of-band response
+	// that is not added to the conversation
+	Conversation string `json:"conversation,omitempty"`
+	// Input replaces the conversation as the response context if set.
+	Input           []Item            `json:"input,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	MaxOutputTokens any               `json:"max_output_tokens,omitempty"`
+}
+
+// Item is a conversation item: a message, a function call or a function call output.
+type Item struct {
+	ID     string `json:"id,omitempty"`
+	Object string `json:"object,omitempty"` // "realtime.item"
+	// "message", "function_call" or "function_call_output"
+	Type   string `json:"type"`
+	Status string `json:"status,omitempty"` // "completed", "incomplete" or "in_progress"
+
+	// for messages
+
+	Role    string    `json:"role,omitempty"` // "user", "assistant" or "system"
+	Content []Content `json:"content,omitempty"`
+
+	// for function calls and outputs
+
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"` // JSON object encoded as string
+	Output    string `json:"output,omitempty"`
+}
+
+// Content is a part of message content.
+type Content struct {
+	// "input_text", "input_audio", "output_text" or "output_audio"
+	Type       string `json:"type"`
+	Text       string `json:"text,omitempty"`
+	Audio      string `json:"audio,omitempty"` // base64-encoded
+	Transcript string `json:"transcript,omitempty"`
+}
+
+// TextMessage creates a message item with given role and text.
+func TextMessage(role, text string) Item {
+	contentType := "input_text"
+	if role == "assistant" {
+		contentType = "output_text"
+	}
+	return Item{
+		Type:    "message",
+		Role:    role,
+		Content: []Content{{Type: contentType, Text: text}},
+	}
+}
+
+// FunctionCallOutput creates a function call output item for given call.
+func FunctionCallOutput(callID, output string) Item {
+	return Item{
+		Type:   "function_call_output",
+		CallID: callID,
+		Output: output,
+	}
+}
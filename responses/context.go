@@ -0,0 +1,60 @@
+package responses
+
+import "github.com/unkn0wncode/openai/content/output"
+
+// ContextStrategy is a way to keep the input of a request within the context window of the model.
+type ContextStrategy string
+
+// Available context strategies.
+const (
+	// ContextDropOldest drops the oldest turns of the input, keeping leading system and
+	// developer messages and Instructions.
+	ContextDropOldest ContextStrategy = "drop_oldest"
+	// ContextSummarize replaces the oldest turns of the input with their summary
+	// made by a separate request, usually with a cheaper model.
+	ContextSummarize ContextStrategy = "summarize"
+	// ContextServerCompaction delegates to the compaction of ContextManagement on the server.
+	// Input is sent as is.
+	ContextServerCompaction ContextStrategy = "server_compaction"
+)
+
+// DefaultSummaryInstructions are used for ContextSummarize when no SummaryInstructions are given.
+const DefaultSummaryInstructions = "Summarize the conversation transcript given by the user. " +
+	"Keep facts, decisions, names, numbers, open questions and results of tool calls " +
+	"that may be needed to continue the conversation. Reply with the summary only."
+
+// ContextWindow configures client-side management of the context window of a request.
+// The input is only changed if it's estimated to exceed the limit.
+// Turns are cut at user messages, so that tool calls are never separated from their outputs.
+type ContextWindow struct {
+	Strategy ContextStrategy
+
+	// MaxInputTokens is the token budget for the whole request without output.
+	// Defaults to LimitContext of the model from models.Data minus MaxOutputTokens of the request.
+	MaxInputTokens int
+
+	// SummaryModel is the model used by ContextSummarize, default models.DefaultNano.
+	// Reasoning models are asked for the lowest reasoning effort they support.
+	SummaryModel string
+	// SummaryInstructions are instructions for the summarizing request, default DefaultSummaryInstructions.
+	SummaryInstructions string
+	// SummaryMaxTokens limits the length of the summary and is reserved in the budget, default 1000.
+	SummaryMaxTokens int
+
+	// CountTokens estimates the number of tokens in the request.
+	// Defaults to tokens.ResponsesRequest with the definitions of the request tools.
+	CountTokens func(req *Request) (int, error)
+
+	// OnTrim is called when the input was trimmed, in addition to setting Response.ContextReport.
+	OnTrim func(ContextReport)
+}
+
+// ContextReport describes how the input of a request was trimmed to fit the context window.
+type ContextReport struct {
+	Strategy     ContextStrategy
+	Limit        int          // token budget of the request
+	TokensBefore int          // estimated tokens before trimming
+	TokensAfter  int          // estimated tokens after trimming
+	Dropped      []output.Any // items removed from the input
+	Summary      string       // summary that replaced the dropped items, for ContextSummarize
+}
@@ -0,0 +1,30 @@
+package convstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/responses/convstore/storetest"
+)
+
+func TestStores(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Memory", func(t *testing.T) {
+		t.Parallel()
+		storetest.Run(t, NewMemory())
+	})
+
+	t.Run("File", func(t *testing.T) {
+		t.Parallel()
+		store, err := NewFile(t.TempDir())
+		require.NoError(t, err)
+		storetest.Run(t, store)
+
+		// IDs can't escape the directory
+		require.NoError(t, store.Append("../x/y", storetest.Item(t, "message", "hi")))
+		items, err := store.Items("../x/y")
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+	})
+}
@@ -0,0 +1,187 @@
+package convstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/unkn0wncode/openai/content/output"
+)
+
+// File keeps each conversation in a JSONL file in a directory, one item per line.
+// Appending only adds lines to the end of the file. It's safe for concurrent use
+// within one process.
+type File struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFile creates a store in given directory, creating the directory if needed.
+func NewFile(dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create conversation store directory: %w", err)
+	}
+	return &File{dir: dir}, nil
+}
+
+// path returns the file path of a conversation.
+func (f *File) path(conversationID string) (string, error) {
+	if conversationID == "" {
+		return "", errors.New("conversation ID is empty")
+	}
+	return filepath.Join(f.dir, url.PathEscape(conversationID)+".jsonl"), nil
+}
+
+// Items returns all items of the conversation in order.
+func (f *File) Items(conversationID string) ([]output.Any, error) {
+	path, err := f.path(conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]output.Any, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal(line, &items[i]); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal item %d of conversation '%s': %w", i, conversationID, err)
+		}
+	}
+
+	return items, nil
+}
+
+// Append adds items to the end of the conversation.
+func (f *File) Append(conversationID string, items ...output.Any) error {
+	path, err := f.path(conversationID)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to marshal item: %w", err)
+		}
+		// items must fit in one line
+		if err := json.Compact(&buf, data); err != nil {
+			return fmt.Errorf("failed to compact item: %w", err)
+		}
+		buf.WriteByte('\n')
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return writeFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, buf.Bytes())
+}
+
+// Fork creates a new conversation from the first n items of another one.
+func (f *File) Fork(conversationID string, n int, newConversationID string) error {
+	path, err := f.path(conversationID)
+	if err != nil {
+		return err
+	}
+	newPath, err := f.path(newConversationID)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lines, err := readLines(path)
+	if err != nil {
+		return err
+	}
+	if n < 0 || n > len(lines) {
+		return fmt.Errorf("can't fork at item %d, conversation '%s' has %d items", n, conversationID, len(lines))
+	}
+
+	var buf bytes.Buffer
+	for _, line := range lines[:n] {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	err = writeFile(newPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, buf.Bytes())
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("conversation '%s' already exists", newConversationID)
+	}
+	return err
+}
+
+// Delete removes the conversation file.
+func (f *File) Delete(conversationID string) error {
+	path, err := f.path(conversationID)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete conversation file: %w", err)
+	}
+	return nil
+}
+
+// readLines reads non-empty lines of a file, a missing file has no lines.
+func readLines(path string) ([][]byte, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open conversation file: %w", err)
+	}
+	defer file.Close()
+
+	var lines [][]byte
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			lines = append(lines, line)
+		}
+		if errors.Is(err, io.EOF) {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read conversation file: %w", err)
+		}
+	}
+}
+
+// writeFile opens a file with given flags and writes data to it.
+func writeFile(path string, flag int, data []byte) error {
+	file, err := os.OpenFile(path, flag, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open conversation file: %w", err)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write conversation file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close conversation file: %w", err)
+	}
+	return nil
+}
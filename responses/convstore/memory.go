@@ -0,0 +1,74 @@
+// Package convstore provides implementations of responses.ConversationStore
+// keeping conversations in memory, in JSONL files or in an SQL database.
+package convstore
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/unkn0wncode/openai/content/output"
+	"github.com/unkn0wncode/openai/responses"
+)
+
+// interface compliance checks
+var (
+	_ responses.ConversationStore = (*Memory)(nil)
+	_ responses.ConversationStore = (*File)(nil)
+	_ responses.ConversationStore = (*SQL)(nil)
+)
+
+// Memory keeps conversations in memory. It's safe for concurrent use.
+type Memory struct {
+	mu            sync.RWMutex
+	conversations map[string][]output.Any
+}
+
+// NewMemory creates an empty in-memory store.
+func NewMemory() *Memory {
+	return &Memory{conversations: map[string][]output.Any{}}
+}
+
+// Items returns all items of the conversation in order.
+func (m *Memory) Items(conversationID string) ([]output.Any, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return slices.Clone(m.conversations[conversationID]), nil
+}
+
+// Append adds items to the end of the conversation.
+func (m *Memory) Append(conversationID string, items ...output.Any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.conversations[conversationID] = append(m.conversations[conversationID], items...)
+	return nil
+}
+
+// Fork creates a new conversation from the first n items of another one.
+func (m *Memory) Fork(conversationID string, n int, newConversationID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.conversations[newConversationID]; ok {
+		return fmt.Errorf("conversation '%s' already exists", newConversationID)
+	}
+
+	items := m.conversations[conversationID]
+	if n < 0 || n > len(items) {
+		return fmt.Errorf("can't fork at item %d, conversation '%s' has %d items", n, conversationID, len(items))
+	}
+
+	m.conversations[newConversationID] = slices.Clone(items[:n])
+	return nil
+}
+
+// Delete removes the conversation.
+func (m *Memory) Delete(conversationID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.conversations, conversationID)
+	return nil
+}
@@ -0,0 +1,199 @@
+package convstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/unkn0wncode/openai/content/output"
+)
+
+// SQL keeps conversations in a table of an SQL database, one row per item.
+// It works with any database/sql driver, the caller imports the driver and opens the database.
+// Appends to the same conversation from several processes may fail on the primary key
+// and should be retried.
+type SQL struct {
+	db          *sql.DB
+	table       string
+	placeholder func(n int) string
+}
+
+// SQLOptions configures an SQL store.
+type SQLOptions struct {
+	// Table is the name of the table, default "conversation_items".
+	Table string
+	// Placeholder returns a query placeholder for the n-th argument starting from 1.
+	// Default is "?" used by MySQL and SQLite, use DollarPlaceholder for PostgreSQL.
+	Placeholder func(n int) string
+}
+
+// DollarPlaceholder returns PostgreSQL-style placeholders: $1, $2, etc.
+func DollarPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+var tableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// NewSQL creates a store using given database. Call CreateTable if the table doesn't exist.
+func NewSQL(db *sql.DB, opts SQLOptions) (*SQL, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	if opts.Table == "" {
+		opts.Table = "conversation_items"
+	}
+	if !tableNameRegexp.MatchString(opts.Table) {
+		return nil, fmt.Errorf("invalid table name '%s'", opts.Table)
+	}
+	if opts.Placeholder == nil {
+		opts.Placeholder = func(int) string { return "?" }
+	}
+
+	return &SQL{db: db, table: opts.Table, placeholder: opts.Placeholder}, nil
+}
+
+// CreateTable creates the table if it doesn't exist.
+func (s *SQL) CreateTable() error {
+	_, err := s.db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	conversation_id VARCHAR(255) NOT NULL,
+	position INTEGER NOT NULL,
+	item TEXT NOT NULL,
+	PRIMARY KEY (conversation_id, position)
+)`, s.table))
+	if err != nil {
+		return fmt.Errorf("failed to create conversation table: %w", err)
+	}
+	return nil
+}
+
+// Items returns all items of the conversation in order.
+func (s *SQL) Items(conversationID string) ([]output.Any, error) {
+	rows, err := s.db.Query(fmt.Sprintf(
+		"SELECT item FROM %s WHERE conversation_id = %s ORDER BY position",
+		s.table, s.placeholder(1),
+	), conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query conversation items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []output.Any
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation item: %w", err)
+		}
+
+		var item output.Any
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal item %d of conversation '%s': %w", len(items), conversationID, err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read conversation items: %w", err)
+	}
+
+	return items, nil
+}
+
+// Append adds items to the end of the conversation.
+func (s *SQL) Append(conversationID string, items ...output.Any) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	//nolint:errcheck // no-op after commit
+	defer tx.Rollback()
+
+	next, err := s.count(tx, conversationID)
+	if err != nil {
+		return err
+	}
+
+	insert := fmt.Sprintf(
+		"INSERT INTO %s (conversation_id, position, item) VALUES (%s, %s, %s)",
+		s.table, s.placeholder(1), s.placeholder(2), s.placeholder(3),
+	)
+	for i, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to marshal item: %w", err)
+		}
+		if _, err := tx.Exec(insert, conversationID, next+i, string(data)); err != nil {
+			return fmt.Errorf("failed to insert conversation item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit conversation items: %w", err)
+	}
+	return nil
+}
+
+// Fork creates a new conversation from the first n items of another one.
+func (s *SQL) Fork(conversationID string, n int, newConversationID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	//nolint:errcheck // no-op after commit
+	defer tx.Rollback()
+
+	existing, err := s.count(tx, newConversationID)
+	if err != nil {
+		return err
+	}
+	if existing > 0 {
+		return fmt.Errorf("conversation '%s' already exists", newConversationID)
+	}
+
+	total, err := s.count(tx, conversationID)
+	if err != nil {
+		return err
+	}
+	if n < 0 || n > total {
+		return fmt.Errorf("can't fork at item %d, conversation '%s' has %d items", n, conversationID, total)
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf(
+		"INSERT INTO %s (conversation_id, position, item) SELECT %s, position, item FROM %s WHERE conversation_id = %s AND position < %s",
+		s.table, s.placeholder(1), s.table, s.placeholder(2), s.placeholder(3),
+	), newConversationID, conversationID, n); err != nil {
+		return fmt.Errorf("failed to copy conversation items: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit conversation fork: %w", err)
+	}
+	return nil
+}
+
+// Delete removes all items of the conversation.
+func (s *SQL) Delete(conversationID string) error {
+	if _, err := s.db.Exec(fmt.Sprintf(
+		"DELETE FROM %s WHERE conversation_id = %s",
+		s.table, s.placeholder(1),
+	), conversationID); err != nil {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	return nil
+}
+
+// count returns the number of items in the conversation, which is also the next position.
+func (s *SQL) count(tx *sql.Tx, conversationID string) (int, error) {
+	var n int
+	if err := tx.QueryRow(fmt.Sprintf(
+		"SELECT COUNT(*) FROM %s WHERE conversation_id = %s",
+		s.table, s.placeholder(1),
+	), conversationID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count conversation items: %w", err)
+	}
+	return n, nil
+}
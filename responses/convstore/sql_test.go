@@ -0,0 +1,204 @@
+package convstore
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/responses/convstore/storetest"
+)
+
+// The SQL store runs against a real database in the sqlitetest module,
+// the fake driver here covers failures that a real database doesn't produce on demand.
+
+// fakeDriver is a database/sql driver that answers queries with preset results and failures.
+// Each data source name is a separate database.
+type fakeDriver struct {
+	mu  sync.Mutex
+	dbs map[string]*fakeDB
+}
+
+func init() {
+	sql.Register("convstore-fake", &fakeDriver{dbs: map[string]*fakeDB{}})
+}
+
+// fakeDB keeps preset results and counts transactions.
+type fakeDB struct {
+	mu        sync.Mutex
+	count     int64    // result of COUNT queries
+	items     []string // result of SELECT queries
+	failOn    string   // prefix of queries that fail
+	failAfter int      // number of matching queries that succeed before failing
+	commits   int
+	rollbacks int
+}
+
+// fakeDBCount numbers fake databases to keep them separate.
+var fakeDBCount atomic.Int64
+
+// openFakeDB opens a new fake database.
+func openFakeDB(t *testing.T) (*sql.DB, *fakeDB) {
+	t.Helper()
+	name := fmt.Sprintf("db%d", fakeDBCount.Add(1))
+	db, err := sql.Open("convstore-fake", name)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	conn, err := db.Driver().Open(name)
+	require.NoError(t, err)
+	return db, conn.(*fakeConn).db
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dbs[name] == nil {
+		d.dbs[name] = &fakeDB{}
+	}
+	return &fakeConn{db: d.dbs[name]}, nil
+}
+
+// fakeConn is a connection to a fake database.
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.commits++
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.rollbacks++
+	return nil
+}
+
+// fakeStmt runs one query against the fake database.
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+// fail reports whether the query must fail, must be called with the lock held.
+func (s *fakeStmt) fail() bool {
+	if s.db.failOn == "" || !strings.HasPrefix(s.query, s.db.failOn) {
+		return false
+	}
+	if s.db.failAfter > 0 {
+		s.db.failAfter--
+		return false
+	}
+	return true
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if s.fail() {
+		return nil, errors.New("fake failure")
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if s.fail() {
+		return nil, errors.New("fake failure")
+	}
+	if strings.HasPrefix(s.query, "SELECT COUNT(*)") {
+		return &fakeRows{values: []driver.Value{s.db.count}}, nil
+	}
+	rows := &fakeRows{}
+	for _, item := range s.db.items {
+		rows.values = append(rows.values, item)
+	}
+	return rows, nil
+}
+
+// fakeRows is a result of one column.
+type fakeRows struct {
+	values []driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"value"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+func TestSQLFailures(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewSQL(nil, SQLOptions{})
+	require.Error(t, err)
+
+	db, fake := openFakeDB(t)
+	_, err = NewSQL(db, SQLOptions{Table: "items; DROP TABLE x"})
+	require.ErrorContains(t, err, "invalid table name")
+	store, err := NewSQL(db, SQLOptions{})
+	require.NoError(t, err)
+
+	// a failed insert rolls back the items inserted before it
+	fake.failOn, fake.failAfter = "INSERT", 1
+	err = store.Append("conv", storetest.Item(t, "message", "a"), storetest.Item(t, "message", "b"))
+	require.ErrorContains(t, err, "failed to insert")
+	require.Equal(t, 0, fake.commits)
+	require.Equal(t, 1, fake.rollbacks)
+
+	// forks don't overwrite existing conversations
+	fake.failOn, fake.count = "", 2
+	require.ErrorContains(t, store.Fork("conv", 1, "branch"), "already exists")
+	require.Equal(t, 0, fake.commits)
+	require.Equal(t, 2, fake.rollbacks)
+
+	// broken rows are reported with their position
+	fake.items = []string{`{"type":"message"}`, `{broken`}
+	_, err = store.Items("conv")
+	require.ErrorContains(t, err, "failed to unmarshal item 1")
+
+	fake.failOn = "SELECT"
+	_, err = store.Items("conv")
+	require.ErrorContains(t, err, "failed to query")
+	require.ErrorContains(t, store.Append("conv", storetest.Item(t, "message", "a")), "failed to count")
+}
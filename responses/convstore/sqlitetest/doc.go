@@ -0,0 +1,4 @@
+// Package sqlitetest runs the SQL conversation store against a real SQLite database.
+// It's a separate module, so that the SQLite driver is not a dependency of the library.
+// Run the tests from this directory with "go test ./...".
+package sqlitetest
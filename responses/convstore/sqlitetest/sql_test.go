@@ -0,0 +1,86 @@
+package sqlitetest
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/responses/convstore"
+	"github.com/unkn0wncode/openai/responses/convstore/storetest"
+	_ "modernc.org/sqlite"
+)
+
+// openSQLite opens a new empty SQLite database in a temporary directory.
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "conversations.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// positions returns sorted positions of items of the conversation stored in the table.
+func positions(t *testing.T, db *sql.DB, table, conversationID string) []int64 {
+	t.Helper()
+	rows, err := db.Query(fmt.Sprintf(
+		"SELECT position FROM %s WHERE conversation_id = ? ORDER BY position", table,
+	), conversationID)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var result []int64
+	for rows.Next() {
+		var position int64
+		require.NoError(t, rows.Scan(&position))
+		result = append(result, position)
+	}
+	require.NoError(t, rows.Err())
+	return result
+}
+
+func TestSQL(t *testing.T) {
+	t.Parallel()
+
+	db := openSQLite(t)
+	store, err := convstore.NewSQL(db, convstore.SQLOptions{})
+	require.NoError(t, err)
+
+	_, err = store.Items("conv")
+	require.Error(t, err)
+	require.NoError(t, store.CreateTable())
+	require.NoError(t, store.CreateTable())
+	storetest.Run(t, store)
+
+	// items are numbered from the end of the conversation
+	require.NoError(t, store.Append("conv", storetest.Item(t, "message", "a"), storetest.Item(t, "message", "b")))
+	require.NoError(t, store.Append("conv", storetest.Item(t, "message", "c")))
+	require.Equal(t, []int64{0, 1, 2}, positions(t, db, "conversation_items", "conv"))
+	require.Equal(t, []int64{0, 1}, positions(t, db, "conversation_items", "branch"))
+
+	// forks are limited by the length and don't overwrite existing conversations
+	require.NoError(t, store.Fork("conv", 0, "empty"))
+	require.NoError(t, store.Fork("conv", 3, "full"))
+	require.Equal(t, []int64{0, 1, 2}, positions(t, db, "conversation_items", "full"))
+	require.ErrorContains(t, store.Fork("conv", -1, "other"), "can't fork")
+	require.ErrorContains(t, store.Fork("conv", 4, "other"), "can't fork")
+	require.ErrorContains(t, store.Fork("conv", 1, "full"), "already exists")
+	require.Empty(t, positions(t, db, "conversation_items", "other"))
+
+	// the primary key rejects items at taken positions
+	_, err = db.Exec("INSERT INTO conversation_items (conversation_id, position, item) VALUES ('conv', 0, '{}')")
+	require.Error(t, err)
+}
+
+func TestSQLDollarPlaceholder(t *testing.T) {
+	t.Parallel()
+
+	// SQLite accepts numbered placeholders too, so the PostgreSQL query style runs on it
+	db := openSQLite(t)
+	store, err := convstore.NewSQL(db, convstore.SQLOptions{Table: "history", Placeholder: convstore.DollarPlaceholder})
+	require.NoError(t, err)
+	require.NoError(t, store.CreateTable())
+	storetest.Run(t, store)
+	require.Equal(t, []int64{0, 1}, positions(t, db, "history", "branch"))
+}
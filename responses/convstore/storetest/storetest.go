@@ -0,0 +1,71 @@
+// Package storetest checks implementations of responses.ConversationStore.
+package storetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/content/output"
+	"github.com/unkn0wncode/openai/responses"
+)
+
+// Item creates an item with given type and text for tests.
+func Item(t *testing.T, itemType, text string) output.Any {
+	t.Helper()
+	var a output.Any
+	require.NoError(t, json.Unmarshal(fmt.Appendf(nil, `{"type":%q,"text":%q}`, itemType, text), &a))
+	return a
+}
+
+// Run checks the ConversationStore contract on an empty store.
+// It uses conversations "conv", "branch" and "other".
+func Run(t *testing.T, store responses.ConversationStore) {
+	t.Helper()
+
+	items, err := store.Items("conv")
+	require.NoError(t, err)
+	require.Empty(t, items)
+
+	// encrypted reasoning and compaction items are stored as is
+	reasoning := Item(t, "reasoning", "first")
+	var compaction output.Any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "compaction",
+		"encrypted_content": "gAAAA\nmultiline"
+	}`), &compaction))
+
+	require.NoError(t, store.Append("conv", Item(t, "message", "hi"), reasoning))
+	require.NoError(t, store.Append("conv", compaction))
+	require.NoError(t, store.Append("conv"))
+
+	items, err = store.Items("conv")
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	require.Equal(t, "message", items[0].Type)
+	require.JSONEq(t, reasoning.String(), items[1].String())
+	require.JSONEq(t, compaction.String(), items[2].String())
+
+	// branch after the first item and continue both conversations separately
+	require.NoError(t, store.Fork("conv", 1, "branch"))
+	require.Error(t, store.Fork("conv", 1, "branch"))
+	require.Error(t, store.Fork("conv", 4, "other"))
+	require.NoError(t, store.Append("branch", Item(t, "message", "branched")))
+
+	branch, err := store.Items("branch")
+	require.NoError(t, err)
+	require.Len(t, branch, 2)
+	require.JSONEq(t, items[0].String(), branch[0].String())
+	require.JSONEq(t, `{"type":"message","text":"branched"}`, branch[1].String())
+
+	items, err = store.Items("conv")
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	require.NoError(t, store.Delete("conv"))
+	items, err = store.Items("conv")
+	require.NoError(t, err)
+	require.Empty(t, items)
+	require.NoError(t, store.Delete("conv"))
+}
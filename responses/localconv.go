@@ -0,0 +1,75 @@
+// Package responses / localconv.go contains types for conversations kept on the client side.
+package responses
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/unkn0wncode/openai/content/output"
+	"github.com/unkn0wncode/openai/roles"
+)
+
+// ConversationStore keeps conversation items on the client side. It's an alternative
+// to server-side conversations and PreviousResponseID, which don't work when responses
+// are not stored (Store set to false, zero data retention).
+// Implementations are in the convstore package.
+type ConversationStore interface {
+	// Items returns all items of the conversation in order.
+	// A conversation that doesn't exist has no items.
+	Items(conversationID string) ([]output.Any, error)
+
+	// Append adds items to the end of the conversation, creating it if needed.
+	Append(conversationID string, items ...output.Any) error
+
+	// Fork creates a new conversation from the first n items of another one,
+	// allowing to branch the conversation at any point. The new conversation must not exist.
+	Fork(conversationID string, n int, newConversationID string) error
+
+	// Delete removes the conversation with all its items.
+	Delete(conversationID string) error
+}
+
+// LocalConversation refers to a conversation in a ConversationStore.
+//
+// When set in a request, Service.Send prepends stored items to the request input,
+// and after a successful response appends the new input items and all response outputs,
+// including items of automatic tool call rounds, to the store.
+// Follow-up requests with tool outputs replay the conversation instead of using PreviousResponseID.
+//
+// To replay reasoning of stateless requests, add "reasoning.encrypted_content" to Request.Include.
+type LocalConversation struct {
+	Store ConversationStore
+	ID    string
+}
+
+// Items returns items of the conversation from the store.
+func (lc *LocalConversation) Items() ([]output.Any, error) {
+	if lc.Store == nil {
+		return nil, fmt.Errorf("local conversation store is nil")
+	}
+	return lc.Store.Items(lc.ID)
+}
+
+// InputItems converts a request input into a list of items as they would be stored in
+// a conversation. A string input becomes a user message.
+func InputItems(reqInput any) ([]output.Any, error) {
+	if reqInput == nil {
+		return nil, nil
+	}
+
+	if text, ok := reqInput.(string); ok {
+		reqInput = []any{output.Message{Role: roles.User, Content: text}}
+	}
+
+	data, err := json.Marshal(reqInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal input: %w", err)
+	}
+
+	var items []output.Any
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal input items: %w", err)
+	}
+
+	return items, nil
+}
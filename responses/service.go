@@ -55,6 +55,15 @@ type Service interface {
 
 	// Conversation retrieves a conversation by ID.
 	Conversation(id string) (*Conversation, error)
+
+	// ExportConversation retrieves all items of a conversation in chronological order,
+	// fetching as many pages as needed.
+	ExportConversation(id string) ([]output.Any, error)
+
+	// ImportConversation creates a new conversation with given items, sending them in batches
+	// that fit the per-call item limit. If a batch fails, the created conversation is returned
+	// along with the error and contains the items sent before.
+	ImportConversation(metadata map[string]string, items ...any) (*Conversation, error)
 }
 
 // Content is an interface listing all types that can be used as content in Responses API.
@@ -126,6 +135,14 @@ type Request struct {
 	// If set, will be called on messages received alongside other outputs (e.g., tool calls)
 	// that would otherwise be returned in the response but can be handled sooner with this handler.
 	IntermediateMessageHandler func(output.Message) `json:"-"`
+	// If set, Send keeps the conversation in a local store, see LocalConversation.
+	// Can't be combined with Conversation or PreviousResponseID.
+	LocalConversation *LocalConversation `json:"-"`
+	// If set, Send trims the input to fit the context window of the model, see ContextWindow.
+	ContextWindow *ContextWindow `json:"-"`
+	// If set, parameters unsupported by the model are removed before sending instead of
+	// failing validation, see StripUnsupported.
+	AutoStrip bool `json:"-"`
 }
 
 // Clone creates a copy of the ResponseRequest with all fields copied.
@@ -180,6 +197,9 @@ type Response struct {
 	ID            string
 	Outputs       []output.Any
 	ParsedOutputs []any
+
+	// ContextReport is set if the input was trimmed according to Request.ContextWindow.
+	ContextReport *ContextReport
 }
 
 // Parse parses the []output.Any and places the parsed objects in ParsedOutputs.
@@ -393,6 +413,10 @@ type ConversationListOptions struct {
 	Limit   int
 	FirstID string
 	LastID  string
+	// After is an item ID to list items after, use ConversationItemList.LastID for the next page.
+	After string
+	// Order is "asc" or "desc" (default, newest items first).
+	Order   string
 	Include *ConversationItemsInclude
 }
 
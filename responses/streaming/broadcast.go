@@ -0,0 +1,262 @@
+// Package streaming / broadcast.go implements fan-out of a single stream to multiple subscribers.
+package streaming
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrSlowSubscriber is delivered to a subscriber with the PolicyFail policy when its buffer
+// overflows. The subscriber is detached after receiving it.
+var ErrSlowSubscriber = errors.New("subscriber buffer is full")
+
+// Policy defines what happens when an event is broadcast to a subscriber with a full buffer.
+type Policy int
+
+const (
+	// PolicyBlock waits until the subscriber has room for the event.
+	// A slow subscriber slows down the whole broadcast, including all other subscribers.
+	PolicyBlock Policy = iota
+	// PolicyDropOldest discards the oldest buffered event to make room for the new one.
+	// Use Subscription.Dropped to get the number of discarded events.
+	PolicyDropOldest
+	// PolicyFail delivers ErrSlowSubscriber after the buffered events and detaches the subscriber.
+	PolicyFail
+)
+
+// SubscribeOptions configures a subscription to a Broadcaster.
+type SubscribeOptions struct {
+	// Buffer is the number of events that can be queued for the subscriber.
+	// With PolicyDropOldest and PolicyFail it's raised to at least 1.
+	Buffer int
+	// Policy defines the behavior on buffer overflow, PolicyBlock by default.
+	Policy Policy
+}
+
+// Subscription is a single consumer of a Broadcaster.
+// It's a regular StreamIterator, so it can be iterated with Next, Chan or All.
+// Closing it or canceling its context detaches it from the broadcast.
+type Subscription struct {
+	*StreamIterator
+
+	ch      chan any
+	opts    SubscribeOptions
+	dropped atomic.Int64
+
+	b    *Broadcaster
+	stop func() bool // stops detaching on context cancellation
+}
+
+// Close closes the subscription and detaches it from the broadcast.
+func (s *Subscription) Close() {
+	s.StreamIterator.Close()
+	if s.b != nil {
+		s.b.remove(s)
+	}
+}
+
+// Dropped returns the number of events discarded due to PolicyDropOldest.
+func (s *Subscription) Dropped() int {
+	return int(s.dropped.Load())
+}
+
+// deliver queues an event for the subscriber according to its policy.
+// Returns false if the subscriber must be detached.
+func (s *Subscription) deliver(event any) bool {
+	switch s.opts.Policy {
+	case PolicyDropOldest:
+		for {
+			select {
+			case s.ch <- event:
+				return true
+			default:
+			}
+			select {
+			case <-s.ch:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	case PolicyFail:
+		// the last slot is reserved for the error, only the broadcaster sends to the channel,
+		// so the length can only decrease concurrently
+		if len(s.ch) >= s.opts.Buffer {
+			s.ch <- ErrSlowSubscriber
+			return false
+		}
+		s.ch <- event
+		return true
+	default:
+		select {
+		case s.ch <- event:
+			return true
+		case <-s.ctx.Done():
+			return false
+		case <-s.closed:
+			return false
+		}
+	}
+}
+
+// end delivers the result of the broadcast to the subscriber and closes its channel.
+func (s *Subscription) end(err error) {
+	if err != nil {
+		if s.opts.Policy == PolicyFail {
+			// the reserved slot is always free at this point
+			s.ch <- err
+		} else {
+			s.deliver(err)
+		}
+	}
+	close(s.ch)
+}
+
+// Broadcaster reads events from a source stream and delivers each of them to all subscribers.
+// Events are shared between subscribers as is, so they must not be modified by consumers.
+type Broadcaster struct {
+	src *StreamIterator
+
+	mu       sync.Mutex
+	subs     []*Subscription
+	finished bool
+	err      error
+
+	startOnce sync.Once
+	done      chan struct{}
+}
+
+// NewBroadcaster creates a Broadcaster for given source stream.
+// Source must not be consumed by anything else. Add subscribers with Subscribe,
+// then call Start to begin reading the source.
+func NewBroadcaster(src *StreamIterator) *Broadcaster {
+	return &Broadcaster{
+		src:  src,
+		done: make(chan struct{}),
+	}
+}
+
+// Subscribe adds a new subscriber. Subscribers added after Start only receive
+// events broadcast from that point on. The context limits the subscription,
+// it doesn't affect the source or other subscribers.
+func (b *Broadcaster) Subscribe(ctx context.Context, opts SubscribeOptions) *Subscription {
+	capacity := opts.Buffer
+	if opts.Policy != PolicyBlock {
+		if opts.Buffer < 1 {
+			opts.Buffer = 1
+		}
+		capacity = opts.Buffer
+		if opts.Policy == PolicyFail {
+			capacity++
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.finished {
+		// the broadcast is over, the new subscriber gets only its result
+		ch := make(chan any, 1)
+		if b.err != nil {
+			ch <- b.err
+		}
+		close(ch)
+		return &Subscription{StreamIterator: NewStreamIterator(ctx, ch), ch: ch, opts: opts}
+	}
+
+	ch := make(chan any, capacity)
+	sub := &Subscription{
+		StreamIterator: NewStreamIterator(ctx, ch),
+		ch:             ch,
+		opts:           opts,
+		b:              b,
+	}
+	sub.stop = context.AfterFunc(ctx, func() { b.remove(sub) })
+	b.subs = append(b.subs, sub)
+	return sub
+}
+
+// Start begins reading the source in a background goroutine. Subsequent calls do nothing.
+func (b *Broadcaster) Start() {
+	b.startOnce.Do(func() {
+		go b.run()
+	})
+}
+
+// Wait blocks until the source stream ends and returns its error, if any.
+func (b *Broadcaster) Wait() error {
+	<-b.done
+	return b.err
+}
+
+// run pumps events from the source to subscribers until the source ends.
+func (b *Broadcaster) run() {
+	defer close(b.done)
+
+	for b.src.Next() {
+		event := b.src.Event()
+		for _, sub := range b.subscribers() {
+			if !sub.deliver(event) {
+				b.detach(sub)
+			}
+		}
+	}
+
+	b.mu.Lock()
+	b.finished = true
+	b.err = b.src.Err()
+	subs := b.subs
+	b.subs = nil
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.stop()
+		sub.end(b.err)
+	}
+}
+
+// subscribers returns a snapshot of the current subscribers.
+func (b *Broadcaster) subscribers() []*Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*Subscription(nil), b.subs...)
+}
+
+// detach removes a subscriber from the broadcast and closes its channel.
+// Must only be called by the broadcasting goroutine, as it's the only one sending to the channel.
+func (b *Broadcaster) detach(sub *Subscription) {
+	if b.remove(sub) {
+		close(sub.ch)
+	}
+}
+
+// remove takes a subscriber out of the broadcast, so no further events are queued for it.
+// Its channel is left open, as an event may still be in delivery. Returns false if the
+// subscriber was already removed.
+func (b *Broadcaster) remove(sub *Subscription) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, s := range b.subs {
+		if s == sub {
+			b.subs = append(b.subs[:i], b.subs[i+1:]...)
+			if sub.stop != nil {
+				sub.stop()
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Tee splits the source stream into n subscriptions with same options and starts the broadcast.
+// Subscriptions use the context of the source stream.
+func Tee(src *StreamIterator, n int, opts SubscribeOptions) []*Subscription {
+	b := NewBroadcaster(src)
+	subs := make([]*Subscription, n)
+	for i := range subs {
+		subs[i] = b.Subscribe(src.ctx, opts)
+	}
+	b.Start()
+	return subs
+}
@@ -0,0 +1,223 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sourceOf creates a stream iterator delivering given items and closing afterwards.
+func sourceOf(items ...any) *StreamIterator {
+	ch := make(chan any, len(items))
+	for _, item := range items {
+		ch <- item
+	}
+	close(ch)
+	return NewStreamIterator(context.Background(), ch)
+}
+
+func TestStreamConcurrentConsumers(t *testing.T) {
+	t.Parallel()
+
+	const n = 1000
+	ch := make(chan any)
+	go func() {
+		defer close(ch)
+		for i := range n {
+			ch <- i
+		}
+	}()
+
+	s := NewStreamIterator(context.Background(), ch)
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		total int
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range s.Chan() {
+			mu.Lock()
+			total++
+			mu.Unlock()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for s.Next() {
+			_ = s.Event()
+			mu.Lock()
+			total++
+			mu.Unlock()
+		}
+	}()
+	wg.Wait()
+
+	require.Equal(t, n, total)
+	require.NoError(t, s.Err())
+}
+
+func TestStreamClose(t *testing.T) {
+	t.Parallel()
+
+	s := NewStreamIterator(context.Background(), make(chan any))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range s.Chan() {
+		}
+	}()
+
+	s.Close()
+	<-done
+	require.False(t, s.Next())
+	require.NoError(t, s.Err())
+}
+
+func TestStreamCancelled(t *testing.T) {
+	t.Parallel()
+
+	// a producer stopped by the context closes the channel without delivering the error,
+	// a truncated stream must never look complete
+	t.Run("Next", func(t *testing.T) {
+		t.Parallel()
+		for range 100 {
+			ctx, cancel := context.WithCancel(context.Background())
+			ch := make(chan any)
+			cancel()
+			close(ch)
+
+			s := NewStream(ctx, ch)
+			require.False(t, s.Next())
+			require.ErrorIs(t, s.Err(), context.Canceled)
+		}
+	})
+
+	t.Run("Chan", func(t *testing.T) {
+		t.Parallel()
+		for range 100 {
+			ctx, cancel := context.WithCancel(context.Background())
+			ch := make(chan any)
+			cancel()
+			close(ch)
+
+			s := NewStreamIterator(ctx, ch)
+			for range s.Chan() {
+			}
+			require.ErrorIs(t, s.Err(), context.Canceled)
+		}
+	})
+}
+
+func TestBroadcaster(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Tee", func(t *testing.T) {
+		t.Parallel()
+		subs := Tee(sourceOf(1, 2, 3), 3, SubscribeOptions{})
+
+		var wg sync.WaitGroup
+		results := make([][]any, len(subs))
+		for i, sub := range subs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = sub.All()
+			}()
+		}
+		wg.Wait()
+
+		for i, sub := range subs {
+			require.Equal(t, []any{1, 2, 3}, results[i])
+			require.NoError(t, sub.Err())
+		}
+	})
+
+	t.Run("SourceError", func(t *testing.T) {
+		t.Parallel()
+		b := NewBroadcaster(sourceOf(1, errors.New("broken")))
+		sub := b.Subscribe(context.Background(), SubscribeOptions{Buffer: 1})
+		b.Start()
+
+		require.True(t, sub.Next())
+		require.Equal(t, 1, sub.Event())
+		require.False(t, sub.Next())
+		require.EqualError(t, sub.Err(), "broken")
+		require.EqualError(t, b.Wait(), "broken")
+
+		late := b.Subscribe(context.Background(), SubscribeOptions{})
+		require.False(t, late.Next())
+		require.EqualError(t, late.Err(), "broken")
+	})
+
+	t.Run("DropOldest", func(t *testing.T) {
+		t.Parallel()
+		b := NewBroadcaster(sourceOf(1, 2, 3, 4, 5))
+		sub := b.Subscribe(context.Background(), SubscribeOptions{Buffer: 2, Policy: PolicyDropOldest})
+		b.Start()
+		require.NoError(t, b.Wait())
+
+		require.Equal(t, []any{4, 5}, sub.All())
+		require.Equal(t, 3, sub.Dropped())
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		t.Parallel()
+		b := NewBroadcaster(sourceOf(1, 2, 3, 4))
+		slow := b.Subscribe(context.Background(), SubscribeOptions{Buffer: 2, Policy: PolicyFail})
+		fast := b.Subscribe(context.Background(), SubscribeOptions{Buffer: 4})
+		b.Start()
+		require.NoError(t, b.Wait())
+
+		require.Equal(t, []any{1, 2}, slow.All())
+		require.ErrorIs(t, slow.Err(), ErrSlowSubscriber)
+		require.Equal(t, []any{1, 2, 3, 4}, fast.All())
+		require.NoError(t, fast.Err())
+	})
+
+	t.Run("DetachCanceled", func(t *testing.T) {
+		t.Parallel()
+		b := NewBroadcaster(sourceOf(1, 2, 3))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		gone := b.Subscribe(ctx, SubscribeOptions{})
+		kept := b.Subscribe(context.Background(), SubscribeOptions{Buffer: 3})
+		b.Start()
+		require.NoError(t, b.Wait())
+
+		require.False(t, gone.Next())
+		require.ErrorIs(t, gone.Err(), context.Canceled)
+		require.Equal(t, []any{1, 2, 3}, kept.All())
+	})
+
+	t.Run("DetachBuffered", func(t *testing.T) {
+		t.Parallel()
+		// subscribers that never block the broadcast are detached as soon as they are gone
+		src := make(chan any)
+		b := NewBroadcaster(NewStreamIterator(context.Background(), src))
+		ctx, cancel := context.WithCancel(context.Background())
+		canceled := b.Subscribe(ctx, SubscribeOptions{Policy: PolicyDropOldest})
+		closed := b.Subscribe(context.Background(), SubscribeOptions{Policy: PolicyFail})
+		kept := b.Subscribe(context.Background(), SubscribeOptions{Policy: PolicyDropOldest})
+		b.Start()
+
+		cancel()
+		closed.Close()
+		require.Eventually(t, func() bool {
+			return len(b.subscribers()) == 1
+		}, time.Second, time.Millisecond)
+		require.Same(t, kept, b.subscribers()[0])
+		require.False(t, canceled.Next())
+		require.ErrorIs(t, canceled.Err(), context.Canceled)
+
+		src <- 1
+		close(src)
+		require.NoError(t, b.Wait())
+		require.Equal(t, []any{1}, kept.All())
+	})
+}
@@ -0,0 +1,232 @@
+// Package streaming / relay.go contains helpers for relaying a stream to HTTP clients.
+package streaming
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	openai "github.com/unkn0wncode/openai/internal"
+)
+
+// RelayOptions configures relaying of a stream to an HTTP client.
+type RelayOptions struct {
+	// TextOnly makes the relay skip all events except ResponseOutputTextDelta.
+	// In SSE format the deltas are written as plain text data instead of JSON.
+	TextOnly bool
+
+	// Heartbeat is the interval between keep-alive writes when there are no events,
+	// zero disables heartbeats. SSE uses comment lines, NDJSON uses empty lines.
+	Heartbeat time.Duration
+
+	// Cancel is called when the client disconnects or can't be written to.
+	// Pass the cancel function of the context used for the upstream request to abort it.
+	// The stream is closed in any case.
+	Cancel context.CancelFunc
+}
+
+// RelaySSE writes events from the stream to the HTTP client as server-sent events,
+// flushing after each event. Each event is written with its type as the event name
+// and its JSON as data. With TextOnly, text deltas are written as unnamed events with
+// plain text data.
+//
+// After the stream ends, a "done" event with empty data is written. If the stream fails,
+// an "error" event with the error message is written and the error is returned.
+// If the client disconnects, the upstream is aborted and the error is returned.
+func RelaySSE(w http.ResponseWriter, r *http.Request, stream *StreamIterator, opts RelayOptions) error {
+	return relay(w, r, stream, opts, sseFormat{textOnly: opts.TextOnly})
+}
+
+// RelayNDJSON writes events from the stream to the HTTP client as newline-delimited JSON,
+// flushing after each event. Heartbeats are written as empty lines that clients must skip.
+//
+// If the stream fails, a line with {"type":"error","message":...} is written and
+// the error is returned. If the client disconnects, the upstream is aborted and
+// the error is returned.
+func RelayNDJSON(w http.ResponseWriter, r *http.Request, stream *StreamIterator, opts RelayOptions) error {
+	return relay(w, r, stream, opts, ndjsonFormat{})
+}
+
+// relayFormat defines how stream items are written to the client.
+type relayFormat interface {
+	contentType() string
+	writeEvent(w io.Writer, event any) error
+	writeError(w io.Writer, err error) error
+	writeEnd(w io.Writer) error
+	writeHeartbeat(w io.Writer) error
+}
+
+// relay pumps the stream to the client in given format.
+func relay(
+	w http.ResponseWriter, r *http.Request, stream *StreamIterator, opts RelayOptions, format relayFormat,
+) error {
+	rc := http.NewResponseController(w)
+	flush := func() error {
+		if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	}
+	abort := func() {
+		stream.Close()
+		if opts.Cancel != nil {
+			opts.Cancel()
+		}
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", format.contentType())
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no") // disables buffering in nginx
+	w.WriteHeader(http.StatusOK)
+	if err := flush(); err != nil {
+		abort()
+		return fmt.Errorf("failed to write to client: %w", err)
+	}
+
+	var heartbeat <-chan time.Time
+	if opts.Heartbeat > 0 {
+		ticker := time.NewTicker(opts.Heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	events := stream.Chan()
+	for {
+		var err error
+		select {
+		case event, ok := <-events:
+			if !ok {
+				if streamErr := stream.Err(); streamErr != nil {
+					if err := format.writeError(w, streamErr); err == nil {
+						_ = flush()
+					}
+					return streamErr
+				}
+				if err := format.writeEnd(w); err == nil {
+					_ = flush()
+				}
+				return nil
+			}
+
+			if streamErr, isErr := event.(error); isErr {
+				if err := format.writeError(w, streamErr); err == nil {
+					_ = flush()
+				}
+				return streamErr
+			}
+
+			if _, isDelta := event.(ResponseOutputTextDelta); opts.TextOnly && !isDelta {
+				continue
+			}
+			err = format.writeEvent(w, event)
+		case <-heartbeat:
+			err = format.writeHeartbeat(w)
+		case <-r.Context().Done():
+			abort()
+			return fmt.Errorf("client disconnected: %w", r.Context().Err())
+		}
+
+		if err == nil {
+			err = flush()
+		}
+		if err != nil {
+			abort()
+			return fmt.Errorf("failed to write to client: %w", err)
+		}
+	}
+}
+
+// sseFormat writes items as server-sent events.
+type sseFormat struct {
+	textOnly bool
+}
+
+func (sseFormat) contentType() string {
+	return "text/event-stream"
+}
+
+func (f sseFormat) writeEvent(w io.Writer, event any) error {
+	if delta, ok := event.(ResponseOutputTextDelta); ok && f.textOnly {
+		return writeSSE(w, "", delta.Delta)
+	}
+
+	data, err := openai.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	var base BaseEvent
+	if err := json.Unmarshal(data, &base); err != nil {
+		return fmt.Errorf("failed to get event type: %w", err)
+	}
+	return writeSSE(w, base.Type, string(data))
+}
+
+func (f sseFormat) writeError(w io.Writer, err error) error {
+	if f.textOnly {
+		return writeSSE(w, "error", err.Error())
+	}
+	data, _ := openai.Marshal(map[string]string{"type": "error", "message": err.Error()})
+	return writeSSE(w, "error", string(bytes.TrimSuffix(data, []byte("\n"))))
+}
+
+func (sseFormat) writeEnd(w io.Writer) error {
+	return writeSSE(w, "done", "")
+}
+
+func (sseFormat) writeHeartbeat(w io.Writer) error {
+	_, err := io.WriteString(w, ": ping\n\n")
+	return err
+}
+
+// writeSSE writes a single event, splitting multi-line data into multiple data fields.
+func writeSSE(w io.Writer, event, data string) error {
+	var b strings.Builder
+	if event != "" {
+		b.WriteString("event: " + event + "\n")
+	}
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r", "\n"), "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ndjsonFormat writes items as newline-delimited JSON.
+type ndjsonFormat struct{}
+
+func (ndjsonFormat) contentType() string {
+	return "application/x-ndjson"
+}
+
+func (ndjsonFormat) writeEvent(w io.Writer, event any) error {
+	// the encoder terminates the output with a newline
+	data, err := openai.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (f ndjsonFormat) writeError(w io.Writer, err error) error {
+	return f.writeEvent(w, map[string]string{"type": "error", "message": err.Error()})
+}
+
+func (ndjsonFormat) writeEnd(io.Writer) error {
+	return nil
+}
+
+func (ndjsonFormat) writeHeartbeat(w io.Writer) error {
+	_, err := io.WriteString(w, "\n")
+	return err
+}
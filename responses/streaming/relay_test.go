@@ -0,0 +1,123 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// textDelta creates a text delta event as it's received from the API.
+func textDelta(t *testing.T, delta string) any {
+	t.Helper()
+	event, err := Unmarshal([]byte(`{"type":"response.output_text.delta","sequence_number":1,"delta":` +
+		strings.ReplaceAll(`"`+delta+`"`, "\n", `\n`) + `}`))
+	require.NoError(t, err)
+	return event
+}
+
+// pingRecorder is a response recorder that signals when the first heartbeat is written.
+type pingRecorder struct {
+	*httptest.ResponseRecorder
+	once   sync.Once
+	pinged chan struct{}
+}
+
+func newPingRecorder() *pingRecorder {
+	return &pingRecorder{ResponseRecorder: httptest.NewRecorder(), pinged: make(chan struct{})}
+}
+
+func (r *pingRecorder) Write(b []byte) (int, error) {
+	return r.WriteString(string(b))
+}
+
+func (r *pingRecorder) WriteString(str string) (int, error) {
+	n, err := r.ResponseRecorder.WriteString(str)
+	if strings.Contains(str, "ping") {
+		r.once.Do(func() { close(r.pinged) })
+	}
+	return n, err
+}
+
+func TestRelaySSE(t *testing.T) {
+	t.Parallel()
+
+	created, err := Unmarshal([]byte(`{"type":"response.created","sequence_number":0,"response":{"id":"resp_1"}}`))
+	require.NoError(t, err)
+
+	t.Run("Events", func(t *testing.T) {
+		t.Parallel()
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		err := RelaySSE(rec, req, sourceOf(created, textDelta(t, "Hi")), RelayOptions{})
+		require.NoError(t, err)
+
+		require.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+		require.True(t, rec.Flushed)
+		body := rec.Body.String()
+		require.Contains(t, body, "event: response.created\ndata: {\"type\":\"response.created\"")
+		require.Contains(t, body, "event: response.output_text.delta\ndata: {")
+		require.True(t, strings.HasSuffix(body, "event: done\ndata: \n\n"))
+	})
+
+	t.Run("TextOnly", func(t *testing.T) {
+		t.Parallel()
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		src := sourceOf(created, textDelta(t, "line1\nline2"), errors.New("broken"))
+		err := RelaySSE(rec, req, src, RelayOptions{TextOnly: true})
+		require.EqualError(t, err, "broken")
+
+		require.Equal(t, "data: line1\ndata: line2\n\nevent: error\ndata: broken\n\n", rec.Body.String())
+	})
+
+	t.Run("ClientDisconnect", func(t *testing.T) {
+		t.Parallel()
+		upstreamCtx, cancelUpstream := context.WithCancel(context.Background())
+		defer cancelUpstream()
+		src := NewStreamIterator(upstreamCtx, make(chan any))
+
+		clientCtx, disconnect := context.WithCancel(context.Background())
+		req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(clientCtx)
+		rec := newPingRecorder()
+
+		done := make(chan error, 1)
+		go func() {
+			done <- RelaySSE(rec, req, src, RelayOptions{
+				Heartbeat: time.Millisecond,
+				Cancel:    cancelUpstream,
+			})
+		}()
+		select {
+		case <-rec.pinged:
+		case <-time.After(5 * time.Second):
+			t.Fatal("no heartbeat written")
+		}
+		disconnect()
+
+		require.ErrorIs(t, <-done, context.Canceled)
+		require.ErrorIs(t, upstreamCtx.Err(), context.Canceled)
+		require.Contains(t, rec.Body.String(), ": ping\n\n")
+	})
+}
+
+func TestRelayNDJSON(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	err := RelayNDJSON(rec, req, sourceOf(textDelta(t, "a<b"), errors.New("broken")), RelayOptions{})
+	require.EqualError(t, err, "broken")
+
+	require.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], `"delta":"a<b"`)
+	require.Equal(t, `{"message":"broken","type":"error"}`, lines[1])
+}
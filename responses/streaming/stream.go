@@ -7,12 +7,20 @@ import (
 )
 
 // Stream represents a streaming response iterator with a Next() method.
+// It is safe to call its methods from multiple goroutines, but each event is delivered only once,
+// so concurrent consumers will receive different events. Use Broadcaster to deliver same events
+// to multiple consumers.
 type Stream struct {
 	eventChan <-chan any
 	ctx       context.Context
-	current   any
-	err       error
-	done      bool
+
+	mu      sync.Mutex
+	current any
+	err     error
+	done    bool
+
+	closeOnce sync.Once
+	closed    chan struct{}
 }
 
 // NewStream creates a new Stream from an event channel and context.
@@ -20,6 +28,7 @@ func NewStream(ctx context.Context, eventChan <-chan any) *Stream {
 	return &Stream{
 		eventChan: eventChan,
 		ctx:       ctx,
+		closed:    make(chan struct{}),
 	}
 }
 
@@ -27,46 +36,77 @@ func NewStream(ctx context.Context, eventChan <-chan any) *Stream {
 // It returns true if there is an event available, false if the stream is done or an error occurred.
 // After Next returns false, use Err() to check if it was due to an error.
 func (s *Stream) Next() bool {
-	if s.done {
+	if s.isDone() {
+		return false
+	}
+	if err := s.ctx.Err(); err != nil {
+		s.finish(err)
 		return false
 	}
 
 	select {
 	case event, ok := <-s.eventChan:
 		if !ok {
-			s.done = true
+			// the producer closes the channel without the error when the context is done
+			s.finish(s.ctx.Err())
 			return false
 		}
 
 		// Check if the event is an error
 		if err, isErr := event.(error); isErr {
-			s.err = err
-			s.done = true
+			s.finish(err)
 			return false
 		}
 
+		s.mu.Lock()
 		s.current = event
+		s.mu.Unlock()
 		return true
 	case <-s.ctx.Done():
-		s.err = s.ctx.Err()
-		s.done = true
+		s.finish(s.ctx.Err())
+		return false
+	case <-s.closed:
 		return false
 	}
 }
 
 // Event returns the current event. Only valid after Next() returns true.
 func (s *Stream) Event() any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.current
 }
 
 // Err returns any error that occurred during iteration.
 func (s *Stream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.err
 }
 
-// Close closes the stream.
+// Close closes the stream. Pending and future calls to Next return false.
+// To abort the underlying request, cancel the context that was used to create the stream.
 func (s *Stream) Close() {
+	s.finish(nil)
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+// isDone reports whether the stream has finished.
+func (s *Stream) isDone() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done
+}
+
+// finish marks the stream as done, keeping the first error that occurred.
+func (s *Stream) finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return
+	}
 	s.done = true
+	s.err = err
 }
 
 // StreamIterator provides both Next() iteration and channel-based iteration.
@@ -86,6 +126,8 @@ func NewStreamIterator(ctx context.Context, eventChan <-chan any) *StreamIterato
 // Chan returns the underlying channel for range iteration.
 // This allows: for event := range stream.Chan() { ... }
 // Errors are sent through the channel AND stored for later access via Err().
+// Chan can be called multiple times and returns the same channel.
+// Calling Next while ranging over the channel takes events away from the channel.
 func (s *StreamIterator) Chan() <-chan any {
 	s.chanOnce.Do(func() {
 		s.outputChan = make(chan any)
@@ -96,22 +138,30 @@ func (s *StreamIterator) Chan() <-chan any {
 				select {
 				case event, ok := <-s.eventChan:
 					if !ok {
+						s.finish(s.ctx.Err())
 						return
 					}
 					if err, isErr := event.(error); isErr {
-						s.err = err
-						s.done = true
-						s.outputChan <- err
+						s.finish(err)
+						select {
+						case s.outputChan <- err:
+						case <-s.ctx.Done():
+						case <-s.closed:
+						}
 						return
 					}
 					select {
 					case s.outputChan <- event:
 					case <-s.ctx.Done():
+						s.finish(s.ctx.Err())
+						return
+					case <-s.closed:
 						return
 					}
 				case <-s.ctx.Done():
-					s.err = s.ctx.Err()
-					s.done = true
+					s.finish(s.ctx.Err())
+					return
+				case <-s.closed:
 					return
 				}
 			}
@@ -130,6 +180,9 @@ func (s *StreamIterator) All() []any {
 
 	events := []any{}
 	for event := range s.Chan() {
+		if _, isErr := event.(error); isErr {
+			continue
+		}
 		events = append(events, event)
 	}
 	return events
@@ -0,0 +1,149 @@
+package responses
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/unkn0wncode/openai/content/input"
+	"github.com/unkn0wncode/openai/content/output"
+	"github.com/unkn0wncode/openai/models"
+)
+
+// builtinToolTypes are tool types that can be listed in Request.Tools by type.
+var builtinToolTypes = []string{
+	models.ToolWebSearch,
+	"web_search_preview",
+	models.ToolFileSearch,
+	models.ToolComputerUse,
+	models.ToolMCP,
+	models.ToolLocalShell,
+	models.ToolCodeInterpreter,
+	models.ToolShell,
+	models.ToolApplyPatch,
+	models.ToolImageGeneration,
+}
+
+// Validate checks the request against capabilities and limits of its model, see models.GetCapabilities.
+// Requests to unknown models are only checked against limits from models.Data.
+// Built-in tools are checked by type, other tools are checked by the service when they're resolved.
+// Returns joined errors wrapping models.ErrUnsupported.
+func (data *Request) Validate() error {
+	model := data.Model
+	if model == "" {
+		model = models.Default
+	}
+
+	var errs []error
+	unsupported := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: model '%s': %s", models.ErrUnsupported, model, fmt.Sprintf(format, args...)))
+	}
+
+	if limit := models.Data[model].LimitOutput; limit > 0 && data.MaxOutputTokens > limit {
+		unsupported("MaxOutputTokens %d exceeds the limit of %d", data.MaxOutputTokens, limit)
+	}
+
+	caps, ok := models.GetCapabilities(model)
+	if !ok {
+		return errors.Join(errs...)
+	}
+
+	if data.Reasoning != nil && !caps.Reasoning {
+		unsupported("Reasoning is not supported")
+	}
+	if (data.Temperature != 0 || data.TopP != 0) && !data.samplingAllowed(caps) {
+		unsupported("Temperature and TopP are not supported with reasoning")
+	}
+	if data.Text != nil && data.Text.Format.Type == "json_schema" && !caps.StructuredOutputs {
+		unsupported("structured outputs are not supported")
+	}
+	for _, name := range data.Tools {
+		if slices.Contains(builtinToolTypes, name) && !caps.SupportsTool(name) {
+			unsupported("tool '%s' is not supported", name)
+		}
+	}
+
+	if !caps.SupportsInput(models.ModalityImage) {
+		items, err := InputItems(data.Input)
+		if err != nil {
+			return err
+		}
+		if hasInputImages(items) {
+			unsupported("image inputs are not supported")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// samplingAllowed reports whether sampling parameters can be used with the model and reasoning effort.
+func (data *Request) samplingAllowed(caps models.Capabilities) bool {
+	if !caps.Sampling {
+		return false
+	}
+	return !caps.Reasoning || data.Reasoning == nil || data.Reasoning.Effort == "" || data.Reasoning.Effort == "none"
+}
+
+// StripUnsupported removes parameters that the model doesn't support: Reasoning, Temperature, TopP
+// and built-in tools, and lowers MaxOutputTokens to the limit of the model.
+// Content, like images or output format, is never changed.
+// Returns names of changed parameters.
+func (data *Request) StripUnsupported() []string {
+	model := data.Model
+	if model == "" {
+		model = models.Default
+	}
+
+	var stripped []string
+	if limit := models.Data[model].LimitOutput; limit > 0 && data.MaxOutputTokens > limit {
+		data.MaxOutputTokens = limit
+		stripped = append(stripped, "MaxOutputTokens")
+	}
+
+	caps, ok := models.GetCapabilities(model)
+	if !ok {
+		return stripped
+	}
+
+	if data.Reasoning != nil && !caps.Reasoning {
+		data.Reasoning = nil
+		stripped = append(stripped, "Reasoning")
+	}
+	if (data.Temperature != 0 || data.TopP != 0) && !data.samplingAllowed(caps) {
+		data.Temperature, data.TopP = 0, 0
+		stripped = append(stripped, "Temperature", "TopP")
+	}
+
+	var toolNames []string
+	for _, name := range data.Tools {
+		if slices.Contains(builtinToolTypes, name) && !caps.SupportsTool(name) {
+			stripped = append(stripped, "Tools."+name)
+			continue
+		}
+		toolNames = append(toolNames, name)
+	}
+	data.Tools = toolNames
+
+	return stripped
+}
+
+// hasInputImages reports whether any message among the items contains an input image.
+func hasInputImages(items []output.Any) bool {
+	for _, item := range items {
+		if item.Type != "" && item.Type != "message" {
+			continue
+		}
+
+		var msg output.Message
+		if err := item.UnmarshalToTarget(&msg); err != nil {
+			continue
+		}
+		parts, _ := msg.Content.([]any)
+		for _, part := range parts {
+			if _, ok := part.(input.InputImage); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package responses
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/content/input"
+	"github.com/unkn0wncode/openai/content/output"
+	"github.com/unkn0wncode/openai/models"
+	"github.com/unkn0wncode/openai/roles"
+)
+
+func TestRequestValidate(t *testing.T) {
+	t.Parallel()
+
+	imageInput := []any{output.Message{Role: roles.User, Content: []any{
+		input.InputText{Text: "What's on the picture?"},
+		input.InputImage{ImageURL: "https://example.com/a.png"},
+	}}}
+
+	for name, tc := range map[string]struct {
+		req   Request
+		valid bool
+	}{
+		"Default":                   {Request{Input: "hi"}, true},
+		"UnknownModel":              {Request{Model: "my-model", Input: "hi", Temperature: 0.5}, true},
+		"TemperatureOnO3":           {Request{Model: models.GPTO3, Input: "hi", Temperature: 0.5}, false},
+		"TemperatureOnGPT5":         {Request{Model: models.GPT5, Input: "hi", TopP: 0.5}, false},
+		"TemperatureWithoutEffort":  {Request{Model: models.GPT52, Input: "hi", Temperature: 0.5}, true},
+		"TemperatureWithEffortNone": {Request{Model: models.GPT52, Input: "hi", Temperature: 0.5, Reasoning: &ReasoningConfig{Effort: "none"}}, true},
+		"TemperatureWithEffort":     {Request{Model: models.GPT52, Input: "hi", Temperature: 0.5, Reasoning: &ReasoningConfig{Effort: "low"}}, false},
+		"ReasoningOnGPT4o":          {Request{Model: models.GPT4Omni, Input: "hi", Reasoning: &ReasoningConfig{Effort: "low"}}, false},
+		"MaxOutputTokens":           {Request{Model: models.GPT4Omni, Input: "hi", MaxOutputTokens: 1_000_000}, false},
+		"ImageOnGPT4o":              {Request{Model: models.GPT4Omni, Input: imageInput}, true},
+		"ImageOnO3Mini":             {Request{Model: models.GPTO3Mini, Input: imageInput}, false},
+		"SchemaOnGPT4":              {Request{Model: models.GPT4Turbo, Input: "hi", Text: &TextOptions{Format: TextFormatType{Type: "json_schema"}}}, false},
+		"WebSearchOnO1Mini":         {Request{Model: models.GPTO1Mini, Input: "hi", Tools: []string{"web_search"}}, false},
+		"ShellOnGPT54":              {Request{Model: models.GPT54, Input: "hi", Tools: []string{"shell", "my_function"}}, true},
+		"FineTuned":                 {Request{Model: "ft:gpt-4o-mini:org::abc", Input: "hi", Reasoning: &ReasoningConfig{}}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.req.Validate()
+			if tc.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.True(t, errors.Is(err, models.ErrUnsupported), err)
+		})
+	}
+
+	t.Run("StripUnsupported", func(t *testing.T) {
+		t.Parallel()
+		req := Request{
+			Model:           models.GPTO3Mini,
+			Input:           imageInput,
+			Temperature:     0.5,
+			MaxOutputTokens: 1_000_000,
+			Tools:           []string{"web_search", "my_function"},
+		}
+		require.Equal(t, []string{"MaxOutputTokens", "Temperature", "TopP", "Tools.web_search"}, req.StripUnsupported())
+		require.Zero(t, req.Temperature)
+		require.Equal(t, models.Data[models.GPTO3Mini].LimitOutput, req.MaxOutputTokens)
+		require.Equal(t, []string{"my_function"}, req.Tools)
+
+		// content is kept, so the request is still invalid
+		require.ErrorIs(t, req.Validate(), models.ErrUnsupported)
+		req.Input = "hi"
+		require.NoError(t, req.Validate())
+	})
+}
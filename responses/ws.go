@@ -2,19 +2,66 @@ package responses
 
 import (
 	"context"
+	"errors"
 
 	"github.com/unkn0wncode/openai/responses/streaming"
 )
 
+// ErrWSConnClosed is returned by WSConn.Send when the connection is closed or broken.
+var ErrWSConnClosed = errors.New("websocket connection is closed")
+
+// WSState is the state of a WebSocket connection.
+type WSState int
+
+const (
+	// WSStateConnecting means that the connection is being (re)established.
+	WSStateConnecting WSState = iota
+	// WSStateOpen means that the connection is established and healthy.
+	WSStateOpen
+	// WSStateDegraded means that the connection was lost or stopped responding.
+	// Turns that already started are failed, the rest are kept and sent again after a redial.
+	WSStateDegraded
+	// WSStateClosed means that the connection is closed for good,
+	// either by Close or after failing to reconnect. It's the final state.
+	WSStateClosed
+)
+
+// String returns the name of the state.
+func (s WSState) String() string {
+	switch s {
+	case WSStateConnecting:
+		return "connecting"
+	case WSStateOpen:
+		return "open"
+	case WSStateDegraded:
+		return "degraded"
+	case WSStateClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
 // WSConn is a persistent WebSocket connection to the Responses API.
 // It is created by Service.WebSocket.
 type WSConn interface {
 	// Send sends one response.create event and returns a streaming iterator for
-	// the resulting server events.
+	// the resulting server events. Events are buffered until they're read, so a stream
+	// that is neither read nor canceled keeps its whole response in memory.
 	Send(ctx context.Context, req *Request) (*streaming.StreamIterator, error)
+	// SendAuto works like Send, but automatically executes registered tools called by the model
+	// and sends their outputs in follow-up turns on the same connection, like Service.Send does.
+	// Events of all turns are delivered through the returned iterator.
+	SendAuto(ctx context.Context, req *Request) (*streaming.StreamIterator, error)
 	// Warmup sends one response.create event with generate=false and returns the
 	// response ID that can be used as PreviousResponseID.
 	Warmup(ctx context.Context, req *Request) (string, error)
+	// State returns the current state of the connection.
+	State() WSState
+	// States returns a channel receiving state changes. All calls return the same channel.
+	// If it's not read in time, only the latest state is kept.
+	// The channel is closed after WSStateClosed is delivered.
+	States() <-chan WSState
 	// Close closes the WebSocket connection.
 	Close() error
 }
@@ -0,0 +1,480 @@
+package responses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/responses/streaming"
+)
+
+// WSPoolOptions configures a WSPool.
+type WSPoolOptions struct {
+	// Size is the number of connections kept open, 4 by default.
+	Size int
+	// MinBackoff is the delay before the first reconnection attempt, 500ms by default.
+	// The delay is doubled after each failed attempt.
+	MinBackoff time.Duration
+	// MaxBackoff limits the delay between reconnection attempts, 30s by default.
+	MaxBackoff time.Duration
+}
+
+// wsPoolAffinityLimit is the number of latest response IDs remembered per connection.
+const wsPoolAffinityLimit = 256
+
+// WSPool is a pool of WebSocket connections that allows running multiple turns concurrently.
+// A single WSConn processes turns sequentially, the pool routes each Send to an idle
+// connection instead, waiting for one to become idle if all are busy.
+//
+// Requests with PreviousResponseID are routed to the connection that produced the referenced
+// response to benefit from connection-local state, even if it's busy. If that connection was
+// lost, any idle connection is used, which requires the previous response to be stored.
+//
+// Connections that get closed for good, e.g. after exhausting their own reconnection attempts,
+// are replaced in the background with exponential backoff.
+//
+// The pool state is open when all connections are open, degraded when only some are,
+// and connecting when none are.
+//
+// WSPool implements WSConn, so it can be used in place of a single connection.
+type WSPool struct {
+	svc  Service
+	opts WSPoolOptions
+
+	ctx    context.Context // canceled on Close, limits reconnection attempts
+	cancel context.CancelFunc
+	state  *openai.StateNotifier[WSState]
+
+	mu       sync.Mutex
+	conns    []*wsPoolConn
+	affinity map[string]*wsPoolConn // response ID -> connection that produced it
+	changed  chan struct{}          // closed and replaced when a connection becomes available
+	closed   bool
+}
+
+var _ WSConn = (*WSPool)(nil)
+
+// wsPoolConn is a single slot of the pool.
+type wsPoolConn struct {
+	conn WSConn // nil while reconnecting
+	busy int    // number of turns in flight
+	ids  []string
+}
+
+// NewWSPool opens a pool of WebSocket connections using given service.
+// Context is only used for the initial dials. Returns an error if no connection could be opened,
+// connections that failed to open are retried in the background.
+func NewWSPool(ctx context.Context, svc Service, opts WSPoolOptions) (*WSPool, error) {
+	if opts.Size <= 0 {
+		opts.Size = 4
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = 500 * time.Millisecond
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = max(30*time.Second, opts.MinBackoff)
+	}
+
+	poolCtx, cancel := context.WithCancel(context.Background())
+	p := &WSPool{
+		svc:      svc,
+		opts:     opts,
+		ctx:      poolCtx,
+		cancel:   cancel,
+		state:    openai.NewStateNotifier(WSStateConnecting),
+		affinity: map[string]*wsPoolConn{},
+		changed:  make(chan struct{}),
+	}
+
+	type dialResult struct {
+		conn WSConn
+		err  error
+	}
+	results := make(chan dialResult, opts.Size)
+	for range opts.Size {
+		go func() {
+			conn, err := svc.WebSocket(ctx)
+			results <- dialResult{conn, err}
+		}()
+	}
+
+	var errs []error
+	for range opts.Size {
+		res := <-results
+		pc := &wsPoolConn{conn: res.conn}
+		p.conns = append(p.conns, pc)
+		if res.err != nil {
+			errs = append(errs, res.err)
+		}
+	}
+
+	if len(errs) == opts.Size {
+		cancel()
+		return nil, fmt.Errorf("failed to open websocket pool: %w", errors.Join(errs...))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, pc := range p.conns {
+		if pc.conn == nil {
+			go p.reconnect(pc)
+		} else {
+			go p.watch(pc, pc.conn)
+		}
+	}
+	p.updateState()
+
+	return p, nil
+}
+
+// Send routes the request to a connection and returns a streaming iterator for the server's reply.
+// If all connections are busy, it waits until one becomes idle or ctx is done.
+// Sends failing with ErrWSConnClosed are retried on other connections.
+// The stream must be read to the end or its context canceled to release the connection.
+// After cancellation, the connection is released once the server finishes the current turn.
+func (p *WSPool) Send(ctx context.Context, req *Request) (*streaming.StreamIterator, error) {
+	return p.send(ctx, req, WSConn.Send)
+}
+
+// SendAuto routes the request to a connection like Send and runs the whole tool execution loop
+// of WSConn.SendAuto on it.
+func (p *WSPool) SendAuto(ctx context.Context, req *Request) (*streaming.StreamIterator, error) {
+	return p.send(ctx, req, WSConn.SendAuto)
+}
+
+// send routes the request to a connection and starts a turn on it with given method.
+func (p *WSPool) send(
+	ctx context.Context, req *Request,
+	method func(WSConn, context.Context, *Request) (*streaming.StreamIterator, error),
+) (*streaming.StreamIterator, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request is nil")
+	}
+
+	for {
+		pc, conn, err := p.acquire(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		// the turn outlives ctx, so that the connection is released only when the server is done with it
+		turnCtx, stopTurn := context.WithCancel(context.WithoutCancel(ctx))
+		stream, err := method(conn, turnCtx, req)
+		if errors.Is(err, ErrWSConnClosed) {
+			// the connection is dead, retry on another one
+			stopTurn()
+			p.release(pc, conn, err)
+			continue
+		}
+		if err != nil {
+			stopTurn()
+			p.release(pc, conn, nil)
+			return nil, err
+		}
+
+		return p.track(ctx, pc, conn, req, stream, stopTurn), nil
+	}
+}
+
+// Warmup sends a request with generate=false over the pool and returns the response ID
+// for use as PreviousResponseID. A subsequent Send with that ID is routed to the same connection.
+func (p *WSPool) Warmup(ctx context.Context, req *Request) (string, error) {
+	if req == nil {
+		return "", fmt.Errorf("request is nil")
+	}
+	warmupReq := req.Clone()
+	generate := false
+	warmupReq.Generate = &generate
+
+	stream, err := p.Send(ctx, warmupReq)
+	if err != nil {
+		return "", err
+	}
+
+	var responseID string
+	for stream.Next() {
+		if e, ok := stream.Event().(streaming.ResponseCreated); ok {
+			responseID = e.Response.ID
+		}
+	}
+
+	if err := stream.Err(); err != nil {
+		return "", err
+	}
+	if responseID == "" {
+		return "", fmt.Errorf("warmup response ID not found")
+	}
+
+	return responseID, nil
+}
+
+// Close closes all connections and stops reconnection attempts.
+// Pending Send calls waiting for a connection return an error.
+func (p *WSPool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.cancel()
+	var errs []error
+	for _, pc := range p.conns {
+		if pc.conn != nil {
+			errs = append(errs, pc.conn.Close())
+			pc.conn = nil
+		}
+	}
+	p.notify()
+	p.updateState()
+	p.mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// State returns the aggregated state of pooled connections.
+func (p *WSPool) State() WSState {
+	return p.state.Get()
+}
+
+// States returns a channel receiving changes of the aggregated state.
+func (p *WSPool) States() <-chan WSState {
+	return p.state.Chan()
+}
+
+// acquire picks a connection for the request, waiting for one to become available.
+// Returns the slot and its current connection.
+func (p *WSPool) acquire(ctx context.Context, req *Request) (*wsPoolConn, WSConn, error) {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, nil, fmt.Errorf("websocket pool is closed")
+		}
+		if pc := p.pick(req.PreviousResponseID); pc != nil {
+			pc.busy++
+			conn := pc.conn
+			p.mu.Unlock()
+			return pc, conn, nil
+		}
+		changed := p.changed
+		p.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+}
+
+// pick returns a connection for a turn, nil if none is available. Must be called with mu held.
+func (p *WSPool) pick(previousID string) *wsPoolConn {
+	if previousID != "" {
+		if pc, ok := p.affinity[previousID]; ok && pc.conn != nil {
+			return pc
+		}
+	}
+	for _, pc := range p.conns {
+		if pc.conn != nil && pc.busy == 0 && pc.conn.State() == WSStateOpen {
+			return pc
+		}
+	}
+	return nil
+}
+
+// track forwards events of the turn to the caller, remembering the produced response ID
+// for affinity and releasing the connection when the turn ends.
+// If ctx is done first, the server still generates the current turn, so its events are drained
+// until its terminal event or connection loss, then stopTurn stops the stream before releasing.
+func (p *WSPool) track(
+	ctx context.Context, pc *wsPoolConn, conn WSConn, req *Request, stream *streaming.StreamIterator,
+	stopTurn context.CancelFunc,
+) *streaming.StreamIterator {
+	out := make(chan any)
+	go func() {
+		defer close(out)
+
+		// a stream may contain multiple turns, each continuing the previous one
+		previousID := req.PreviousResponseID
+		canceled := false
+		for stream.Next() {
+			event := stream.Event()
+			if e, ok := event.(streaming.ResponseCreated); ok {
+				p.bind(pc, conn, previousID, e.Response.ID)
+				previousID = e.Response.ID
+			}
+			if !canceled {
+				select {
+				case out <- event:
+					continue
+				case <-ctx.Done():
+					canceled = true
+				}
+			}
+			if isTurnEnd(event) {
+				break
+			}
+		}
+
+		stopTurn()
+		err := stream.Err()
+		p.release(pc, conn, err)
+		if err != nil && !canceled {
+			select {
+			case out <- err:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return streaming.NewStreamIterator(ctx, out)
+}
+
+// isTurnEnd reports whether the event is the last one the server sends for a turn.
+func isTurnEnd(event any) bool {
+	switch event.(type) {
+	case streaming.ResponseCompleted,
+		streaming.ResponseFailed,
+		streaming.ResponseIncomplete,
+		streaming.Error,
+		streaming.WSError:
+		return true
+	default:
+		return false
+	}
+}
+
+// bind remembers that the response was produced on given connection.
+func (p *WSPool) bind(pc *wsPoolConn, conn WSConn, previousID, responseID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if pc.conn != conn || responseID == "" {
+		return
+	}
+	// the chain moved on, only its latest response needs to be remembered
+	if previousID != "" && p.affinity[previousID] == pc {
+		delete(p.affinity, previousID)
+	}
+	p.affinity[responseID] = pc
+	pc.ids = append(pc.ids, responseID)
+	if len(pc.ids) > wsPoolAffinityLimit {
+		if p.affinity[pc.ids[0]] == pc {
+			delete(p.affinity, pc.ids[0])
+		}
+		pc.ids = pc.ids[1:]
+	}
+}
+
+// release marks the end of a turn on the connection.
+// The connection is replaced if it's closed. Turns of a connection that was already
+// swapped out don't count towards the slot.
+func (p *WSPool) release(pc *wsPoolConn, conn WSConn, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if pc.conn != conn {
+		return
+	}
+	pc.busy--
+	if errors.Is(err, ErrWSConnClosed) || conn.State() == WSStateClosed {
+		p.replace(pc)
+	}
+	p.notify()
+}
+
+// watch follows state changes of the connection, replacing it once it's closed.
+func (p *WSPool) watch(pc *wsPoolConn, conn WSConn) {
+	for state := range conn.States() {
+		p.mu.Lock()
+		if pc.conn == conn && state == WSStateClosed {
+			p.replace(pc)
+		}
+		p.notify()
+		p.updateState()
+		p.mu.Unlock()
+	}
+}
+
+// replace drops the connection of the slot and starts reconnecting it. Must be called with mu held.
+func (p *WSPool) replace(pc *wsPoolConn) {
+	if p.closed || pc.conn == nil {
+		return
+	}
+	pc.conn.Close()
+	pc.conn = nil
+	pc.busy = 0
+	for _, id := range pc.ids {
+		if p.affinity[id] == pc {
+			delete(p.affinity, id)
+		}
+	}
+	pc.ids = nil
+	p.updateState()
+	go p.reconnect(pc)
+}
+
+// updateState recalculates the aggregated state. Must be called with mu held.
+func (p *WSPool) updateState() {
+	if p.closed {
+		p.state.Set(WSStateClosed, true)
+		return
+	}
+
+	open := 0
+	for _, pc := range p.conns {
+		if pc.conn != nil && pc.conn.State() == WSStateOpen {
+			open++
+		}
+	}
+	switch open {
+	case len(p.conns):
+		p.state.Set(WSStateOpen, false)
+	case 0:
+		p.state.Set(WSStateConnecting, false)
+	default:
+		p.state.Set(WSStateDegraded, false)
+	}
+}
+
+// reconnect opens a new connection for the slot, retrying with exponential backoff
+// until it succeeds or the pool is closed.
+func (p *WSPool) reconnect(pc *wsPoolConn) {
+	backoff := p.opts.MinBackoff
+	for {
+		select {
+		case <-time.After(backoff):
+		case <-p.ctx.Done():
+			return
+		}
+
+		conn, err := p.svc.WebSocket(p.ctx)
+		if err == nil {
+			p.mu.Lock()
+			if p.closed {
+				p.mu.Unlock()
+				conn.Close()
+				return
+			}
+			pc.conn = conn
+			pc.busy = 0
+			go p.watch(pc, conn)
+			p.notify()
+			p.updateState()
+			p.mu.Unlock()
+			return
+		}
+
+		backoff = min(backoff*2, p.opts.MaxBackoff)
+	}
+}
+
+// notify wakes up all Send calls waiting for a connection. Must be called with mu held.
+func (p *WSPool) notify() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
@@ -0,0 +1,287 @@
+package responses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	openai "github.com/unkn0wncode/openai/internal"
+	"github.com/unkn0wncode/openai/responses/streaming"
+)
+
+// fakeWSService opens fakeWSConn connections, other Service methods are not implemented.
+type fakeWSService struct {
+	Service
+
+	mu    sync.Mutex
+	conns []*fakeWSConn
+	hold  chan struct{} // if set, turns wait for it to be closed before completing
+}
+
+func (s *fakeWSService) WebSocket(context.Context) (WSConn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn := &fakeWSConn{
+		id:    len(s.conns),
+		hold:  s.hold,
+		state: openai.NewStateNotifier(WSStateOpen),
+	}
+	s.conns = append(s.conns, conn)
+	return conn, nil
+}
+
+func (s *fakeWSService) dials() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// fakeWSConn replies to each request with response.created and response.completed events.
+type fakeWSConn struct {
+	id    int
+	hold  chan struct{}
+	state *openai.StateNotifier[WSState]
+
+	mu     sync.Mutex
+	sent   []*Request
+	broken bool
+	closed bool
+}
+
+func (c *fakeWSConn) Send(ctx context.Context, req *Request) (*streaming.StreamIterator, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.broken || c.closed {
+		return nil, ErrWSConnClosed
+	}
+	c.sent = append(c.sent, req)
+
+	events := make(chan any, 2)
+	id := fmt.Sprintf("resp_%d_%d", c.id, len(c.sent))
+	go func() {
+		defer close(events)
+		events <- streaming.ResponseCreated{Response: streaming.Response{ID: id}}
+		if c.hold != nil {
+			<-c.hold
+		}
+		events <- streaming.ResponseCompleted{Response: streaming.Response{ID: id}}
+	}()
+	return streaming.NewStreamIterator(ctx, events), nil
+}
+
+func (c *fakeWSConn) SendAuto(ctx context.Context, req *Request) (*streaming.StreamIterator, error) {
+	return c.Send(ctx, req)
+}
+
+func (c *fakeWSConn) Warmup(context.Context, *Request) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (c *fakeWSConn) State() WSState {
+	return c.state.Get()
+}
+
+func (c *fakeWSConn) States() <-chan WSState {
+	return c.state.Chan()
+}
+
+func (c *fakeWSConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	c.state.Set(WSStateClosed, true)
+	return nil
+}
+
+func (c *fakeWSConn) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *fakeWSConn) sentCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.sent)
+}
+
+// responseID reads the stream to the end and returns the ID of the created response.
+func responseID(t *testing.T, stream *streaming.StreamIterator) string {
+	t.Helper()
+	var id string
+	for stream.Next() {
+		if e, ok := stream.Event().(streaming.ResponseCreated); ok {
+			id = e.Response.ID
+		}
+	}
+	require.NoError(t, stream.Err())
+	return id
+}
+
+func TestWSPool(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ConcurrentTurns", func(t *testing.T) {
+		t.Parallel()
+		svc := &fakeWSService{hold: make(chan struct{})}
+		pool, err := NewWSPool(t.Context(), svc, WSPoolOptions{Size: 2})
+		require.NoError(t, err)
+		defer pool.Close()
+
+		first, err := pool.Send(t.Context(), &Request{Input: "1"})
+		require.NoError(t, err)
+		second, err := pool.Send(t.Context(), &Request{Input: "2"})
+		require.NoError(t, err)
+		for _, conn := range svc.conns {
+			require.Equal(t, 1, conn.sentCount())
+		}
+
+		// both connections are busy, so the third turn has to wait
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+		_, err = pool.Send(ctx, &Request{Input: "3"})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		close(svc.hold)
+		require.NotEmpty(t, responseID(t, first))
+		require.NotEmpty(t, responseID(t, second))
+
+		third, err := pool.Send(t.Context(), &Request{Input: "3"})
+		require.NoError(t, err)
+		require.NotEmpty(t, responseID(t, third))
+	})
+
+	t.Run("Affinity", func(t *testing.T) {
+		t.Parallel()
+		svc := &fakeWSService{}
+		pool, err := NewWSPool(t.Context(), svc, WSPoolOptions{Size: 3})
+		require.NoError(t, err)
+		defer pool.Close()
+
+		id, err := pool.Warmup(t.Context(), &Request{Input: "warmup"})
+		require.NoError(t, err)
+
+		for range 3 {
+			stream, err := pool.Send(t.Context(), &Request{Input: "next", PreviousResponseID: id})
+			require.NoError(t, err)
+			id = responseID(t, stream)
+		}
+
+		var counts []int
+		for _, conn := range svc.conns {
+			counts = append(counts, conn.sentCount())
+		}
+		require.ElementsMatch(t, []int{4, 0, 0}, counts)
+	})
+
+	t.Run("Reconnect", func(t *testing.T) {
+		t.Parallel()
+		svc := &fakeWSService{}
+		pool, err := NewWSPool(t.Context(), svc, WSPoolOptions{Size: 2, MinBackoff: time.Millisecond})
+		require.NoError(t, err)
+		defer pool.Close()
+
+		for _, conn := range svc.conns {
+			conn.mu.Lock()
+			conn.broken = true
+			conn.mu.Unlock()
+		}
+
+		// all connections fail, the send waits for a reconnected one
+		stream, err := pool.Send(t.Context(), &Request{Input: "hi"})
+		require.NoError(t, err)
+		require.NotEmpty(t, responseID(t, stream))
+
+		require.Eventually(t, func() bool { return svc.dials() == 4 }, time.Second, time.Millisecond)
+		require.True(t, svc.conns[0].isClosed())
+		require.True(t, svc.conns[1].isClosed())
+	})
+
+	t.Run("State", func(t *testing.T) {
+		t.Parallel()
+		svc := &fakeWSService{}
+		pool, err := NewWSPool(t.Context(), svc, WSPoolOptions{Size: 2, MinBackoff: time.Hour})
+		require.NoError(t, err)
+		require.Equal(t, WSStateOpen, pool.State())
+
+		// a connection closed on its own is replaced, the pool is degraded until then
+		svc.conns[0].Close()
+		require.Eventually(t, func() bool { return pool.State() == WSStateDegraded }, time.Second, time.Millisecond)
+
+		require.NoError(t, pool.Close())
+		require.Equal(t, WSStateClosed, pool.State())
+		var last WSState
+		for state := range pool.States() {
+			last = state
+		}
+		require.Equal(t, WSStateClosed, last)
+	})
+
+	t.Run("CanceledTurnKeepsConnection", func(t *testing.T) {
+		t.Parallel()
+		svc := &fakeWSService{hold: make(chan struct{})}
+		pool, err := NewWSPool(t.Context(), svc, WSPoolOptions{Size: 1})
+		require.NoError(t, err)
+		defer pool.Close()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		stream, err := pool.Send(ctx, &Request{Input: "1"})
+		require.NoError(t, err)
+		require.True(t, stream.Next())
+		cancel()
+
+		// the server is still generating the canceled turn
+		waitCtx, waitCancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer waitCancel()
+		_, err = pool.Send(waitCtx, &Request{Input: "2"})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		close(svc.hold)
+		next, err := pool.Send(t.Context(), &Request{Input: "2"})
+		require.NoError(t, err)
+		require.NotEmpty(t, responseID(t, next))
+	})
+
+	t.Run("SwappedConnectionIsIdle", func(t *testing.T) {
+		t.Parallel()
+		svc := &fakeWSService{hold: make(chan struct{})}
+		pool, err := NewWSPool(t.Context(), svc, WSPoolOptions{Size: 1, MinBackoff: time.Millisecond})
+		require.NoError(t, err)
+		defer pool.Close()
+
+		old, err := pool.Send(t.Context(), &Request{Input: "1"})
+		require.NoError(t, err)
+
+		// turns of the replaced connection don't keep the new one busy
+		svc.conns[0].Close()
+		require.Eventually(t, func() bool { return pool.State() == WSStateOpen && svc.dials() == 2 },
+			time.Second, time.Millisecond)
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		defer cancel()
+		current, err := pool.Send(ctx, &Request{Input: "2"})
+		require.NoError(t, err)
+
+		// and don't free it when they end
+		close(svc.hold)
+		require.NotEmpty(t, responseID(t, old))
+		require.NotEmpty(t, responseID(t, current))
+		next, err := pool.Send(ctx, &Request{Input: "3"})
+		require.NoError(t, err)
+		require.NotEmpty(t, responseID(t, next))
+		require.Equal(t, 2, svc.conns[1].sentCount())
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		t.Parallel()
+		pool, err := NewWSPool(t.Context(), &fakeWSService{}, WSPoolOptions{Size: 1})
+		require.NoError(t, err)
+		require.NoError(t, pool.Close())
+
+		_, err = pool.Send(t.Context(), &Request{Input: "hi"})
+		require.EqualError(t, err, "websocket pool is closed")
+	})
+}
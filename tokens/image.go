@@ -0,0 +1,127 @@
+package tokens
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	_ "image/gif"  // register decoder for sizes of data URLs
+	_ "image/jpeg" // register decoder for sizes of data URLs
+	_ "image/png"  // register decoder for sizes of data URLs
+	"math"
+	"strings"
+)
+
+// Sizes assumed for images whose size can't be determined, like images given by URL or file ID.
+const (
+	DefaultImageWidth  = 1024
+	DefaultImageHeight = 1024
+)
+
+// imageCost describes how a model charges for input images.
+// Tile-based models charge base tokens plus tokens per 512px tile of the image scaled
+// to fit 2048x2048 and 768px on the shortest side, or only base tokens for low detail.
+// Patch-based models charge for 32px patches, up to 1536, multiplied by a multiplier.
+type imageCost struct {
+	prefix     string
+	base       int
+	perTile    int
+	multiplier float64 // set for patch-based models
+}
+
+// imageCosts lists image costs by model name prefix, more specific prefixes go first.
+// Models that match no prefix are priced like GPT-4o.
+var imageCosts = []imageCost{
+	{prefix: "gpt-4o-mini", base: 2833, perTile: 5667},
+	{prefix: "gpt-4.1-mini", multiplier: 1.62},
+	{prefix: "gpt-4.1-nano", multiplier: 2.46},
+	{prefix: "gpt-5-mini", multiplier: 1.62},
+	{prefix: "gpt-5-nano", multiplier: 2.46},
+	{prefix: "o4-mini", multiplier: 1.72},
+	{prefix: "gpt-5", base: 70, perTile: 140},
+	{prefix: "o1", base: 75, perTile: 150},
+	{prefix: "o3", base: 75, perTile: 150},
+	{prefix: "computer-use", base: 65, perTile: 129},
+	{prefix: "", base: 85, perTile: 170},
+}
+
+// Image returns the estimated number of tokens of an image with given size and detail
+// ("low", "high" or "auto") for the model. Detail "auto" is counted as "high".
+func Image(model string, width, height int, detail string) int {
+	if width <= 0 || height <= 0 {
+		width, height = DefaultImageWidth, DefaultImageHeight
+	}
+
+	var cost imageCost
+	for _, c := range imageCosts {
+		if strings.HasPrefix(model, c.prefix) {
+			cost = c
+			break
+		}
+	}
+
+	if cost.multiplier > 0 {
+		return int(math.Ceil(float64(imagePatches(width, height)) * cost.multiplier))
+	}
+
+	if detail == "low" {
+		return cost.base
+	}
+
+	w, h := float64(width), float64(height)
+	if scale := 2048 / max(w, h); scale < 1 {
+		w, h = w*scale, h*scale
+	}
+	if scale := 768 / min(w, h); scale < 1 {
+		w, h = w*scale, h*scale
+	}
+	tiles := int(math.Ceil(w/512) * math.Ceil(h/512))
+	return cost.base + cost.perTile*tiles
+}
+
+// maxImagePatches is the maximum number of 32px patches an image is scaled down to.
+const maxImagePatches = 1536
+
+// imagePatches returns the number of 32px patches that cover the image after scaling.
+func imagePatches(width, height int) int {
+	w, h := float64(width), float64(height)
+	patches := math.Ceil(w/32) * math.Ceil(h/32)
+	if patches <= maxImagePatches {
+		return int(patches)
+	}
+
+	// scale down to fit the patch budget, then shrink to a whole number of patches on one side
+	scale := math.Sqrt(32 * 32 * maxImagePatches / (w * h))
+	scale *= min(math.Floor(w*scale/32)/(w*scale/32), math.Floor(h*scale/32)/(h*scale/32))
+	return int(min(math.Ceil(w*scale/32)*math.Ceil(h*scale/32), maxImagePatches))
+}
+
+// ImageURL returns the estimated number of tokens of an image given by URL.
+// The size is read from base64 data URLs of PNG, JPEG and GIF images,
+// other images are assumed to be DefaultImageWidth x DefaultImageHeight.
+func ImageURL(model, url, detail string) int {
+	width, height := imageSize(url)
+	return Image(model, width, height, detail)
+}
+
+// imageSize returns the size of an image from a base64 data URL, or zeros if it's unknown.
+func imageSize(url string) (width, height int) {
+	if !strings.HasPrefix(url, "data:image/") {
+		return 0, 0
+	}
+
+	_, data, ok := strings.Cut(url, ";base64,")
+	if !ok {
+		return 0, 0
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return 0, 0
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(decoded))
+	if err != nil {
+		return 0, 0
+	}
+	return config.Width, config.Height
+}
@@ -0,0 +1,165 @@
+package tokens
+
+import (
+	"cmp"
+	"encoding/json"
+
+	"github.com/unkn0wncode/openai/chat"
+	"github.com/unkn0wncode/openai/content/input"
+	"github.com/unkn0wncode/openai/content/output"
+	"github.com/unkn0wncode/openai/responses"
+	"github.com/unkn0wncode/openai/roles"
+)
+
+// Chat returns the estimated number of tokens of chat messages, including the reply priming.
+func Chat(model string, msgs []chat.Message) int {
+	total := ReplyOverhead
+	for _, msg := range msgs {
+		total += MessageOverhead + Count(model, msg.Role) + Count(model, msg.Content) + Count(model, msg.Refusal)
+		if msg.Name != "" {
+			total += NameOverhead + Count(model, msg.Name)
+		}
+		for _, img := range msg.Images {
+			// chat.Image defaults to low detail
+			total += ImageURL(model, img.URL, cmp.Or(img.Detail, "low"))
+		}
+		total += Count(model, msg.FunctionCall.Name) + Count(model, msg.FunctionCall.Arguments)
+		for _, call := range msg.ToolCalls {
+			total += Count(model, call.ID)
+			if call.Function != nil {
+				total += Count(model, call.Function.Name) + Count(model, call.Function.Arguments)
+			}
+		}
+		total += Count(model, msg.ToolCallID)
+	}
+	return total
+}
+
+// ChatRequest returns the estimated number of tokens of the request input with given
+// tool definitions, see Tools.
+func ChatRequest(req *chat.Request, toolDefs ...any) int {
+	return Chat(req.Model, req.Messages) + Tools(req.Model, toolDefs...)
+}
+
+// Tools returns the estimated number of tokens of tool definitions, like tools.Tool or
+// tools.FunctionCall, counted as their JSON.
+func Tools(model string, toolDefs ...any) int {
+	total := 0
+	for _, def := range toolDefs {
+		b, err := json.Marshal(def)
+		if err != nil {
+			continue
+		}
+		total += Count(model, string(b))
+	}
+	return total
+}
+
+// Responses returns the estimated number of tokens of Responses input items,
+// including the reply priming. Items can be output.Any, types of the output package
+// or any other values that marshal to JSON items.
+// Encrypted content of reasoning items is not counted.
+func Responses(model string, items []any) int {
+	total := ReplyOverhead
+	for _, item := range items {
+		total += itemTokens(model, item)
+	}
+	return total
+}
+
+// ResponsesRequest returns the estimated number of tokens of the request input with
+// instructions and given tool definitions, see Tools.
+// Returns an error if the input can't be converted to items.
+func ResponsesRequest(req *responses.Request, toolDefs ...any) (int, error) {
+	items, err := responses.InputItems(req.Input)
+	if err != nil {
+		return 0, err
+	}
+
+	anyItems := make([]any, len(items))
+	for i, item := range items {
+		anyItems[i] = item
+	}
+
+	total := Responses(req.Model, anyItems) + Tools(req.Model, toolDefs...)
+	if req.Instructions != "" {
+		total += MessageOverhead + Count(req.Model, roles.Developer) + Count(req.Model, req.Instructions)
+	}
+	return total, nil
+}
+
+// itemTokens returns the estimated number of tokens of an input item.
+func itemTokens(model string, item any) int {
+	parsed := item
+	switch v := item.(type) {
+	case output.Any:
+		if p, err := v.Unmarshal(); err == nil {
+			parsed = p
+		}
+	case *output.Any:
+		if p, err := v.Unmarshal(); err == nil {
+			parsed = p
+		}
+	}
+
+	switch it := parsed.(type) {
+	case output.Message:
+		return MessageOverhead + Count(model, cmp.Or(it.Role, roles.User)) + contentTokens(model, it.Content)
+	case output.FunctionCall:
+		return MessageOverhead + Count(model, it.CallID) + Count(model, it.Name) + Count(model, it.Arguments)
+	case output.FunctionCallOutput:
+		return MessageOverhead + Count(model, it.CallID) + Count(model, it.Output)
+	case output.CustomToolCall:
+		return MessageOverhead + Count(model, it.Name) + Count(model, it.Input)
+	case output.CustomToolCallOutput:
+		return MessageOverhead + Count(model, it.CallID) + Count(model, it.Output)
+	case output.Reasoning:
+		total := 0
+		for _, s := range it.Summary {
+			total += Count(model, s.Text)
+		}
+		return total
+	default:
+		return MessageOverhead + jsonTokens(model, item)
+	}
+}
+
+// contentTokens returns the estimated number of tokens of message content.
+// Files are not counted because their content is extracted on the server.
+func contentTokens(model string, content any) int {
+	switch c := content.(type) {
+	case string:
+		return Count(model, c)
+	case []any:
+		total := 0
+		for _, part := range c {
+			switch p := part.(type) {
+			case string:
+				total += Count(model, p)
+			case input.InputText:
+				total += Count(model, p.Text)
+			case output.OutputText:
+				total += Count(model, p.Text)
+			case output.Refusal:
+				total += Count(model, p.Refusal)
+			case input.InputImage:
+				total += ImageURL(model, p.ImageURL, p.Detail)
+			case input.InputFile:
+			default:
+				total += jsonTokens(model, p)
+			}
+		}
+		return total
+	default:
+		return jsonTokens(model, content)
+	}
+}
+
+// jsonTokens returns the number of tokens of a value marshaled to JSON.
+func jsonTokens(model string, v any) int {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return Count(model, string(b))
+}
@@ -0,0 +1,107 @@
+// Package tokens estimates numbers of tokens in texts and requests of Chat and Responses APIs.
+//
+// Texts are tokenized with the encoding used by the model, BPE files of encodings are
+// embedded, so counting works offline. Requests are counted in the message format that
+// models see, with per-message overheads, estimated tokens of images and tool schemas.
+// Results are estimates: the exact format of tool schemas and some items is not public.
+package tokens
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+// Encodings used by OpenAI models.
+const (
+	O200KBase  = "o200k_base"  // GPT-4o, GPT-4.1, GPT-5, o-series and newer models
+	CL100KBase = "cl100k_base" // GPT-4, GPT-3.5 and embedding models
+	P50KBase   = "p50k_base"   // legacy Codex and text-davinci-002/003 models
+	R50KBase   = "r50k_base"   // legacy GPT-3 models
+)
+
+// Overheads of the chat message format.
+const (
+	MessageOverhead = 3 // tokens added to every message for its role and delimiters
+	NameOverhead    = 1 // tokens added to a message with a name
+	ReplyOverhead   = 3 // tokens priming the reply of the assistant
+)
+
+// encodingPrefixes maps model name prefixes to encodings, more specific prefixes go first.
+// Models that match no prefix use O200KBase.
+var encodingPrefixes = []struct {
+	prefix   string
+	encoding string
+}{
+	{"gpt-4o", O200KBase},
+	{"gpt-4.1", O200KBase},
+	{"gpt-4.5", O200KBase},
+	{"gpt-4", CL100KBase},
+	{"gpt-3.5", CL100KBase},
+	{"text-embedding-", CL100KBase},
+	{"davinci-002", CL100KBase},
+	{"babbage-002", CL100KBase},
+	{"text-davinci-002", P50KBase},
+	{"text-davinci-003", P50KBase},
+	{"code-", P50KBase},
+	{"text-", R50KBase},
+	{"davinci", R50KBase},
+	{"curie", R50KBase},
+	{"babbage", R50KBase},
+	{"ada", R50KBase},
+}
+
+// EncodingForModel returns the name of the encoding used by the model.
+func EncodingForModel(model string) string {
+	for _, p := range encodingPrefixes {
+		if strings.HasPrefix(model, p.prefix) {
+			return p.encoding
+		}
+	}
+	return O200KBase
+}
+
+var (
+	encodersMux sync.Mutex
+	encoders    = map[string]*tiktoken.Tiktoken{}
+	loaderOnce  sync.Once
+)
+
+// Encoder returns an encoder by encoding name. Encoders are loaded from embedded files once
+// and cached.
+func Encoder(encoding string) (*tiktoken.Tiktoken, error) {
+	loaderOnce.Do(func() {
+		tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+	})
+
+	encodersMux.Lock()
+	defer encodersMux.Unlock()
+
+	if enc, ok := encoders[encoding]; ok {
+		return enc, nil
+	}
+
+	enc, err := tiktoken.GetEncoding(encoding)
+	if err != nil {
+		return nil, err
+	}
+	encoders[encoding] = enc
+	return enc, nil
+}
+
+// Count returns the number of tokens in the text for the model.
+// Special tokens are counted as regular text.
+// If the encoder fails to load, the number is estimated as 4 characters per token.
+func Count(model, text string) int {
+	if text == "" {
+		return 0
+	}
+
+	enc, err := Encoder(EncodingForModel(model))
+	if err != nil {
+		return (len(text) + 3) / 4
+	}
+	return len(enc.EncodeOrdinary(text))
+}
@@ -0,0 +1,121 @@
+package tokens
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/unkn0wncode/openai/chat"
+	"github.com/unkn0wncode/openai/content/input"
+	"github.com/unkn0wncode/openai/content/output"
+	"github.com/unkn0wncode/openai/responses"
+	"github.com/unkn0wncode/openai/roles"
+	"github.com/unkn0wncode/openai/tools"
+)
+
+func TestEncodingForModel(t *testing.T) {
+	t.Parallel()
+
+	for model, encoding := range map[string]string{
+		"gpt-4o-mini":            O200KBase,
+		"gpt-4.1-nano":           O200KBase,
+		"gpt-5.4":                O200KBase,
+		"o3-mini":                O200KBase,
+		"gpt-4-turbo":            CL100KBase,
+		"gpt-3.5-turbo-instruct": CL100KBase,
+		"text-embedding-3-small": CL100KBase,
+		"text-davinci-003":       P50KBase,
+		"text-curie-001":         R50KBase,
+		"unknown-model":          O200KBase,
+	} {
+		require.Equal(t, encoding, EncodingForModel(model), model)
+	}
+}
+
+func TestCount(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 6, Count("gpt-4", "tiktoken is great!"))
+	require.Equal(t, 2, Count("gpt-5", "hello world"))
+	require.Zero(t, Count("gpt-5", ""))
+	// special tokens are regular text
+	require.Positive(t, Count("gpt-5", "<|endoftext|>"))
+}
+
+func TestImage(t *testing.T) {
+	t.Parallel()
+
+	// examples from the vision guide
+	require.Equal(t, 765, Image("gpt-4o", 1024, 1024, "high"))
+	require.Equal(t, 1105, Image("gpt-4o", 2048, 4096, "auto"))
+	require.Equal(t, 85, Image("gpt-4o", 4096, 8192, "low"))
+	require.Equal(t, 1659, Image("gpt-4.1-mini", 1024, 1024, "low"))
+	require.Equal(t, 1452, imagePatches(1800, 2400))
+	require.Equal(t, 16*32, imagePatches(500, 1000))
+	require.Equal(t, Image("gpt-5", DefaultImageWidth, DefaultImageHeight, ""), Image("gpt-5", 0, 0, ""))
+
+	// size is read from data URLs
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 600, 300))))
+	url := "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+	require.Equal(t, 85+170*2, ImageURL("gpt-4o", url, "high"))
+	require.Equal(t, 765, ImageURL("gpt-4o", "https://example.com/a.png", "high"))
+}
+
+func TestChat(t *testing.T) {
+	t.Parallel()
+
+	msgs := []chat.Message{{Role: roles.User, Content: "hello world"}}
+	require.Equal(t, ReplyOverhead+MessageOverhead+1+2, Chat("gpt-5", msgs))
+
+	msgs[0].Images = []chat.Image{{URL: "https://example.com/a.png"}}
+	require.Equal(t, ReplyOverhead+MessageOverhead+1+2+70, Chat("gpt-5", msgs))
+
+	fn := tools.FunctionCall{Name: "get_time", Description: "Returns current time", ParamsSchema: tools.EmptyParamsSchema}
+	req := &chat.Request{Model: "gpt-5", Messages: msgs}
+	require.Equal(t, Chat("gpt-5", msgs)+Tools("gpt-5", fn), ChatRequest(req, fn))
+	require.Positive(t, Tools("gpt-5", fn))
+}
+
+func TestResponses(t *testing.T) {
+	t.Parallel()
+
+	req := &responses.Request{Model: "gpt-5", Input: "hello world"}
+	n, err := ResponsesRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, ReplyOverhead+MessageOverhead+1+2, n)
+
+	req.Instructions = "hello world"
+	n, err = ResponsesRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, ReplyOverhead+2*(MessageOverhead+1+2), n)
+
+	items := []any{
+		output.Message{Role: roles.User, Content: []any{
+			input.InputText{Text: "hello world"},
+			input.InputImage{ImageURL: "https://example.com/a.png", Detail: "low"},
+		}},
+		output.FunctionCall{CallID: "call_1", Name: "f", Arguments: "{}"},
+		output.FunctionCallOutput{CallID: "call_1", Output: "hello world"},
+		output.Reasoning{Summary: []output.ReasoningSummary{{Text: "hello world"}}},
+	}
+	direct := Responses("gpt-5", items)
+	require.Equal(t,
+		ReplyOverhead+
+			MessageOverhead+1+2+70+
+			MessageOverhead+Count("gpt-5", "call_1")+1+1+
+			MessageOverhead+Count("gpt-5", "call_1")+2+
+			2,
+		direct,
+	)
+
+	// items parsed from JSON are counted the same
+	parsed, err := responses.InputItems(items)
+	require.NoError(t, err)
+	n, err = ResponsesRequest(&responses.Request{Model: "gpt-5", Input: parsed})
+	require.NoError(t, err)
+	require.Equal(t, direct, n)
+}
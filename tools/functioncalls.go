@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+
+	"github.com/unkn0wncode/openai/filters"
 )
 
 // Registry holds user-defined tools that AI can request to use.
@@ -166,7 +168,7 @@ type Tool struct {
 	VectorStoreIDs []string `json:"vector_store_ids,omitempty"`
 	// Max number of results for file_search type
 	MaxNumResults int `json:"max_num_results,omitempty"`
-	// Filters for selecting files, see documentation:
+	// Filters for selecting files, preferably built with the filters package, see documentation:
 	// https://platform.openai.com/docs/guides/tools-file-search#metadata-filtering
 	// https://platform.openai.com/docs/guides/retrieval#attribute-filtering
 	Filters any `json:"filters,omitempty"`
@@ -314,6 +316,9 @@ func (r *Registry) RegisterTool(tool Tool) error {
 		if len(tool.VectorStoreIDs) == 0 {
 			return fmt.Errorf("file_search tool '%s' requires vector_store_ids", tool.Name)
 		}
+		if err := filters.Validate(tool.Filters); err != nil {
+			return fmt.Errorf("file_search tool '%s' has invalid filters: %w", tool.Name, err)
+		}
 
 	case "web_search", "web_search_preview":
 		// Web search doesn't require additional fields
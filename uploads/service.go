@@ -0,0 +1,143 @@
+// Package uploads provides a wrapper for the OpenAI Uploads API, which uploads large files in parts.
+package uploads
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/unkn0wncode/openai/files"
+)
+
+// Limits of the Uploads API.
+const (
+	MaxPartSize   = 64 << 20 // 64 MB
+	MaxUploadSize = 8 << 30  // 8 GB
+	// Expiration is the time after creation when a pending upload expires.
+	Expiration = time.Hour
+)
+
+// Statuses of uploads.
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+)
+
+// Service is the service layer for OpenAI Uploads API.
+type Service interface {
+	// Create creates an upload session that accepts parts.
+	Create(req *CreateRequest) (*Upload, error)
+
+	// AddPart uploads a part of the upload, up to MaxPartSize. The content is streamed
+	// without buffering and the request is not retried.
+	// ctx controls cancellation of the part upload.
+	AddPart(ctx context.Context, uploadID string, data io.Reader) (*Part, error)
+
+	// Complete completes the upload with parts in given order and returns the upload
+	// with the created file. md5 is an optional hex checksum of the whole content.
+	Complete(uploadID string, partIDs []string, md5 string) (*Upload, error)
+
+	// Cancel cancels the upload, no parts can be added after it.
+	Cancel(uploadID string) (*Upload, error)
+
+	// UploadFile uploads the content in parts concurrently, retrying failed parts,
+	// and completes the upload with its MD5 checksum. Returns the created file.
+	// ctx controls cancellation, a cancelled upload can be resumed with FileRequest.Resume.
+	UploadFile(ctx context.Context, content io.ReaderAt, req *FileRequest) (*files.File, error)
+}
+
+// CreateRequest contains parameters of a new upload.
+type CreateRequest struct {
+	// required
+	Filename string `json:"filename"`
+	Purpose  string `json:"purpose"` // one of files.Purpose* constants
+	Bytes    int64  `json:"bytes"`   // size of the whole file
+	MimeType string `json:"mime_type"`
+
+	// optional
+	// ExpiresAfter sets expiration of the created file after its creation, from 1 hour to 30 days.
+	ExpiresAfter time.Duration `json:"-"`
+}
+
+// Upload is an upload object returned by the API.
+type Upload struct {
+	ID        string      `json:"id"`
+	Object    string      `json:"object"` // always "upload"
+	Bytes     int64       `json:"bytes"`
+	CreatedAt int64       `json:"created_at"` // Unix timestamp
+	ExpiresAt int64       `json:"expires_at"` // Unix timestamp
+	Filename  string      `json:"filename"`
+	Purpose   string      `json:"purpose"`
+	Status    string      `json:"status"` // one of Status* constants
+	File      *files.File `json:"file"`   // created file, set when completed
+}
+
+// Part is a part of an upload.
+type Part struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`     // always "upload.part"
+	CreatedAt int64  `json:"created_at"` // Unix timestamp
+	UploadID  string `json:"upload_id"`
+}
+
+// FileRequest configures uploading of a file in parts with Service.UploadFile.
+type FileRequest struct {
+	// required
+	Filename string
+	Purpose  string // one of files.Purpose* constants
+	MimeType string
+	Size     int64 // size of the content
+
+	// optional
+	// ExpiresAfter sets expiration of the created file after its creation, from 1 hour to 30 days.
+	ExpiresAfter time.Duration
+	// PartSize is the size of parts, default and maximum is MaxPartSize.
+	// Parts are sent without the overall Timeout of HTTPClient, use ctx to limit the upload.
+	PartSize int64
+	// Concurrency is the number of parts uploaded at once, default is 4.
+	Concurrency int
+	// PartAttempts is the number of attempts to upload each part, default is taken from
+	// HTTPClient.RequestAttempts. Only rate limits, server and network errors are retried,
+	// after HTTPClient.RetryInterval doubled with each attempt or the delay asked by the server.
+	PartAttempts int
+
+	// OnProgress is called after each uploaded part.
+	OnProgress func(Progress)
+	// OnState is called with the state of the upload after it's created and after each part.
+	// The state can be persisted to resume the upload later.
+	OnState func(State)
+	// Resume continues a partially uploaded session instead of creating a new one.
+	// Parts uploaded before are skipped, the content must be the same.
+	Resume *State
+}
+
+// Progress describes progress of an upload.
+type Progress struct {
+	Bytes      int64 // uploaded bytes
+	TotalBytes int64
+	Parts      int // uploaded parts
+	TotalParts int
+}
+
+// State is the state of an upload that can be persisted, e.g. as JSON, to resume it later
+// with FileRequest.Resume before the upload expires.
+type State struct {
+	UploadID  string    `json:"upload_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Size      int64     `json:"size"`
+	PartSize  int64     `json:"part_size"`
+	// PartIDs are IDs of uploaded parts by their index, empty for parts not uploaded yet.
+	PartIDs []string `json:"part_ids"`
+}
+
+// Done reports whether all parts are uploaded.
+func (s *State) Done() bool {
+	for _, id := range s.PartIDs {
+		if id == "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,363 @@
+// Package vectorstores provides a wrapper for the OpenAI Vector Stores API, which indexes files
+// for the file_search tool and direct search.
+package vectorstores
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/unkn0wncode/openai/content/output"
+)
+
+// Statuses of vector stores.
+const (
+	StatusExpired    = "expired"
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+)
+
+// Statuses of files and file batches.
+const (
+	FileStatusInProgress = "in_progress"
+	FileStatusCompleted  = "completed"
+	FileStatusCancelled  = "cancelled"
+	FileStatusFailed     = "failed"
+)
+
+// Limits of file attributes.
+const (
+	MaxAttributes           = 16
+	MaxAttributeKeyLength   = 64
+	MaxAttributeValueLength = 512
+)
+
+// Service is the service layer for OpenAI Vector Stores API.
+type Service interface {
+	// Create creates a vector store, optionally with files.
+	Create(req *CreateRequest) (*VectorStore, error)
+
+	// Retrieve returns a vector store by ID.
+	Retrieve(id string) (*VectorStore, error)
+
+	// Update changes name, expiration or metadata of a vector store.
+	Update(id string, req *UpdateRequest) (*VectorStore, error)
+
+	// Delete deletes a vector store, files themselves are not deleted.
+	Delete(id string) error
+
+	// List returns a page of vector stores.
+	List(opts *ListOptions) (*List, error)
+
+	// AddFile attaches an uploaded file to a vector store, the file is ingested asynchronously.
+	AddFile(storeID string, req *FileRequest) (*File, error)
+
+	// RetrieveFile returns a file of a vector store.
+	RetrieveFile(storeID, fileID string) (*File, error)
+
+	// UpdateFileAttributes replaces attributes of a file used for filtering in search.
+	UpdateFileAttributes(storeID, fileID string, attributes map[string]any) (*File, error)
+
+	// ListFiles returns a page of files of a vector store.
+	ListFiles(storeID string, opts *FileListOptions) (*FileList, error)
+
+	// RemoveFile detaches a file from a vector store, the file itself is not deleted.
+	RemoveFile(storeID, fileID string) error
+
+	// PollFile fetches the file until its ingestion is finished: completed, failed or cancelled.
+	// ctx controls cancellation, interval is time to wait between polls.
+	PollFile(ctx context.Context, storeID, fileID string, interval time.Duration) (*File, error)
+
+	// CreateFileBatch attaches multiple uploaded files to a vector store at once.
+	CreateFileBatch(storeID string, req *FileBatchRequest) (*FileBatch, error)
+
+	// RetrieveFileBatch returns a file batch of a vector store.
+	RetrieveFileBatch(storeID, batchID string) (*FileBatch, error)
+
+	// CancelFileBatch cancels ingestion of files of a batch.
+	CancelFileBatch(storeID, batchID string) (*FileBatch, error)
+
+	// PollFileBatch fetches the file batch until its ingestion is finished: completed, failed or cancelled.
+	// ctx controls cancellation, interval is time to wait between polls.
+	PollFileBatch(ctx context.Context, storeID, batchID string, interval time.Duration) (*FileBatch, error)
+
+	// Search searches a vector store for chunks relevant to the query.
+	Search(storeID string, req *SearchRequest) (*SearchResults, error)
+}
+
+// VectorStore is a vector store object returned by the API.
+type VectorStore struct {
+	ID           string            `json:"id"`
+	Object       string            `json:"object"`     // always "vector_store"
+	CreatedAt    int64             `json:"created_at"` // Unix timestamp
+	Name         string            `json:"name"`
+	UsageBytes   int64             `json:"usage_bytes"`
+	FileCounts   FileCounts        `json:"file_counts"`
+	Status       string            `json:"status"` // one of Status* constants
+	ExpiresAfter *ExpiresAfter     `json:"expires_after"`
+	ExpiresAt    int64             `json:"expires_at"`     // Unix timestamp, zero if it doesn't expire
+	LastActiveAt int64             `json:"last_active_at"` // Unix timestamp
+	Metadata     map[string]string `json:"metadata"`
+}
+
+// FileCounts contains numbers of files by status.
+type FileCounts struct {
+	InProgress int `json:"in_progress"`
+	Completed  int `json:"completed"`
+	Failed     int `json:"failed"`
+	Cancelled  int `json:"cancelled"`
+	Total      int `json:"total"`
+}
+
+// ExpiresAfter is an expiration policy of a vector store.
+type ExpiresAfter struct {
+	Anchor string `json:"anchor"` // only "last_active_at" is supported
+	Days   int    `json:"days"`
+}
+
+// ChunkingStrategy describes how files are split into chunks.
+// Use AutoChunking or StaticChunking to create one.
+type ChunkingStrategy struct {
+	Type   string          `json:"type"` // "auto" or "static"
+	Static *StaticStrategy `json:"static,omitempty"`
+}
+
+// StaticStrategy contains parameters of static chunking.
+type StaticStrategy struct {
+	// MaxChunkSizeTokens is from 100 to 4096, default is 800.
+	MaxChunkSizeTokens int `json:"max_chunk_size_tokens"`
+	// ChunkOverlapTokens must not exceed half of MaxChunkSizeTokens, default is 400.
+	ChunkOverlapTokens int `json:"chunk_overlap_tokens"`
+}
+
+// AutoChunking returns the default chunking strategy.
+func AutoChunking() *ChunkingStrategy {
+	return &ChunkingStrategy{Type: "auto"}
+}
+
+// StaticChunking returns a chunking strategy with given chunk size and overlap in tokens.
+func StaticChunking(maxChunkSizeTokens, chunkOverlapTokens int) *ChunkingStrategy {
+	return &ChunkingStrategy{Type: "static", Static: &StaticStrategy{
+		MaxChunkSizeTokens: maxChunkSizeTokens,
+		ChunkOverlapTokens: chunkOverlapTokens,
+	}}
+}
+
+// Validate checks parameters of static chunking.
+func (s *ChunkingStrategy) Validate() error {
+	if s == nil || s.Type != "static" {
+		return nil
+	}
+	switch {
+	case s.Static == nil:
+		return fmt.Errorf("static chunking strategy has no parameters")
+	case s.Static.MaxChunkSizeTokens < 100 || s.Static.MaxChunkSizeTokens > 4096:
+		return fmt.Errorf("max chunk size must be from 100 to 4096 tokens, got %d", s.Static.MaxChunkSizeTokens)
+	case s.Static.ChunkOverlapTokens < 0 || s.Static.ChunkOverlapTokens > s.Static.MaxChunkSizeTokens/2:
+		return fmt.Errorf("chunk overlap must be from 0 to half of max chunk size, got %d", s.Static.ChunkOverlapTokens)
+	}
+	return nil
+}
+
+// CreateRequest contains parameters of a new vector store.
+type CreateRequest struct {
+	Name             string            `json:"name,omitempty"`
+	FileIDs          []string          `json:"file_ids,omitempty"`
+	ChunkingStrategy *ChunkingStrategy `json:"chunking_strategy,omitempty"` // default is auto
+	ExpiresAfter     *ExpiresAfter     `json:"expires_after,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// UpdateRequest contains changes of a vector store, empty fields are not changed.
+type UpdateRequest struct {
+	Name         string            `json:"name,omitempty"`
+	ExpiresAfter *ExpiresAfter     `json:"expires_after,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
+
+// ListOptions configures pagination when listing vector stores or files.
+type ListOptions struct {
+	// Limit is the number of objects per page, from 1 to 100, default is 20.
+	Limit int
+	// After and Before are object IDs to list objects after or before.
+	After  string
+	Before string
+	// Order is "asc" or "desc" by creation time, default is "desc".
+	Order string
+}
+
+// FileListOptions configures listing of files of a vector store.
+type FileListOptions struct {
+	ListOptions
+	// Filter selects files by status, one of FileStatus* constants.
+	Filter string
+}
+
+// List is a page of vector stores.
+type List struct {
+	Object  string        `json:"object"` // always "list"
+	Data    []VectorStore `json:"data"`
+	FirstID string        `json:"first_id"`
+	LastID  string        `json:"last_id"`
+	HasMore bool          `json:"has_more"`
+}
+
+// FileRequest attaches a file to a vector store.
+type FileRequest struct {
+	FileID string `json:"file_id"`
+	// Attributes are used for filtering in search, values are strings, numbers or booleans.
+	Attributes       map[string]any    `json:"attributes,omitempty"`
+	ChunkingStrategy *ChunkingStrategy `json:"chunking_strategy,omitempty"`
+}
+
+// File is a file of a vector store.
+type File struct {
+	ID               string            `json:"id"`
+	Object           string            `json:"object"` // always "vector_store.file"
+	UsageBytes       int64             `json:"usage_bytes"`
+	CreatedAt        int64             `json:"created_at"` // Unix timestamp
+	VectorStoreID    string            `json:"vector_store_id"`
+	Status           string            `json:"status"` // one of FileStatus* constants
+	LastError        *LastError        `json:"last_error"`
+	ChunkingStrategy *ChunkingStrategy `json:"chunking_strategy"`
+	Attributes       map[string]any    `json:"attributes"`
+}
+
+// LastError is the error of a failed file ingestion.
+type LastError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface.
+func (e *LastError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// FileList is a page of files of a vector store.
+type FileList struct {
+	Object  string `json:"object"` // always "list"
+	Data    []File `json:"data"`
+	FirstID string `json:"first_id"`
+	LastID  string `json:"last_id"`
+	HasMore bool   `json:"has_more"`
+}
+
+// FileBatchRequest attaches multiple files to a vector store.
+// Either FileIDs with shared Attributes and ChunkingStrategy, or Files with their own are used.
+type FileBatchRequest struct {
+	FileIDs          []string          `json:"file_ids,omitempty"`
+	Attributes       map[string]any    `json:"attributes,omitempty"`
+	ChunkingStrategy *ChunkingStrategy `json:"chunking_strategy,omitempty"`
+	Files            []FileRequest     `json:"files,omitempty"`
+}
+
+// FileBatch is a batch of files attached to a vector store.
+type FileBatch struct {
+	ID            string     `json:"id"`
+	Object        string     `json:"object"`     // always "vector_store.files_batch"
+	CreatedAt     int64      `json:"created_at"` // Unix timestamp
+	VectorStoreID string     `json:"vector_store_id"`
+	Status        string     `json:"status"` // one of FileStatus* constants
+	FileCounts    FileCounts `json:"file_counts"`
+}
+
+// SearchRequest contains parameters of a vector store search.
+type SearchRequest struct {
+	Query any `json:"query"` // string or []string
+	// Filters select files by attributes, preferably built with the filters package.
+	Filters        any             `json:"filters,omitempty"`
+	MaxNumResults  int             `json:"max_num_results,omitempty"` // from 1 to 50, default is 10
+	RankingOptions *RankingOptions `json:"ranking_options,omitempty"`
+	RewriteQuery   bool            `json:"rewrite_query,omitempty"`
+}
+
+// RankingOptions configure ranking of search results.
+type RankingOptions struct {
+	Ranker         string  `json:"ranker,omitempty"` // "auto" or a ranker version
+	ScoreThreshold float64 `json:"score_threshold,omitempty"`
+}
+
+// SearchResults is a page of search results.
+type SearchResults struct {
+	Object      string         `json:"object"`       // always "vector_store.search_results.page"
+	SearchQuery any            `json:"search_query"` // queries used, possibly rewritten
+	Data        []SearchResult `json:"data"`
+	HasMore     bool           `json:"has_more"`
+	NextPage    string         `json:"next_page"`
+}
+
+// SearchResult is a search result of a file.
+type SearchResult struct {
+	FileID     string          `json:"file_id"`
+	Filename   string          `json:"filename"`
+	Score      float64         `json:"score"` // from 0 to 1
+	Attributes map[string]any  `json:"attributes"`
+	Content    []SearchContent `json:"content"`
+}
+
+// SearchContent is a matched chunk of a file.
+type SearchContent struct {
+	Type string `json:"type"` // always "text"
+	Text string `json:"text"`
+}
+
+// Text returns texts of matched chunks joined with newlines.
+func (r SearchResult) Text() string {
+	texts := make([]string, len(r.Content))
+	for i, c := range r.Content {
+		texts[i] = c.Text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// FileSearchResult converts the result to the type used in file_search tool calls.
+// Attribute values are formatted as strings.
+func (r SearchResult) FileSearchResult() output.FileSearchResult {
+	var attributes map[string]string
+	if r.Attributes != nil {
+		attributes = make(map[string]string, len(r.Attributes))
+		for k, v := range r.Attributes {
+			attributes[k] = fmt.Sprint(v)
+		}
+	}
+	return output.FileSearchResult{
+		FileID:     r.FileID,
+		FileName:   r.Filename,
+		Score:      r.Score,
+		Text:       r.Text(),
+		Attributes: attributes,
+	}
+}
+
+// FileSearchResults converts all results to the type used in file_search tool calls.
+func (r *SearchResults) FileSearchResults() []output.FileSearchResult {
+	results := make([]output.FileSearchResult, len(r.Data))
+	for i, d := range r.Data {
+		results[i] = d.FileSearchResult()
+	}
+	return results
+}
+
+// ValidateAttributes checks attributes of a file against limits of the API.
+func ValidateAttributes(attributes map[string]any) error {
+	if len(attributes) > MaxAttributes {
+		return fmt.Errorf("%d attributes exceed the limit of %d", len(attributes), MaxAttributes)
+	}
+	for k, v := range attributes {
+		if k == "" || len(k) > MaxAttributeKeyLength {
+			return fmt.Errorf("attribute key '%s' must be from 1 to %d characters", k, MaxAttributeKeyLength)
+		}
+		switch v := v.(type) {
+		case string:
+			if len(v) > MaxAttributeValueLength {
+				return fmt.Errorf("value of attribute '%s' exceeds %d characters", k, MaxAttributeValueLength)
+			}
+		case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		default:
+			return fmt.Errorf("value of attribute '%s' has unsupported type %T", k, v)
+		}
+	}
+	return nil
+}